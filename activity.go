@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Активность корпуса по датам изменения файлов
+type CorpusActivity struct {
+	MeanModTime time.Time     `json:"mean_mod_time"`
+	StdDev      time.Duration `json:"stddev_ns"`
+	Newest      string        `json:"newest"`
+	NewestTime  time.Time     `json:"newest_time"`
+	Oldest      string        `json:"oldest"`
+	OldestTime  time.Time     `json:"oldest_time"`
+}
+
+// Расчёт по ModTime всех файлов, каталоги не учитываются.
+// Возвращает nil, если файлов нет
+func computeActivity(results []FileAnalysisResult) *CorpusActivity {
+	var files []FileAnalysisResult
+	for _, res := range results {
+		if !res.IsDir {
+			files = append(files, res)
+		}
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	a := &CorpusActivity{}
+	oldest, newest := files[0], files[0]
+	for _, res := range files[1:] {
+		if res.ModTime.Before(oldest.ModTime) {
+			oldest = res
+		}
+		if res.ModTime.After(newest.ModTime) {
+			newest = res
+		}
+	}
+	a.Oldest, a.OldestTime = oldest.FilePath, oldest.ModTime
+	a.Newest, a.NewestTime = newest.FilePath, newest.ModTime
+
+	// смещения от самого старого файла, чтобы не терять точность на больших UnixNano
+	var sum float64
+	for _, res := range files {
+		sum += float64(res.ModTime.Sub(a.OldestTime))
+	}
+	mean := sum / float64(len(files))
+	var sq float64
+	for _, res := range files {
+		d := float64(res.ModTime.Sub(a.OldestTime)) - mean
+		sq += d * d
+	}
+	a.MeanModTime = a.OldestTime.Add(time.Duration(mean))
+	a.StdDev = time.Duration(math.Sqrt(sq / float64(len(files))))
+	return a
+}
+
+func writeActivityText(out io.Writer, c colorizer, a CorpusActivity) {
+	const layout = "2006-01-02 15:04"
+	fmt.Fprintln(out, "Активность корпуса:")
+	fmt.Fprintf(out, " среднее время изменения: %s, разброс %.1f дн.\n", a.MeanModTime.Format(layout), a.StdDev.Hours()/24)
+	fmt.Fprintf(out, " новейший файл: %s (%s)\n", c.name(a.Newest), a.NewestTime.Format(layout))
+	fmt.Fprintf(out, " старейший файл: %s (%s)\n", c.name(a.Oldest), a.OldestTime.Format(layout))
+	fmt.Fprintln(out)
+}
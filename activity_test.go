@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunActivity(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	days := map[string]int{"old.txt": 0, "mid.txt": 2, "new.txt": 4}
+	for name, d := range days {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("some words here"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mt := base.AddDate(0, 0, d)
+		if err := os.Chtimes(path, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Activity: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	a := report.Summary.Activity
+	if a == nil {
+		t.Fatal("expected activity in summary")
+	}
+	if !a.MeanModTime.Equal(base.AddDate(0, 0, 2)) {
+		t.Errorf("expected mean %v, got %v", base.AddDate(0, 0, 2), a.MeanModTime)
+	}
+	wantStd := time.Duration(math.Sqrt(8.0/3) * float64(24*time.Hour))
+	if diff := a.StdDev - wantStd; diff < -time.Second || diff > time.Second {
+		t.Errorf("expected stddev %v, got %v", wantStd, a.StdDev)
+	}
+	if filepath.Base(a.Newest) != "new.txt" || filepath.Base(a.Oldest) != "old.txt" {
+		t.Errorf("unexpected newest/oldest %s, %s", a.Newest, a.Oldest)
+	}
+
+	out.Reset()
+	opts.Format = "text"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Активность корпуса:") || !strings.Contains(out.String(), "разброс 1.6 дн.") {
+		t.Errorf("expected activity section in text output:\n%s", out.String())
+	}
+}
+
+func TestComputeActivityEmpty(t *testing.T) {
+	if a := computeActivity([]FileAnalysisResult{{FileName: "d", IsDir: true}}); a != nil {
+		t.Errorf("expected nil activity without files, got %+v", a)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Способ свести скалярные результаты анализатора по всем файлам в одно значение
+type Aggregation int
+
+const (
+	AggregateNone Aggregation = iota
+	AggregateSum
+	AggregateMax
+	AggregateMean
+)
+
+var aggregationNames = []string{"none", "sum", "max", "mean"}
+
+func (a Aggregation) String() string {
+	return aggregationNames[a]
+}
+
+func (a Aggregation) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Aggregation) UnmarshalText(b []byte) error {
+	for i, name := range aggregationNames {
+		if string(b) == name {
+			*a = Aggregation(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("неизвестная агрегация %q", b)
+}
+
+// Анализатор со скалярным результатом (int или float64), который попадает в TOTAL.
+// Анализаторы без этого метода в итогах не участвуют
+type AggregatingAnalyzer interface {
+	Analyzer
+	Aggregation() Aggregation
+}
+
+func (w WordCountAnalyzer) Aggregation() Aggregation         { return AggregateSum }
+func (l LineCountAnalyzer) Aggregation() Aggregation         { return AggregateSum }
+func (r ReadabilityAnalyzer) Aggregation() Aggregation       { return AggregateMean }
+func (f FKGradeAnalyzer) Aggregation() Aggregation           { return AggregateMean }
+func (s SentenceDiversityAnalyzer) Aggregation() Aggregation { return AggregateMean }
+
+// Итог анализатора по всем файлам
+type Total struct {
+	Analyzer    string      `json:"analyzer"`
+	Aggregation Aggregation `json:"aggregation"`
+	Value       float64     `json:"value"`
+	Files       int         `json:"files"`
+}
+
+// Накопление итогов в сборщике: анализатор -> итог
+type totalsAggregator map[string]*Total
+
+func newTotalsAggregator(analyzers []Analyzer) totalsAggregator {
+	totals := make(totalsAggregator)
+	for _, a := range analyzers {
+		if agg, ok := a.(AggregatingAnalyzer); ok && agg.Aggregation() != AggregateNone {
+			totals[a.Name()] = &Total{Analyzer: a.Name(), Aggregation: agg.Aggregation()}
+		}
+	}
+	return totals
+}
+
+func (t totalsAggregator) add(r AnalysisResult) {
+	total, ok := t[r.NameAnalyzer]
+	if !ok {
+		return
+	}
+	var v float64
+	switch data := r.Data.(type) {
+	case int:
+		v = float64(data)
+	case float64:
+		v = data
+	default:
+		return
+	}
+	switch total.Aggregation {
+	case AggregateSum, AggregateMean:
+		total.Value += v
+	case AggregateMax:
+		if total.Files == 0 || v > total.Value {
+			total.Value = v
+		}
+	}
+	total.Files++
+}
+
+// Итоги по имени анализатора. Для Mean накопленная сумма делится на число файлов
+func (t totalsAggregator) totals() []Total {
+	var totals []Total
+	for _, total := range t {
+		res := *total
+		if res.Aggregation == AggregateMean && res.Files > 0 {
+			res.Value /= float64(res.Files)
+		}
+		totals = append(totals, res)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Analyzer < totals[j].Analyzer })
+	return totals
+}
+
+// Значение итога анализатора, 0 если его нет
+func totalValue(totals []Total, analyzer string) float64 {
+	for _, t := range totals {
+		if t.Analyzer == analyzer {
+			return t.Value
+		}
+	}
+	return 0
+}
+
+// Строка TOTAL: суммы как есть, для max и mean - с пометкой агрегации
+func formatTotals(totals []Total) string {
+	parts := make([]string, 0, len(totals))
+	for _, t := range totals {
+		switch t.Aggregation {
+		case AggregateSum:
+			parts = append(parts, t.Analyzer+" = "+strconv.FormatFloat(t.Value, 'f', -1, 64))
+		case AggregateMax:
+			parts = append(parts, t.Analyzer+" (max) = "+strconv.FormatFloat(t.Value, 'f', -1, 64))
+		case AggregateMean:
+			parts = append(parts, fmt.Sprintf("%s (mean) = %.2f", t.Analyzer, t.Value))
+		}
+	}
+	return "TOTAL: " + strings.Join(parts, ", ")
+}
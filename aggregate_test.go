@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+// Мок-анализатор с заданным способом агрегации
+type aggregatingMock struct {
+	mockAnalyzer
+	kind Aggregation
+}
+
+func (m aggregatingMock) Aggregation() Aggregation {
+	return m.kind
+}
+
+func TestTotalsAggregator(t *testing.T) {
+	totals := newTotalsAggregator([]Analyzer{
+		aggregatingMock{mockAnalyzer{name: "sum"}, AggregateSum},
+		aggregatingMock{mockAnalyzer{name: "max"}, AggregateMax},
+		aggregatingMock{mockAnalyzer{name: "mean"}, AggregateMean},
+		aggregatingMock{mockAnalyzer{name: "none"}, AggregateNone},
+		mockAnalyzer{name: "plain"},
+	})
+	for _, file := range [][]any{{3, -2, 1.0, 7, 1}, {4, -5, 2.0, 8, 2}, {5, -1, 6.0, 9, 3}} {
+		for i, name := range []string{"sum", "max", "mean", "none", "plain"} {
+			totals.add(AnalysisResult{NameAnalyzer: name, Data: file[i]})
+		}
+	}
+	totals.add(AnalysisResult{NameAnalyzer: "sum", Data: "not a number"})
+
+	want := []Total{
+		{"max", AggregateMax, -1, 3},
+		{"mean", AggregateMean, 3, 3},
+		{"sum", AggregateSum, 12, 3},
+	}
+	if got := totals.totals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := formatTotals(want); got != "TOTAL: max (max) = -1, mean (mean) = 3.00, sum = 12" {
+		t.Errorf("unexpected TOTAL line %q", got)
+	}
+}
+
+func TestRunTotals(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt": "One two. One two.\n",
+		"b.txt": "Alpha beta. Gamma delta.\nEpsilon zeta.\n",
+	})
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", Analyzers: "word_count,line_count,sentence_diversity"}
+	var out bytes.Buffer
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	s := report.Summary
+	if s.TotalWords != 10 || s.TotalLines != 5 {
+		t.Errorf("expected 10 words and 5 lines, got %d and %d", s.TotalWords, s.TotalLines)
+	}
+	if got := totalValue(s.Totals, "sentence_diversity"); math.Abs(got-0.75) > 1e-9 {
+		t.Errorf("expected mean sentence diversity 0.75, got %v", got)
+	}
+
+	out.Reset()
+	opts.Format = "text"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if want := "TOTAL: line_count = 5, sentence_diversity (mean) = 0.75, word_count = 10"; !strings.Contains(out.String(), want) {
+		t.Errorf("expected %q in output:\n%s", want, out.String())
+	}
+}
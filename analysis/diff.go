@@ -0,0 +1,224 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+)
+
+// Параметры сравнения двух запусков
+type DiffOptions struct {
+	// Анализаторы, результаты которых сравниваются; пусто - все
+	Analyzers []string
+	// Изменения числовых метрик и частот не больше Tolerance по модулю не учитываются
+	Tolerance float64
+	// Минимальное изменение метрики в процентах, рост с нуля считается изменением на 100%
+	ThresholdPercent float64
+	// Ключ сопоставления файлов двух запусков, nil - FilePath
+	Key func(FileAnalysisResult) string
+}
+
+// Изменение числовой метрики файла
+type MetricDelta struct {
+	Analyzer string  `json:"analyzer"`
+	Old      float64 `json:"old"`
+	New      float64 `json:"new"`
+	Delta    float64 `json:"delta"`
+	Percent  float64 `json:"percent"`
+}
+
+// Изменение частоты слова в результате-словаре (most_frequent_words, terms, ...)
+type FrequencyDelta struct {
+	Analyzer string `json:"analyzer"`
+	Word     string `json:"word"`
+	Old      int    `json:"old"`
+	New      int    `json:"new"`
+}
+
+// Изменения файла, который есть в обоих запусках
+type FileDiff struct {
+	File             string           `json:"file"`
+	Metrics          []MetricDelta    `json:"metrics,omitempty"`
+	Frequencies      []FrequencyDelta `json:"frequencies,omitempty"`
+	AddedAnalyzers   []string         `json:"added_analyzers,omitempty"`
+	RemovedAnalyzers []string         `json:"removed_analyzers,omitempty"`
+}
+
+// Разница между запусками, все списки отсортированы
+type DiffReport struct {
+	Added   []string   `json:"added,omitempty"`
+	Removed []string   `json:"removed,omitempty"`
+	Changed []FileDiff `json:"changed,omitempty"`
+}
+
+// Нет ни добавленных, ни удалённых, ни изменённых файлов
+func (d DiffReport) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Сравнение результатов двух запусков
+func Diff(before, after []FileAnalysisResult, opts DiffOptions) DiffReport {
+	key := opts.Key
+	if key == nil {
+		key = func(r FileAnalysisResult) string { return r.FilePath }
+	}
+	var wanted map[string]bool
+	if len(opts.Analyzers) > 0 {
+		wanted = make(map[string]bool, len(opts.Analyzers))
+		for _, name := range opts.Analyzers {
+			wanted[name] = true
+		}
+	}
+
+	var report DiffReport
+	old := make(map[string]FileAnalysisResult, len(before))
+	for _, res := range before {
+		old[key(res)] = res
+	}
+	seen := make(map[string]bool, len(after))
+	for _, res := range after {
+		k := key(res)
+		seen[k] = true
+		prev, ok := old[k]
+		if !ok {
+			report.Added = append(report.Added, k)
+			continue
+		}
+		if fd := diffFile(k, prev, res, wanted, opts); fd != nil {
+			report.Changed = append(report.Changed, *fd)
+		}
+	}
+	for k := range old {
+		if !seen[k] {
+			report.Removed = append(report.Removed, k)
+		}
+	}
+
+	sort.Strings(report.Added)
+	sort.Strings(report.Removed)
+	sort.Slice(report.Changed, func(i, j int) bool { return report.Changed[i].File < report.Changed[j].File })
+	return report
+}
+
+// Результаты файла по имени анализатора с учётом фильтра wanted
+func resultsByName(res FileAnalysisResult, wanted map[string]bool) map[string]any {
+	m := make(map[string]any, len(res.Results))
+	for _, r := range res.Results {
+		if wanted == nil || wanted[r.NameAnalyzer] {
+			data := r.Data
+			if lazy, ok := data.(Resolver); ok {
+				data = lazy.Result().Data
+			}
+			m[r.NameAnalyzer] = data
+		}
+	}
+	return m
+}
+
+func diffFile(file string, before, after FileAnalysisResult, wanted map[string]bool, opts DiffOptions) *FileDiff {
+	fd := FileDiff{File: file}
+	old, cur := resultsByName(before, wanted), resultsByName(after, wanted)
+	for name, data := range cur {
+		prev, ok := old[name]
+		if !ok {
+			fd.AddedAnalyzers = append(fd.AddedAnalyzers, name)
+			continue
+		}
+		if o, ok := number(prev); ok {
+			if n, ok := number(data); ok {
+				if d, changed := metricDelta(name, o, n, opts); changed {
+					fd.Metrics = append(fd.Metrics, d)
+				}
+			}
+			continue
+		}
+		if o, ok := frequencies(prev); ok {
+			if n, ok := frequencies(data); ok {
+				fd.Frequencies = append(fd.Frequencies, frequencyDeltas(name, o, n, opts.Tolerance)...)
+			}
+		}
+	}
+	for name := range old {
+		if _, ok := cur[name]; !ok {
+			fd.RemovedAnalyzers = append(fd.RemovedAnalyzers, name)
+		}
+	}
+	if len(fd.Metrics) == 0 && len(fd.Frequencies) == 0 && len(fd.AddedAnalyzers) == 0 && len(fd.RemovedAnalyzers) == 0 {
+		return nil
+	}
+	sort.Slice(fd.Metrics, func(i, j int) bool { return fd.Metrics[i].Analyzer < fd.Metrics[j].Analyzer })
+	sort.Slice(fd.Frequencies, func(i, j int) bool {
+		a, b := fd.Frequencies[i], fd.Frequencies[j]
+		if a.Analyzer != b.Analyzer {
+			return a.Analyzer < b.Analyzer
+		}
+		return a.Word < b.Word
+	})
+	sort.Strings(fd.AddedAnalyzers)
+	sort.Strings(fd.RemovedAnalyzers)
+	return &fd
+}
+
+func metricDelta(name string, o, n float64, opts DiffOptions) (MetricDelta, bool) {
+	delta := n - o
+	if math.Abs(delta) <= opts.Tolerance {
+		return MetricDelta{}, false
+	}
+	percent := 100.0
+	if o != 0 {
+		percent = delta / math.Abs(o) * 100
+	}
+	if math.Abs(percent) <= opts.ThresholdPercent {
+		return MetricDelta{}, false
+	}
+	return MetricDelta{Analyzer: name, Old: o, New: n, Delta: delta, Percent: percent}, true
+}
+
+func frequencyDeltas(name string, old, cur map[string]int, tolerance float64) []FrequencyDelta {
+	var deltas []FrequencyDelta
+	add := func(word string, o, n int) {
+		if math.Abs(float64(n-o)) > tolerance {
+			deltas = append(deltas, FrequencyDelta{name, word, o, n})
+		}
+	}
+	for w, n := range cur {
+		add(w, old[w], n)
+	}
+	for w, o := range old {
+		if _, ok := cur[w]; !ok {
+			add(w, o, 0)
+		}
+	}
+	return deltas
+}
+
+// Числовое значение результата; в отчётах без поля type числа читаются как float64
+func number(data any) (float64, bool) {
+	switch v := data.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// Словарь частот, в том числе прочитанный из JSON без поля type
+func frequencies(data any) (map[string]int, bool) {
+	switch v := data.(type) {
+	case map[string]int:
+		return v, true
+	case map[string]any:
+		freq := make(map[string]int, len(v))
+		for w, c := range v {
+			n, ok := c.(float64)
+			if !ok {
+				return nil, false
+			}
+			freq[w] = int(n)
+		}
+		return freq, true
+	}
+	return nil, false
+}
@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func file(path string, results ...AnalysisResult) FileAnalysisResult {
+	return FileAnalysisResult{FileName: path, FilePath: "dir/" + path, Results: results}
+}
+
+func result(name string, data any) AnalysisResult {
+	return AnalysisResult{NameAnalyzer: name, Data: data, Confidence: 1}
+}
+
+func TestDiffAddedRemoved(t *testing.T) {
+	before := []FileAnalysisResult{file("a.txt"), file("gone.txt")}
+	after := []FileAnalysisResult{file("new.txt"), file("a.txt")}
+	got := Diff(before, after, DiffOptions{})
+	want := DiffReport{Added: []string{"dir/new.txt"}, Removed: []string{"dir/gone.txt"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if Diff(before, before, DiffOptions{}).Empty() != true {
+		t.Error("expected an empty diff for identical runs")
+	}
+}
+
+func TestDiffMetricsThresholdAndTolerance(t *testing.T) {
+	before := []FileAnalysisResult{
+		file("a.txt", result("word_count", 100), result("line_count", 10), result("readability", 60.0)),
+		file("b.txt", result("word_count", 0)),
+	}
+	after := []FileAnalysisResult{
+		file("a.txt", result("word_count", 105), result("line_count", 20), result("readability", 60.4)),
+		file("b.txt", result("word_count", 3)),
+	}
+	got := Diff(before, after, DiffOptions{ThresholdPercent: 10, Tolerance: 0.5})
+	want := []FileDiff{
+		{File: "dir/a.txt", Metrics: []MetricDelta{{"line_count", 10, 20, 10, 100}}},
+		{File: "dir/b.txt", Metrics: []MetricDelta{{"word_count", 0, 3, 3, 100}}},
+	}
+	if !reflect.DeepEqual(got.Changed, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Changed)
+	}
+
+	// без порогов учитывается любое изменение, включая дробное
+	got = Diff(before[:1], after[:1], DiffOptions{})
+	if len(got.Changed) != 1 || len(got.Changed[0].Metrics) != 3 {
+		t.Fatalf("expected 3 changed metrics, got %+v", got.Changed)
+	}
+	if m := got.Changed[0].Metrics[1]; m.Analyzer != "readability" || m.Delta < 0.39 || m.Delta > 0.41 {
+		t.Errorf("unexpected readability delta %+v", m)
+	}
+}
+
+func TestDiffAnalyzerFilterAndKey(t *testing.T) {
+	before := []FileAnalysisResult{file("a.txt", result("word_count", 1), result("line_count", 1))}
+	after := []FileAnalysisResult{file("a.txt", result("word_count", 2), result("line_count", 2))}
+	after[0].FilePath = "moved/a.txt"
+	got := Diff(before, after, DiffOptions{
+		Analyzers: []string{"line_count"},
+		Key:       func(r FileAnalysisResult) string { return r.FileName },
+	})
+	want := []FileDiff{{File: "a.txt", Metrics: []MetricDelta{{"line_count", 1, 2, 1, 100}}}}
+	if !reflect.DeepEqual(got.Changed, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Changed)
+	}
+}
+
+func TestDiffFrequencies(t *testing.T) {
+	before := []FileAnalysisResult{file("a.txt", result("most_frequent_words", map[string]int{"go": 3, "old": 1, "same": 2}))}
+	after := []FileAnalysisResult{file("a.txt", result("most_frequent_words", map[string]int{"go": 5, "new": 1, "same": 2}))}
+	got := Diff(before, after, DiffOptions{})
+	want := []FrequencyDelta{
+		{"most_frequent_words", "go", 3, 5},
+		{"most_frequent_words", "new", 0, 1},
+		{"most_frequent_words", "old", 1, 0},
+	}
+	if len(got.Changed) != 1 || !reflect.DeepEqual(got.Changed[0].Frequencies, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Changed)
+	}
+	got = Diff(before, after, DiffOptions{Tolerance: 1})
+	if len(got.Changed) != 1 || len(got.Changed[0].Frequencies) != 1 {
+		t.Errorf("expected only the go delta above tolerance, got %+v", got.Changed)
+	}
+}
+
+func TestDiffAnalyzerSetChanged(t *testing.T) {
+	before := []FileAnalysisResult{file("a.txt", result("word_count", 4), result("license", "MIT"))}
+	after := []FileAnalysisResult{file("a.txt", result("word_count", 4), result("summary", "Hi."), result("geo", map[string]int{}))}
+	got := Diff(before, after, DiffOptions{})
+	want := []FileDiff{{
+		File:             "dir/a.txt",
+		AddedAnalyzers:   []string{"geo", "summary"},
+		RemovedAnalyzers: []string{"license"},
+	}}
+	if !reflect.DeepEqual(got.Changed, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Changed)
+	}
+}
+
+func TestDiffUntypedJSON(t *testing.T) {
+	// старые отчёты без поля type: числа и словари читаются как float64 и map[string]any
+	var before []FileAnalysisResult
+	data := `[{"file_name":"a.txt","file_path":"dir/a.txt","results":[
+		{"name":"word_count","data":10,"confidence":1},
+		{"name":"most_frequent_words","data":{"go":2},"confidence":1}]}]`
+	if err := json.Unmarshal([]byte(data), &before); err != nil {
+		t.Fatal(err)
+	}
+	after := []FileAnalysisResult{file("a.txt", result("word_count", 12), result("most_frequent_words", map[string]int{"go": 2}))}
+	got := Diff(before, after, DiffOptions{})
+	want := []FileDiff{{File: "dir/a.txt", Metrics: []MetricDelta{{"word_count", 10, 12, 2, 20}}}}
+	if !reflect.DeepEqual(got.Changed, want) {
+		t.Errorf("expected %+v, got %+v", want, got.Changed)
+	}
+}
+
+type lazy struct{ data any }
+
+func (l lazy) Result() AnalysisResult {
+	return AnalysisResult{NameAnalyzer: "word_count", Data: l.data}
+}
+
+func TestDiffResolvesLazyData(t *testing.T) {
+	before := []FileAnalysisResult{file("a.txt", result("word_count", lazy{1}))}
+	after := []FileAnalysisResult{file("a.txt", result("word_count", 2))}
+	got := Diff(before, after, DiffOptions{})
+	if len(got.Changed) != 1 || len(got.Changed[0].Metrics) != 1 {
+		t.Errorf("expected lazy data to be compared, got %+v", got.Changed)
+	}
+}
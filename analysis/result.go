@@ -0,0 +1,126 @@
+// Пакет analysis - результаты анализаторов и сравнение запусков.
+// Типы доступны для импорта из сервисов, которые читают JSON отчёты
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Confidence - уверенность анализатора от 0.0 до 1.0.
+// Нулевое значение означает детерминированный результат и заменяется на 1.0.
+type AnalysisResult struct {
+	NameAnalyzer string  `json:"name"`
+	Data         any     `json:"data"`
+	Confidence   float64 `json:"confidence"`
+}
+
+// Структура, содержащая результаты работы всех анализаторов для файла
+type FileAnalysisResult struct {
+	FileName    string           `json:"file_name"`
+	FilePath    string           `json:"file_path"`
+	Size        int64            `json:"size"`
+	ModTime     time.Time        `json:"mod_time"`
+	ContentHash string           `json:"content_hash"`
+	IsDir       bool             `json:"is_dir,omitempty"`
+	Results     []AnalysisResult `json:"results"`
+}
+
+// Данные анализатора name, ok == false если его нет в результатах
+func (r FileAnalysisResult) Result(name string) (any, bool) {
+	for _, res := range r.Results {
+		if res.NameAnalyzer == name {
+			return res.Data, true
+		}
+	}
+	return nil, false
+}
+
+// Типы данных анализаторов, восстанавливаемые при чтении JSON.
+// Ключ - метка из поля "type": стабильное имя, не зависящее от пакета, где объявлен тип
+var (
+	resultTypes = make(map[string]reflect.Type)
+	resultTags  = make(map[reflect.Type]string)
+)
+
+// Регистрация типа данных анализатора по образцу значения под меткой tag.
+// Имя типа из reflect ("main.DensityStats") тоже читается: так его записывали
+// прежние версии отчётов
+func RegisterResultType(tag string, sample any) {
+	t := reflect.TypeOf(sample)
+	resultTypes[tag] = t
+	resultTypes[t.String()] = t
+	resultTags[t] = tag
+}
+
+func init() {
+	// у безымянных типов имя из reflect не зависит от пакета и служит меткой
+	for _, sample := range []any{
+		0,
+		0.0,
+		"",
+		false,
+		[]string(nil),
+		[]int(nil),
+		map[string]int(nil),
+		map[string][]int(nil),
+	} {
+		RegisterResultType(reflect.TypeOf(sample).String(), sample)
+	}
+}
+
+// Отложенные данные результата, при кодировании в JSON вычисляются через Result
+type Resolver interface {
+	Result() AnalysisResult
+}
+
+// Представление AnalysisResult в JSON с именем типа данных
+type analysisResultJSON struct {
+	NameAnalyzer string          `json:"name"`
+	Data         json.RawMessage `json:"data"`
+	Type         string          `json:"type,omitempty"`
+	Confidence   float64         `json:"confidence"`
+}
+
+// Тип пишется только для зарегистрированных типов. Данные без метки или
+// с неизвестной меткой (тип из другой программы) читаются как обычный JSON
+// (float64, map[string]any)
+func (r AnalysisResult) MarshalJSON() ([]byte, error) {
+	data := r.Data
+	if lazy, ok := data.(Resolver); ok {
+		data = lazy.Result().Data
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	out := analysisResultJSON{NameAnalyzer: r.NameAnalyzer, Data: raw, Confidence: r.Confidence}
+	if data != nil {
+		out.Type = resultTags[reflect.TypeOf(data)]
+	}
+	return json.Marshal(out)
+}
+
+func (r *AnalysisResult) UnmarshalJSON(b []byte) error {
+	var in analysisResultJSON
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	r.NameAnalyzer, r.Confidence = in.NameAnalyzer, in.Confidence
+	r.Data = nil
+	if len(in.Data) == 0 {
+		return nil
+	}
+	t, ok := resultTypes[in.Type]
+	if !ok {
+		return json.Unmarshal(in.Data, &r.Data)
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(in.Data, v.Interface()); err != nil {
+		return fmt.Errorf("данные анализатора %s: %w", in.NameAnalyzer, err)
+	}
+	r.Data = v.Elem().Interface()
+	return nil
+}
@@ -0,0 +1,24 @@
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Отчёт CLI читается и без регистрации его типов: данные с неизвестной меткой - обычный JSON
+func TestUnmarshalForeignTags(t *testing.T) {
+	report := `{"file_name":"a.txt","results":[
+		{"name":"word_count","data":3,"type":"int","confidence":1},
+		{"name":"density","data":{"max_words_per_line":4},"type":"density","confidence":1},
+		{"name":"quotes","data":{"count":1},"type":"main.QuoteStats","confidence":1}]}`
+	var res FileAnalysisResult
+	if err := json.Unmarshal([]byte(report), &res); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := res.Results[0].Data.(int); !ok || n != 3 {
+		t.Errorf("expected int 3, got %#v", res.Results[0].Data)
+	}
+	if d, ok := res.Results[1].Data.(map[string]any); !ok || d["max_words_per_line"] != 4.0 {
+		t.Errorf("expected plain JSON object, got %#v", res.Results[1].Data)
+	}
+}
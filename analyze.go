@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"path/filepath"
 	"sync"
 )
@@ -10,26 +11,46 @@ func AnalyzeSequential(files []string, analyzers []Analyzer) ([]FileAnalysisResu
 	var results []FileAnalysisResult
 
 	for _, path := range files {
-		content, size, err := readFileContent(path)
+		fc, err := readFile(path)
 		if err != nil {
 			continue
 		}
 
 		var analysisResults []AnalysisResult
 		for _, analyzer := range analyzers {
-			analysisResults = append(analysisResults, analyzer.Analyze(content))
+			analysisResults = append(analysisResults, runAnalyzer(analyzer, fc.Text))
 		}
 
 		results = append(results, FileAnalysisResult{
-			FileName: filepath.Base(path),
-			Size:     size,
-			Results:  analysisResults,
+			FileName:    NormalizePath(filepath.Base(path)),
+			FilePath:    NormalizePath(path),
+			Size:        fc.Info.Size(),
+			ModTime:     fc.Info.ModTime(),
+			ContentHash: fc.Hash,
+			Results:     analysisResults,
 		})
 	}
 	return results, nil
 }
 
 func AnalyzeParallel(files []string, analyzers []Analyzer, workers int) ([]FileAnalysisResult, error) {
+	return AnalyzeMode(files, analyzers, workers, ParallelBoth)
+}
+
+// То же, что AnalyzeParallel, но каждый результат передаётся в fn сразу после обработки файла.
+// fn вызывается из одной горутины
+func AnalyzeParallelFunc(files []string, analyzers []Analyzer, workers int, fn func(FileAnalysisResult)) {
+	analyzeFilesFunc(context.Background(), files, analyzers, workers, analyzeContent, fn)
+}
+
+// То же, что AnalyzeParallelFunc, но после отмены ctx новые файлы не берутся в работу.
+// Возвращает ctx.Err(), если анализ прерван
+func AnalyzeParallelContext(ctx context.Context, files []string, analyzers []Analyzer, workers int, fn func(FileAnalysisResult)) error {
+	analyzeFilesFunc(ctx, files, analyzers, workers, analyzeContent, fn)
+	return ctx.Err()
+}
+
+func analyzeFilesFunc(ctx context.Context, files []string, analyzers []Analyzer, workers int, analyze func(string, []Analyzer) []AnalysisResult, fn func(FileAnalysisResult)) {
 	filePaths := make(chan string)
 	results := make(chan FileAnalysisResult)
 
@@ -39,45 +60,46 @@ func AnalyzeParallel(files []string, analyzers []Analyzer, workers int) ([]FileA
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			analyzers := cloneAnalyzers(analyzers)
 			for path := range filePaths {
-				content, size, err := readFileContent(path)
-				if err != nil {
-					continue
-				}
-
-				var swg sync.WaitGroup
-				analysisResults := make([]AnalysisResult, len(analyzers))
-
-				for i, analyzer := range analyzers {
-					swg.Add(1)
-					go func(i int, a Analyzer) {
-						defer swg.Done()
-						analysisResults[i] = a.Analyze(content)
-					}(i, analyzer)
-				}
-				swg.Wait()
-
-				results <- FileAnalysisResult{
-					FileName: filepath.Base(path),
-					Size:     size,
-					Results:  analysisResults,
+				if r, ok := analyzePath(path, analyzers, analyze); ok {
+					results <- r
 				}
 			}
 		}()
 	}
 
 	go func() {
+	feed:
 		for _, f := range files {
-			filePaths <- f
+			select {
+			case filePaths <- f:
+			case <-ctx.Done():
+				break feed
+			}
 		}
 		close(filePaths)
 		wg.Wait()
 		close(results)
 	}()
 
-	var out []FileAnalysisResult
 	for r := range results {
-		out = append(out, r)
+		fn(r)
+	}
+}
+
+// Анализ одного файла для воркеров; нечитаемые файлы пропускаются
+func analyzePath(path string, analyzers []Analyzer, analyze func(string, []Analyzer) []AnalysisResult) (FileAnalysisResult, bool) {
+	fc, err := readFile(path)
+	if err != nil {
+		return FileAnalysisResult{}, false
 	}
-	return out, nil
+	return FileAnalysisResult{
+		FileName:    NormalizePath(filepath.Base(path)),
+		FilePath:    NormalizePath(path),
+		Size:        fc.Info.Size(),
+		ModTime:     fc.Info.ModTime(),
+		ContentHash: fc.Hash,
+		Results:     analyze(fc.Text, analyzers),
+	}, true
 }
@@ -1,31 +1,17 @@
 package main
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
+	"stage5/internal/testutil"
 	"strings"
 	"testing"
 )
 
 // тесты и бенчмарки
-func createTempFile(t testing.TB, content string) string {
-	t.Helper()
-
-	f, err := os.CreateTemp("", "*.txt") //создаём файл в системной временной директории
-	if err != nil {
-		t.Fatal(err)
-	}
-	_, err = f.WriteString(content) //проверяем записалось или нет
-	if err != nil {
-		t.Fatal(err)
-	}
-	f.Close()
-
-	return f.Name() //полный путь к файлу
-}
-
 func TestAnalyzeSequential(t *testing.T) {
-	file := createTempFile(t, "hello world\nhello go")
-	defer os.Remove(file)
+	file := testutil.CreateTempFile(t, "hello world\nhello go")
 
 	analyzers := []Analyzer{
 		WordCountAnalyzer{},
@@ -41,39 +27,43 @@ func TestAnalyzeSequential(t *testing.T) {
 		t.Fatalf("expected 1 result, got %d", len(results))
 	}
 
-	for _, res := range results[0].Results {
-		switch res.NameAnalyzer {
-		case "word_count":
-			if res.Data.(int) != 4 {
-				t.Errorf("expected 4 words")
-			}
-		case "line_count":
-			if res.Data.(int) != 2 {
-				t.Errorf("expected 2 lines")
-			}
+	testutil.AssertAnalyzerResult(t, results[0], "word_count", 4)
+	testutil.AssertAnalyzerResult(t, results[0], "line_count", 2)
+}
+
+func TestWordCountMinWordLength(t *testing.T) {
+	content := "I saw a cat, это ёж и\nsunflowers bloom"
+	tests := []struct {
+		min  int
+		want int
+	}{
+		{0, 9},
+		{1, 9},
+		{2, 6},
+		{5, 2},
+	}
+	for _, tt := range tests {
+		res := WordCountAnalyzer{MinWordLength: tt.min}.Analyze(content)
+		if res.Data.(int) != tt.want {
+			t.Errorf("MinWordLength=%d: expected %d words, got %v", tt.min, tt.want, res.Data)
 		}
 	}
 }
 
-func benchmarkFiles(b *testing.B, count int, content string) []string {
-	b.Helper()
+func TestMinWordLengthKeepsLineCount(t *testing.T) {
+	file := testutil.CreateTempFile(t, "a b cc\nd eee\nf")
 
-	var files []string
-	for i := 0; i < count; i++ {
-		f := createTempFile(b, content)
-		files = append(files, f)
+	analyzers := defaultAnalyzers(Options{MinWordLength: 2})
+	results, err := AnalyzeSequential([]string{file}, analyzers)
+	if err != nil {
+		t.Fatal(err)
 	}
-
-	b.Cleanup(func() { //для удаления временных файлов
-		for _, f := range files {
-			os.Remove(f)
-		}
-	})
-	return files
+	testutil.AssertAnalyzerResult(t, results[0], "word_count", 2)
+	testutil.AssertAnalyzerResult(t, results[0], "line_count", 3)
 }
 
 func BenchmarkSequential(b *testing.B) {
-	files := benchmarkFiles(
+	files := testutil.BenchmarkFiles(
 		b,
 		50,
 		strings.Repeat("hello world\n", 1000),
@@ -92,7 +82,7 @@ func BenchmarkSequential(b *testing.B) {
 }
 
 func BenchmarkParallel(b *testing.B) {
-	files := benchmarkFiles(
+	files := testutil.BenchmarkFiles(
 		b,
 		50,
 		strings.Repeat("hello world\n", 1000),
@@ -109,3 +99,73 @@ func BenchmarkParallel(b *testing.B) {
 		AnalyzeParallel(files, analyzers, 8)
 	}
 }
+
+// Четыре анализатора, читающих токены: с общими артефактами и без них
+func BenchmarkParallelArtifacts(b *testing.B) {
+	files := testutil.BenchmarkFiles(
+		b,
+		50,
+		strings.Repeat("hello world HTTP API\n", 1000),
+	)
+
+	for _, bc := range []struct {
+		name      string
+		analyzers []Analyzer
+	}{
+		{"shared", tokenAnalyzers()},
+		{"plain", withoutArtifacts(tokenAnalyzers())},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				AnalyzeParallel(files, bc.analyzers, 8)
+			}
+		})
+	}
+}
+
+// Базовая линия CPU для BenchmarkSequential: то же содержимое и те же
+// анализаторы, но строки уже в памяти и файлы не читаются.
+// Отношение времени к BenchmarkSequential - доля анализа, остаток приходится на I/O (см. PERFORMANCE.md)
+func BenchmarkFibonacciStringProcess(b *testing.B) {
+	contents := make([]string, 50)
+	for i := range contents {
+		contents[i] = strings.Repeat("hello world\n", 1000)
+	}
+
+	analyzers := []Analyzer{
+		WordCountAnalyzer{},
+		LineCountAnalyzer{},
+		MostFrequentWordsAnalyzer{},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, content := range contents {
+			for _, a := range analyzers {
+				runAnalyzer(a, content)
+			}
+		}
+	}
+}
+
+// После отмены в работу не берутся новые файлы
+func TestAnalyzeParallelContextCancelled(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		files[fmt.Sprintf("f%03d.txt", i)] = "hello world"
+	}
+	paths, _, err := dirTraversal(testutil.CreateTempDir(t, files), ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	n := 0
+	err = AnalyzeParallelContext(ctx, paths, []Analyzer{WordCountAnalyzer{}}, 2, func(FileAnalysisResult) {
+		n++
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) || n >= len(paths) {
+		t.Errorf("expected cancellation after a few of %d files, got %d results and %v", len(paths), n, err)
+	}
+}
@@ -0,0 +1,311 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: api/textanalyzer.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AnalyzeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Ext           string                 `protobuf:"bytes,2,opt,name=ext,proto3" json:"ext,omitempty"`
+	Workers       int32                  `protobuf:"varint,3,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeRequest) Reset() {
+	*x = AnalyzeRequest{}
+	mi := &file_api_textanalyzer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeRequest) ProtoMessage() {}
+
+func (x *AnalyzeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_textanalyzer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeRequest) Descriptor() ([]byte, []int) {
+	return file_api_textanalyzer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AnalyzeRequest) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetExt() string {
+	if x != nil {
+		return x.Ext
+	}
+	return ""
+}
+
+func (x *AnalyzeRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+type AnalysisResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Data          *structpb.Value        `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Confidence    float64                `protobuf:"fixed64,3,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisResult) Reset() {
+	*x = AnalysisResult{}
+	mi := &file_api_textanalyzer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisResult) ProtoMessage() {}
+
+func (x *AnalysisResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_textanalyzer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisResult.ProtoReflect.Descriptor instead.
+func (*AnalysisResult) Descriptor() ([]byte, []int) {
+	return file_api_textanalyzer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnalysisResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AnalysisResult) GetData() *structpb.Value {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *AnalysisResult) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+type FileAnalysisResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileName      string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FilePath      string                 `protobuf:"bytes,2,opt,name=file_path,json=filePath,proto3" json:"file_path,omitempty"`
+	Size          int64                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	ModTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=mod_time,json=modTime,proto3" json:"mod_time,omitempty"`
+	ContentHash   string                 `protobuf:"bytes,5,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	Results       []*AnalysisResult      `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileAnalysisResponse) Reset() {
+	*x = FileAnalysisResponse{}
+	mi := &file_api_textanalyzer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileAnalysisResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileAnalysisResponse) ProtoMessage() {}
+
+func (x *FileAnalysisResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_textanalyzer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileAnalysisResponse.ProtoReflect.Descriptor instead.
+func (*FileAnalysisResponse) Descriptor() ([]byte, []int) {
+	return file_api_textanalyzer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileAnalysisResponse) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *FileAnalysisResponse) GetFilePath() string {
+	if x != nil {
+		return x.FilePath
+	}
+	return ""
+}
+
+func (x *FileAnalysisResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *FileAnalysisResponse) GetModTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ModTime
+	}
+	return nil
+}
+
+func (x *FileAnalysisResponse) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *FileAnalysisResponse) GetResults() []*AnalysisResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_api_textanalyzer_proto protoreflect.FileDescriptor
+
+const file_api_textanalyzer_proto_rawDesc = "" +
+	"\n" +
+	"\x16api/textanalyzer.proto\x12\ftextanalyzer\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"P\n" +
+	"\x0eAnalyzeRequest\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x10\n" +
+	"\x03ext\x18\x02 \x01(\tR\x03ext\x12\x18\n" +
+	"\aworkers\x18\x03 \x01(\x05R\aworkers\"p\n" +
+	"\x0eAnalysisResult\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12*\n" +
+	"\x04data\x18\x02 \x01(\v2\x16.google.protobuf.ValueR\x04data\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x03 \x01(\x01R\n" +
+	"confidence\"\xf6\x01\n" +
+	"\x14FileAnalysisResponse\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfile_path\x18\x02 \x01(\tR\bfilePath\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\x125\n" +
+	"\bmod_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\amodTime\x12!\n" +
+	"\fcontent_hash\x18\x05 \x01(\tR\vcontentHash\x126\n" +
+	"\aresults\x18\x06 \x03(\v2\x1c.textanalyzer.AnalysisResultR\aresults2f\n" +
+	"\fTextAnalyzer\x12V\n" +
+	"\x10AnalyzeDirectory\x12\x1c.textanalyzer.AnalyzeRequest\x1a\".textanalyzer.FileAnalysisResponse0\x01B\fZ\n" +
+	"stage5/apib\x06proto3"
+
+var (
+	file_api_textanalyzer_proto_rawDescOnce sync.Once
+	file_api_textanalyzer_proto_rawDescData []byte
+)
+
+func file_api_textanalyzer_proto_rawDescGZIP() []byte {
+	file_api_textanalyzer_proto_rawDescOnce.Do(func() {
+		file_api_textanalyzer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_textanalyzer_proto_rawDesc), len(file_api_textanalyzer_proto_rawDesc)))
+	})
+	return file_api_textanalyzer_proto_rawDescData
+}
+
+var file_api_textanalyzer_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_api_textanalyzer_proto_goTypes = []any{
+	(*AnalyzeRequest)(nil),        // 0: textanalyzer.AnalyzeRequest
+	(*AnalysisResult)(nil),        // 1: textanalyzer.AnalysisResult
+	(*FileAnalysisResponse)(nil),  // 2: textanalyzer.FileAnalysisResponse
+	(*structpb.Value)(nil),        // 3: google.protobuf.Value
+	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+}
+var file_api_textanalyzer_proto_depIdxs = []int32{
+	3, // 0: textanalyzer.AnalysisResult.data:type_name -> google.protobuf.Value
+	4, // 1: textanalyzer.FileAnalysisResponse.mod_time:type_name -> google.protobuf.Timestamp
+	1, // 2: textanalyzer.FileAnalysisResponse.results:type_name -> textanalyzer.AnalysisResult
+	0, // 3: textanalyzer.TextAnalyzer.AnalyzeDirectory:input_type -> textanalyzer.AnalyzeRequest
+	2, // 4: textanalyzer.TextAnalyzer.AnalyzeDirectory:output_type -> textanalyzer.FileAnalysisResponse
+	4, // [4:5] is the sub-list for method output_type
+	3, // [3:4] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_api_textanalyzer_proto_init() }
+func file_api_textanalyzer_proto_init() {
+	if File_api_textanalyzer_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_textanalyzer_proto_rawDesc), len(file_api_textanalyzer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_textanalyzer_proto_goTypes,
+		DependencyIndexes: file_api_textanalyzer_proto_depIdxs,
+		MessageInfos:      file_api_textanalyzer_proto_msgTypes,
+	}.Build()
+	File_api_textanalyzer_proto = out.File
+	file_api_textanalyzer_proto_goTypes = nil
+	file_api_textanalyzer_proto_depIdxs = nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import "strings"
+
+// Общие промежуточные данные файла, которые нужны нескольким анализаторам
+const (
+	ArtifactTokens     = "tokens"     // strings.Fields(content)
+	ArtifactLines      = "lines"      // strings.Split(content, "\n")
+	ArtifactLowercased = "lowercased" // токены в нижнем регистре
+)
+
+// Артефакты одного файла, заполнены только запрошенные поля.
+// Один экземпляр читают анализаторы из разных горутин, изменять его нельзя
+type Artifacts struct {
+	Tokens     []string
+	Lines      []string
+	Lowercased []string
+}
+
+// Анализатор, которому воркер может передать готовые артефакты.
+// AnalyzeWithArtifacts должен возвращать тот же результат, что Analyze
+type ArtifactAnalyzer interface {
+	Analyzer
+	Artifacts() []string
+	AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult
+}
+
+// Вычисление каждого запрошенного артефакта один раз, nil если ничего не запрошено
+func buildArtifacts(content string, analyzers []Analyzer) *Artifacts {
+	need := make(map[string]bool)
+	for _, a := range analyzers {
+		if aa, ok := a.(ArtifactAnalyzer); ok {
+			for _, name := range aa.Artifacts() {
+				need[name] = true
+			}
+		}
+	}
+	if len(need) == 0 {
+		return nil
+	}
+
+	art := &Artifacts{}
+	if need[ArtifactTokens] || need[ArtifactLowercased] {
+		art.Tokens = strings.Fields(content)
+	}
+	if need[ArtifactLowercased] {
+		art.Lowercased = make([]string, len(art.Tokens))
+		for i, w := range art.Tokens {
+			art.Lowercased[i] = strings.ToLower(w)
+		}
+	}
+	if need[ArtifactLines] {
+		art.Lines = strings.Split(content, "\n")
+	}
+	return art
+}
+
+// Запуск анализатора с артефактами, если он их поддерживает
+func runAnalyzerWith(a Analyzer, content string, art *Artifacts) AnalysisResult {
+	aa, ok := a.(ArtifactAnalyzer)
+	if !ok || art == nil {
+		return runAnalyzer(a, content)
+	}
+	return withDefaultConfidence(a, aa.AnalyzeWithArtifacts(content, art))
+}
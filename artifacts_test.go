@@ -0,0 +1,61 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Обёртка, скрывающая AnalyzeWithArtifacts: анализатор сам разбирает текст
+type plainAnalyzer struct {
+	a Analyzer
+}
+
+func (p plainAnalyzer) Name() string {
+	return p.a.Name()
+}
+func (p plainAnalyzer) Analyze(content string) AnalysisResult {
+	return p.a.Analyze(content)
+}
+
+func withoutArtifacts(analyzers []Analyzer) []Analyzer {
+	plain := make([]Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		plain[i] = plainAnalyzer{a}
+	}
+	return plain
+}
+
+// Анализаторы, читающие токены
+func tokenAnalyzers() []Analyzer {
+	return []Analyzer{
+		WordCountAnalyzer{},
+		MostFrequentWordsAnalyzer{},
+		TermExtractorAnalyzer{},
+		FilteredFreqAnalyzer{},
+	}
+}
+
+func TestArtifactsSameResults(t *testing.T) {
+	analyzers := append(tokenAnalyzers(), DensityAnalyzer{}, LineCountAnalyzer{})
+	for _, content := range concurrencyInputs {
+		shared := analyzeContent(content, analyzers)
+		plain := analyzeContent(content, withoutArtifacts(analyzers))
+		if !reflect.DeepEqual(shared, plain) {
+			t.Errorf("%q: results with artifacts %v differ from plain %v", content, shared, plain)
+		}
+		if seq := analyzeContentSequential(content, analyzers); !reflect.DeepEqual(seq, plain) {
+			t.Errorf("%q: sequential results with artifacts differ from plain", content)
+		}
+	}
+}
+
+func TestBuildArtifactsOnlyRequested(t *testing.T) {
+	if art := buildArtifacts("a b", []Analyzer{LineCountAnalyzer{}}); art != nil {
+		t.Errorf("expected no artifacts, got %+v", art)
+	}
+	art := buildArtifacts("A b\nc", []Analyzer{MostFrequentWordsAnalyzer{}})
+	if strings.Join(art.Lowercased, " ") != "a b c" || art.Lines != nil {
+		t.Errorf("unexpected artifacts %+v", art)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+)
+
+// Количество файлов, на которых подбирается число горутин
+const autotuneSample = 20
+
+// Время анализа выборки при заданном числе горутин
+type TuneTiming struct {
+	Workers  int
+	Duration time.Duration
+}
+
+// Варианты числа горутин: степени двойки до 2*NumCPU
+func autotuneCandidates() []int {
+	var candidates []int
+	for w := 1; w <= 2*runtime.NumCPU(); w *= 2 {
+		candidates = append(candidates, w)
+	}
+	return candidates
+}
+
+// Замер AnalyzeParallel на выборке файлов для каждого варианта
+func measureWorkers(files []string, analyzers []Analyzer, candidates []int) []TuneTiming {
+	if len(files) > autotuneSample {
+		files = files[:autotuneSample]
+	}
+	timings := make([]TuneTiming, 0, len(candidates))
+	for _, w := range candidates {
+		start := time.Now()
+		AnalyzeParallel(files, analyzers, w)
+		timings = append(timings, TuneTiming{w, time.Since(start)})
+	}
+	return timings
+}
+
+// Самый быстрый вариант; при равном времени выбирается меньшее число горутин
+func pickFastest(timings []TuneTiming) int {
+	best := timings[0]
+	for _, t := range timings[1:] {
+		if t.Duration < best.Duration || (t.Duration == best.Duration && t.Workers < best.Workers) {
+			best = t
+		}
+	}
+	return best.Workers
+}
+
+// Подбор числа горутин с печатью замеров, возвращает лучший вариант
+func autotune(out io.Writer, files []string, analyzers []Analyzer) int {
+	timings := measureWorkers(files, analyzers, autotuneCandidates())
+	best := pickFastest(timings)
+	fmt.Fprintln(out, "Подбор числа горутин:")
+	for _, t := range timings {
+		fmt.Fprintf(out, " workers=%d: %v\n", t.Workers, t.Duration)
+	}
+	fmt.Fprintf(out, "Лучший вариант: workers=%d\n\n", best)
+	return best
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"stage5/internal/testutil"
+	"testing"
+	"time"
+)
+
+func TestPickFastest(t *testing.T) {
+	timings := []TuneTiming{
+		{1, 90 * time.Millisecond},
+		{2, 50 * time.Millisecond},
+		{4, 30 * time.Millisecond},
+		{8, 35 * time.Millisecond},
+	}
+	if got := pickFastest(timings); got != 4 {
+		t.Errorf("expected 4 workers, got %d", got)
+	}
+}
+
+func TestPickFastestTie(t *testing.T) {
+	timings := []TuneTiming{
+		{8, 20 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{4, 20 * time.Millisecond},
+	}
+	if got := pickFastest(timings); got != 2 {
+		t.Errorf("expected fewest workers on tie, got %d", got)
+	}
+}
+
+func TestAutotuneKeepsJSONClean(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": "hello world", "b.txt": "hello go"})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Autotune: "use"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("autotune table leaked into JSON output: %v\n%s", err, out.String())
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import "strings"
+
+// Частотный словарь без приведения к нижнему регистру: "Go" и "go" - разные слова.
+// Замена MostFrequentWordsAnalyzer при -case-sensitive, поэтому имя результата то же
+// и сводка, -top-words и кластеры работают без изменений
+type CaseSensitiveFreqAnalyzer struct{}
+
+func (c CaseSensitiveFreqAnalyzer) Name() string {
+	return "most_frequent_words"
+}
+func (c CaseSensitiveFreqAnalyzer) Analyze(content string) AnalysisResult {
+	return c.AnalyzeWithArtifacts(content, &Artifacts{Tokens: strings.Fields(content)})
+}
+func (c CaseSensitiveFreqAnalyzer) Artifacts() []string {
+	return []string{ArtifactTokens}
+}
+func (c CaseSensitiveFreqAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	freq := make(map[string]int)
+	for _, word := range art.Tokens {
+		freq[word]++
+	}
+	return AnalysisResult{
+		NameAnalyzer: c.Name(),
+		Data:         freq,
+	}
+}
+
+// Приведение токена к ключу частотного словаря, как в frequencyAnalyzer
+func frequencyKey(opts Options) func(string) string {
+	if opts.CaseSensitive {
+		return func(s string) string { return s }
+	}
+	if opts.Locale != "" {
+		return newCaseFolder(opts.Locale).Fold
+	}
+	return strings.ToLower
+}
+
+// Анализатор частоты слов с учётом -case-sensitive и -locale
+func frequencyAnalyzer(opts Options) Analyzer {
+	if opts.CaseSensitive {
+		return CaseSensitiveFreqAnalyzer{}
+	}
+	if opts.CappedFrequencies {
+		return MostFrequentWordsAnalyzer{Locale: opts.Locale, Cap: opts.FrequencyCap}
+	}
+	return MostFrequentWordsAnalyzer{Locale: opts.Locale}
+}
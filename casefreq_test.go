@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFrequencyCaseHandling(t *testing.T) {
+	content := "Go go GO gopher Gopher"
+	insensitive := runAnalyzer(MostFrequentWordsAnalyzer{}, content).Data
+	if want := map[string]int{"go": 3, "gopher": 2}; !reflect.DeepEqual(insensitive, want) {
+		t.Errorf("case-insensitive: expected %v, got %v", want, insensitive)
+	}
+	sensitive := runAnalyzer(CaseSensitiveFreqAnalyzer{}, content).Data
+	if want := map[string]int{"Go": 1, "go": 1, "GO": 1, "gopher": 1, "Gopher": 1}; !reflect.DeepEqual(sensitive, want) {
+		t.Errorf("case-sensitive: expected %v, got %v", want, sensitive)
+	}
+}
+
+func TestRunTopWordsCaseSensitive(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("Go go Go rust"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	top := func(opts Options) []WordCount {
+		t.Helper()
+		var out bytes.Buffer
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		return report.Summary.TopWords
+	}
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", TopWords: 2}
+	if got, want := top(opts), []WordCount{{"go", 3}, {"rust", 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default: expected %v, got %v", want, got)
+	}
+	opts.CaseSensitive = true
+	if got, want := top(opts), []WordCount{{"Go", 2}, {"go", 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("-case-sensitive: expected %v, got %v", want, got)
+	}
+
+	opts.Locale = "tr"
+	if err := run(context.Background(), opts, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for -case-sensitive with -locale")
+	}
+}
@@ -0,0 +1,17 @@
+package main
+
+import "unicode/utf8"
+
+// Анализатор количества символов (рун, не байт).
+// Не входит в набор по умолчанию, запускается по колонке chars или через -analyzers
+type CharCountAnalyzer struct{}
+
+func (c CharCountAnalyzer) Name() string {
+	return "char_count"
+}
+func (c CharCountAnalyzer) Analyze(content string) AnalysisResult {
+	return AnalysisResult{
+		NameAnalyzer: c.Name(),
+		Data:         utf8.RuneCountInString(content),
+	}
+}
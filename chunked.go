@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// Анализатор, результаты которого по частям файла можно объединить в результат
+// для всего файла. Остальные анализаторы всегда работают над всем содержимым
+type MergeableAnalyzer interface {
+	Analyzer
+	MergeChunks(parts []any) any
+}
+
+func sumInts(parts []any) int {
+	total := 0
+	for _, p := range parts {
+		total += p.(int)
+	}
+	return total
+}
+
+func mergeFreq(parts []any) any {
+	merged := make(map[string]int)
+	for _, p := range parts {
+		for w, c := range p.(map[string]int) {
+			merged[w] += c
+		}
+	}
+	return merged
+}
+
+func (w WordCountAnalyzer) MergeChunks(parts []any) any {
+	return sumInts(parts)
+}
+
+// каждая часть считает на одну строку больше, чем переводов строк в ней
+func (l LineCountAnalyzer) MergeChunks(parts []any) any {
+	return sumInts(parts) - (len(parts) - 1)
+}
+
+func (m MostFrequentWordsAnalyzer) MergeChunks(parts []any) any {
+	merged := mergeFreq(parts).(map[string]int)
+	if m.Cap > 0 {
+		return pruneFrequencies(merged, m.Cap)
+	}
+	return merged
+}
+
+func (c CaseSensitiveFreqAnalyzer) MergeChunks(parts []any) any {
+	return mergeFreq(parts)
+}
+
+// Разбиение content на части примерно по chunkSize байт.
+// Граница всегда проходит сразу после перевода строки, слова не разрываются.
+func splitChunks(content string, chunkSize int) []string {
+	var chunks []string
+	for len(content) > chunkSize {
+		i := strings.IndexByte(content[chunkSize:], '\n')
+		if i < 0 {
+			break
+		}
+		end := chunkSize + i + 1
+		chunks = append(chunks, content[:end])
+		content = content[end:]
+	}
+	return append(chunks, content)
+}
+
+// Анализ большого файла частями: MergeableAnalyzer работают
+// над каждой частью параллельно, остальные - над всем содержимым.
+func analyzeChunked(content string, analyzers []Analyzer, chunkSize int) []AnalysisResult {
+	chunks := splitChunks(content, chunkSize)
+	results := make([]AnalysisResult, len(analyzers))
+
+	var wg sync.WaitGroup
+	for i, a := range analyzers {
+		m, ok := a.(MergeableAnalyzer)
+		if !ok || len(chunks) == 1 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = runAnalyzer(a, content)
+			}()
+			continue
+		}
+
+		parts := make([]AnalysisResult, len(chunks))
+		var cwg sync.WaitGroup
+		for j, chunk := range chunks {
+			cwg.Add(1)
+			go func() {
+				defer cwg.Done()
+				parts[j] = runAnalyzer(cloneAnalyzer(a), chunk)
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cwg.Wait()
+			data := make([]any, len(parts))
+			for j, p := range parts {
+				data[j] = p.Data
+			}
+			results[i] = AnalysisResult{NameAnalyzer: a.Name(), Data: m.MergeChunks(data), Confidence: 1}
+		}()
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	content := "alpha beta\ngamma delta\nepsilon\n"
+	chunks := splitChunks(content, 5)
+
+	if strings.Join(chunks, "") != content {
+		t.Fatalf("chunks do not reassemble content: %q", chunks)
+	}
+	for _, c := range chunks[:len(chunks)-1] {
+		if !strings.HasSuffix(c, "\n") {
+			t.Errorf("chunk %q does not end at a line boundary", c)
+		}
+	}
+}
+
+func TestAnalyzeChunkedMatchesWhole(t *testing.T) {
+	content := strings.Repeat("The quick brown fox\njumps over the lazy dog\n\n", 500) + "no trailing newline"
+	analyzers := []Analyzer{
+		WordCountAnalyzer{},
+		LineCountAnalyzer{},
+		MostFrequentWordsAnalyzer{},
+		DensityAnalyzer{},
+	}
+
+	whole := analyzeContent(content, analyzers)
+	chunked := analyzeChunked(content, analyzers, 1000)
+
+	if !reflect.DeepEqual(whole, chunked) {
+		t.Errorf("chunked results differ from whole-file results:\n%v\n%v", whole, chunked)
+	}
+}
+
+func hugeContent() string {
+	return strings.Repeat("lorem ipsum dolor sit amet consectetur adipiscing elit\n", 200000)
+}
+
+func BenchmarkWholeFile(b *testing.B) {
+	content := hugeContent()
+	analyzers := []Analyzer{WordCountAnalyzer{}, LineCountAnalyzer{}, MostFrequentWordsAnalyzer{}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzeContent(content, analyzers)
+	}
+}
+
+func BenchmarkChunked(b *testing.B) {
+	content := hugeContent()
+	analyzers := []Analyzer{WordCountAnalyzer{}, LineCountAnalyzer{}, MostFrequentWordsAnalyzer{}}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analyzeChunked(content, analyzers, len(content)/8)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+// Анализатор с состоянием, которое нельзя разделять между горутинами.
+// Конвейер вызывает Clone для каждого воркера, и копия используется одной горутиной
+type CloneableAnalyzer interface {
+	Analyzer
+	Clone() Analyzer
+}
+
+func cloneAnalyzer(a Analyzer) Analyzer {
+	if c, ok := a.(CloneableAnalyzer); ok {
+		return c.Clone()
+	}
+	return a
+}
+
+// Собственный набор анализаторов для воркера
+func cloneAnalyzers(analyzers []Analyzer) []Analyzer {
+	out := make([]Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		out[i] = cloneAnalyzer(a)
+	}
+	return out
+}
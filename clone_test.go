@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"stage5/internal/testutil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+var concurrencyInputs = []string{
+	"",
+	"hello world\nhello go",
+	"Привет, мир! Это «цитата» и ещё одно предложение. Второе предложение.",
+	"#!/usr/bin/env python3\r\nimport os\r\n\tprint(os.getcwd())\r\n",
+	"// SPDX-License-Identifier: MIT\n// Copyright 2020-2024\npackage main\n\nfunc main() {\n\tif true {\n\t}\n}\n",
+	"\"unbalanced quote\n    indented line\n\tTabbed line\r" + strings.Repeat("word ", 500),
+}
+
+// Каждый зарегистрированный анализатор вызывается из 32 горутин,
+// результаты должны совпадать с последовательным запуском. Имеет смысл с -race
+func TestRegisteredAnalyzersConcurrentUse(t *testing.T) {
+	for _, name := range registry.Names() {
+		a, _ := registry.Get(name)
+		want := make([]AnalysisResult, len(concurrencyInputs))
+		for i, in := range concurrencyInputs {
+			want[i] = a.Analyze(in)
+		}
+
+		var wg sync.WaitGroup
+		errs := make(chan string, 32)
+		for g := 0; g < 32; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for k := 0; k < len(concurrencyInputs); k++ {
+					i := (g + k) % len(concurrencyInputs)
+					if got := a.Analyze(concurrencyInputs[i]); !reflect.DeepEqual(got, want[i]) {
+						errs <- fmt.Sprintf("%s: input %d: got %v, want %v", name, i, got, want[i])
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for e := range errs {
+			t.Error(e)
+		}
+	}
+}
+
+// Анализатор с несинхронизированным состоянием, безопасен только через Clone
+type statefulAnalyzer struct {
+	seen   map[string]int
+	clones *atomic.Int32
+}
+
+func (s statefulAnalyzer) Name() string {
+	return "stateful"
+}
+func (s statefulAnalyzer) Analyze(content string) AnalysisResult {
+	s.seen[content]++
+	return AnalysisResult{NameAnalyzer: s.Name(), Data: len(content)}
+}
+func (s statefulAnalyzer) Clone() Analyzer {
+	s.clones.Add(1)
+	return statefulAnalyzer{seen: make(map[string]int), clones: s.clones}
+}
+
+func TestPipelineClonesPerWorker(t *testing.T) {
+	var files []string
+	for i := 0; i < 8; i++ {
+		path := testutil.CreateTempFile(t, strings.Repeat("x", i+1))
+		files = append(files, path)
+	}
+
+	var clones atomic.Int32
+	proto := statefulAnalyzer{clones: &clones}
+	results, err := AnalyzeParallel(files, []Analyzer{proto}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(files) {
+		t.Fatalf("expected %d results, got %d", len(files), len(results))
+	}
+	if clones.Load() != 4 {
+		t.Errorf("expected one clone per worker, got %d", clones.Load())
+	}
+
+	worker := proto.Clone()
+	clones.Store(0)
+	content := strings.Repeat("a b c\n", 100)
+	analyzeChunked(content, []Analyzer{WordCountAnalyzer{}, worker}, 64)
+	if clones.Load() != 0 {
+		t.Errorf("analyzer without chunk merger should run once on the whole content, got %d clones", clones.Load())
+	}
+}
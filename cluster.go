@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Параметры кластеризации по умолчанию
+const (
+	clusterDims  = 1000 // размерность векторов: самые частые слова корпуса
+	clusterTerms = 5    // характерных слов в отчёте о кластере
+	clusterIters = 100
+)
+
+// Группа файлов со схожим словарём
+type Cluster struct {
+	Size  int      `json:"size"`
+	Terms []string `json:"terms"`
+	Files []string `json:"files"`
+}
+
+// Кластеризация файлов k-means по TF-IDF векторам частотных словарей.
+// K больше числа файлов уменьшается до числа файлов, пустые кластеры не попадают в отчёт.
+func ClusterFiles(results []FileAnalysisResult, k int, seed int64) []Cluster {
+	type doc struct {
+		name string
+		freq map[string]int
+	}
+	var docs []doc
+	for _, res := range results {
+		for _, r := range res.Results {
+			if freq, ok := r.Data.(map[string]int); ok && r.NameAnalyzer == "most_frequent_words" {
+				docs = append(docs, doc{res.FilePath, freq})
+			}
+		}
+	}
+	// порядок результатов зависит от горутин, для воспроизводимости сортируем
+	sort.Slice(docs, func(i, j int) bool { return docs[i].name < docs[j].name })
+	if k > len(docs) {
+		k = len(docs)
+	}
+	if k <= 0 {
+		return nil
+	}
+
+	// словарь: самые частые слова корпуса
+	global := make(map[string]int)
+	df := make(map[string]int)
+	for _, d := range docs {
+		for w, c := range d.freq {
+			global[w] += c
+			df[w]++
+		}
+	}
+	var vocab []WordCount
+	for w, c := range global {
+		vocab = append(vocab, WordCount{w, c})
+	}
+	sort.Slice(vocab, func(i, j int) bool {
+		if vocab[i].Count != vocab[j].Count {
+			return vocab[i].Count > vocab[j].Count
+		}
+		return vocab[i].Word < vocab[j].Word
+	})
+	if len(vocab) > clusterDims {
+		vocab = vocab[:clusterDims]
+	}
+
+	vectors := make([][]float64, len(docs))
+	for i, d := range docs {
+		total := 0
+		for _, c := range d.freq {
+			total += c
+		}
+		v := make([]float64, len(vocab))
+		for j, w := range vocab {
+			if c := d.freq[w.Word]; c > 0 {
+				idf := math.Log(float64(1+len(docs))/float64(1+df[w.Word])) + 1
+				v[j] = float64(c) / float64(total) * idf
+			}
+		}
+		vectors[i] = normalize(v)
+	}
+
+	assign := kmeans(vectors, k, rand.New(rand.NewSource(seed)))
+
+	clusters := make([]Cluster, k)
+	centroids := centroidsOf(vectors, assign, k, len(vocab))
+	for i, c := range assign {
+		clusters[c].Size++
+		clusters[c].Files = append(clusters[c].Files, docs[i].name)
+	}
+	for c := range clusters {
+		clusters[c].Files = limitFiles(clusters[c].Files)
+		idx := make([]int, len(vocab))
+		for j := range idx {
+			idx[j] = j
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return centroids[c][idx[a]] > centroids[c][idx[b]] })
+		for _, j := range idx[:min(clusterTerms, len(idx))] {
+			if centroids[c][j] > 0 {
+				clusters[c].Terms = append(clusters[c].Terms, vocab[j].Word)
+			}
+		}
+	}
+
+	var out []Cluster
+	for _, c := range clusters {
+		if c.Size > 0 {
+			out = append(out, c)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Size > out[j].Size })
+	return out
+}
+
+func normalize(v []float64) []float64 {
+	var norm float64
+	for _, x := range v {
+		norm += x * x
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
+
+func sqDist(a, b []float64) float64 {
+	var d float64
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return d
+}
+
+// k-means с начальными центрами k-means++, возвращает номер кластера для каждого вектора
+func kmeans(vectors [][]float64, k int, rng *rand.Rand) []int {
+	centroids := [][]float64{vectors[rng.Intn(len(vectors))]}
+	for len(centroids) < k {
+		dists := make([]float64, len(vectors))
+		var sum float64
+		for i, v := range vectors {
+			dists[i] = math.Inf(1)
+			for _, c := range centroids {
+				dists[i] = math.Min(dists[i], sqDist(v, c))
+			}
+			sum += dists[i]
+		}
+		next := rng.Intn(len(vectors))
+		if sum > 0 {
+			r := rng.Float64() * sum
+			for i, d := range dists {
+				if r -= d; r <= 0 {
+					next = i
+					break
+				}
+			}
+		}
+		centroids = append(centroids, vectors[next])
+	}
+
+	assign := make([]int, len(vectors))
+	for i := range assign {
+		assign[i] = -1
+	}
+	for iter := 0; iter < clusterIters; iter++ {
+		changed := false
+		for i, v := range vectors {
+			best := 0
+			for c := range centroids {
+				if sqDist(v, centroids[c]) < sqDist(v, centroids[best]) {
+					best = c
+				}
+			}
+			if assign[i] != best {
+				assign[i] = best
+				changed = true
+			}
+		}
+
+		// опустевший кластер получает самую далёкую от своего центра точку
+		sizes := make([]int, k)
+		for _, c := range assign {
+			sizes[c]++
+		}
+		for c := range centroids {
+			if sizes[c] > 0 {
+				continue
+			}
+			far, farDist := -1, 0.0
+			for i, v := range vectors {
+				if d := sqDist(v, centroids[assign[i]]); sizes[assign[i]] > 1 && d > farDist {
+					far, farDist = i, d
+				}
+			}
+			if far >= 0 {
+				sizes[assign[far]]--
+				assign[far] = c
+				sizes[c]++
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+		centroids = centroidsOf(vectors, assign, k, len(vectors[0]))
+	}
+	return assign
+}
+
+func centroidsOf(vectors [][]float64, assign []int, k, dims int) [][]float64 {
+	centroids := make([][]float64, k)
+	counts := make([]int, k)
+	for c := range centroids {
+		centroids[c] = make([]float64, dims)
+	}
+	for i, v := range vectors {
+		c := assign[i]
+		counts[c]++
+		for j, x := range v {
+			centroids[c][j] += x
+		}
+	}
+	for c := range centroids {
+		if counts[c] > 0 {
+			for j := range centroids[c] {
+				centroids[c][j] /= float64(counts[c])
+			}
+		}
+	}
+	return centroids
+}
+
+func writeClustersText(out io.Writer, c colorizer, clusters []Cluster) {
+	fmt.Fprintln(out, "Кластеры:")
+	for i, cl := range clusters {
+		fmt.Fprintf(out, " #%d (%d файлов) [%s]: %s\n", i+1, cl.Size, strings.Join(cl.Terms, ", "), c.names(cl.Files))
+	}
+	fmt.Fprintln(out)
+}
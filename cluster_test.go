@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func clusterCorpus() []FileAnalysisResult {
+	texts := map[string]string{
+		"cats1.txt": "cat kitten meow purr cat whiskers",
+		"cats2.txt": "kitten purr meow cat cat",
+		"cats3.txt": "whiskers cat meow kitten",
+		"cars1.txt": "engine wheel brake gear engine",
+		"cars2.txt": "gear brake engine clutch",
+		"cars3.txt": "wheel clutch engine gear brake",
+	}
+	var results []FileAnalysisResult
+	for name, text := range texts {
+		results = append(results, FileAnalysisResult{
+			FileName: name,
+			FilePath: name,
+			Results:  []AnalysisResult{MostFrequentWordsAnalyzer{}.Analyze(text)},
+		})
+	}
+	return results
+}
+
+func TestClusterFilesSeparatesVocabularies(t *testing.T) {
+	clusters := ClusterFiles(clusterCorpus(), 2, 1)
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d", len(clusters))
+	}
+
+	var groups [][]string
+	for _, c := range clusters {
+		files := append([]string(nil), c.Files...)
+		sort.Strings(files)
+		groups = append(groups, files)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+
+	want := [][]string{
+		{"cars1.txt", "cars2.txt", "cars3.txt"},
+		{"cats1.txt", "cats2.txt", "cats3.txt"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("expected perfect separation %v, got %v", want, groups)
+	}
+	for _, c := range clusters {
+		if len(c.Terms) == 0 {
+			t.Errorf("expected characteristic terms for cluster %v", c.Files)
+		}
+	}
+}
+
+func TestClusterFilesDeterministic(t *testing.T) {
+	first := fmt.Sprint(ClusterFiles(clusterCorpus(), 3, 7))
+	for i := 0; i < 5; i++ {
+		if got := fmt.Sprint(ClusterFiles(clusterCorpus(), 3, 7)); got != first {
+			t.Fatalf("expected deterministic clustering, got %s and %s", first, got)
+		}
+	}
+}
+
+func TestClusterFilesKLargerThanFiles(t *testing.T) {
+	corpus := clusterCorpus()[:2]
+	clusters := ClusterFiles(corpus, 10, 1)
+	total := 0
+	for _, c := range clusters {
+		if c.Size == 0 {
+			t.Error("empty cluster reported")
+		}
+		total += c.Size
+	}
+	if len(clusters) > 2 || total != 2 {
+		t.Errorf("expected at most 2 clusters covering 2 files, got %+v", clusters)
+	}
+	if ClusterFiles(nil, 3, 1) != nil {
+		t.Error("expected no clusters for empty corpus")
+	}
+}
+
+func TestClusterFilesIdenticalDocuments(t *testing.T) {
+	var corpus []FileAnalysisResult
+	for i := 0; i < 3; i++ {
+		corpus = append(corpus, FileAnalysisResult{
+			FileName: fmt.Sprintf("same%d.txt", i),
+			FilePath: fmt.Sprintf("same%d.txt", i),
+			Results:  []AnalysisResult{MostFrequentWordsAnalyzer{}.Analyze("same words here")},
+		})
+	}
+	total := 0
+	for _, c := range ClusterFiles(corpus, 3, 1) {
+		total += c.Size
+	}
+	if total != 3 {
+		t.Errorf("expected all 3 files assigned, got %d", total)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ANSI коды оформления
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiCyan   = "\033[36m"
+)
+
+// Цвета для первых мест в списке самых частых слов
+var rankColors = []string{ansiRed, ansiYellow, ansiGreen}
+
+// Раскраска текстового вывода, при enabled=false возвращает строки без изменений.
+// rawNames отключает экранирование имён файлов (см. names.go)
+type colorizer struct {
+	enabled  bool
+	rawNames bool
+}
+
+func (c colorizer) wrap(code, s string) string {
+	if !c.enabled {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+func (c colorizer) bold(s string) string {
+	return c.wrap(ansiBold, s)
+}
+
+func (c colorizer) highlight(s string) string {
+	return c.wrap(ansiBold+ansiCyan, s)
+}
+
+// Цвет по месту в рейтинге (начиная с 0)
+func (c colorizer) rank(i int, s string) string {
+	if i >= len(rankColors) {
+		return s
+	}
+	return c.wrap(rankColors[i], s)
+}
+
+// Нужна ли раскраска для режима auto|always|never.
+// В режиме auto цвет включается только для терминала и при пустом NO_COLOR.
+func colorEnabled(mode string, out io.Writer) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "", "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		f, ok := out.(*os.File)
+		if !ok {
+			return false, nil
+		}
+		info, err := f.Stat()
+		if err != nil {
+			return false, nil
+		}
+		return info.Mode()&os.ModeCharDevice != 0, nil
+	}
+	return false, fmt.Errorf("неизвестный режим -color %q", mode)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunColor(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		mode    string
+		escapes bool
+	}{
+		{"never", false},
+		{"auto", false}, // bytes.Buffer - не терминал
+		{"always", true},
+	} {
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 1, TopWords: 2, Color: tt.mode}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(out.String(), "\033["); got != tt.escapes {
+			t.Errorf("-color=%s: expected escapes=%v, output:\n%q", tt.mode, tt.escapes, out.String())
+		}
+	}
+}
+
+func TestColorEnabledNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if on, _ := colorEnabled("auto", os.Stdout); on {
+		t.Error("expected NO_COLOR to disable auto color")
+	}
+	if on, _ := colorEnabled("always", os.Stdout); !on {
+		t.Error("expected -color=always to override NO_COLOR")
+	}
+	if _, err := colorEnabled("rainbow", os.Stdout); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
@@ -0,0 +1,55 @@
+//go:build go_analyzers
+
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+func init() {
+	registry.Register(GoCyclomaticComplexityAnalyzer{})
+	extraAnalyzers = append(extraAnalyzers, func(opts Options) Analyzer {
+		if opts.Ext != ".go" {
+			return nil
+		}
+		return GoCyclomaticComplexityAnalyzer{}
+	})
+}
+
+// Анализатор цикломатической сложности Go файлов: количество ветвлений
+// (if, for, range, switch, select и case). Для непарсящегося файла возвращает -1.
+type GoCyclomaticComplexityAnalyzer struct{}
+
+func (g GoCyclomaticComplexityAnalyzer) Name() string {
+	return "cyclomatic_complexity"
+}
+func (g GoCyclomaticComplexityAnalyzer) Aggregation() Aggregation {
+	return AggregateMax
+}
+func (g GoCyclomaticComplexityAnalyzer) Description() string {
+	return "цикломатическая сложность Go файла, -1 если файл не разбирается"
+}
+func (g GoCyclomaticComplexityAnalyzer) OutputSchema() []SchemaField {
+	return scalar("int", "branches")
+}
+func (g GoCyclomaticComplexityAnalyzer) Analyze(content string) AnalysisResult {
+	file, err := parser.ParseFile(token.NewFileSet(), "", content, parser.SkipObjectResolution)
+	if err != nil {
+		return AnalysisResult{NameAnalyzer: g.Name(), Data: -1}
+	}
+
+	complexity := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.CaseClause:
+			complexity++
+		}
+		return true
+	})
+	return AnalysisResult{
+		NameAnalyzer: g.Name(),
+		Data:         complexity,
+	}
+}
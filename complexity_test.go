@@ -0,0 +1,39 @@
+//go:build go_analyzers
+
+package main
+
+import "testing"
+
+func TestGoCyclomaticComplexityAnalyzer(t *testing.T) {
+	src := `package sample
+
+func classify(xs []int) string {
+	for _, x := range xs {
+		if x < 0 {
+			return "negative"
+		}
+	}
+	for i := 0; i < 3; i++ {
+	}
+	switch len(xs) {
+	case 0:
+		return "empty"
+	case 1:
+		return "single"
+	}
+	return "many"
+}
+`
+	// range, if, for, switch и два case
+	res := GoCyclomaticComplexityAnalyzer{}.Analyze(src)
+	if res.Data.(int) != 6 {
+		t.Errorf("expected complexity 6, got %d", res.Data.(int))
+	}
+}
+
+func TestGoCyclomaticComplexityAnalyzerInvalid(t *testing.T) {
+	res := GoCyclomaticComplexityAnalyzer{}.Analyze("not go code")
+	if res.Data.(int) != -1 {
+		t.Errorf("expected -1 for invalid source, got %d", res.Data.(int))
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !windows
+
+package main
+
+// На других ОС терминалы работают в UTF-8
+func setupConsole() {}
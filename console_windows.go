@@ -0,0 +1,16 @@
+//go:build windows
+
+package main
+
+import "syscall"
+
+// Кодовая страница UTF-8 для вывода кириллицы в старых консолях.
+// Windows Terminal уже работает в UTF-8, там вызов ничего не меняет.
+// Если вызов не удался, остаётся кодовая страница консоли: используйте chcp 65001
+func setupConsole() {
+	const cpUTF8 = 65001
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleOutputCP")
+	if proc.Find() == nil {
+		proc.Call(cpUTF8)
+	}
+}
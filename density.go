@@ -0,0 +1,106 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Метрики плотности текста файла
+type DensityStats struct {
+	MeanWordsPerLine float64 `json:"mean_words_per_line"`
+	MaxWordsPerLine  int     `json:"max_words_per_line"`
+	CharsPerWord     float64 `json:"chars_per_word"`
+}
+
+// Анализатор плотности: слов на строку и символов на слово
+type DensityAnalyzer struct{}
+
+func (d DensityAnalyzer) Name() string {
+	return "density"
+}
+func (d DensityAnalyzer) Analyze(content string) AnalysisResult {
+	return d.AnalyzeWithArtifacts(content, &Artifacts{Lines: strings.Split(content, "\n")})
+}
+func (d DensityAnalyzer) Artifacts() []string {
+	return []string{ArtifactLines}
+}
+func (d DensityAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	var stats DensityStats
+	lines := art.Lines
+	words, chars := 0, 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > stats.MaxWordsPerLine {
+			stats.MaxWordsPerLine = len(fields)
+		}
+		for _, f := range fields {
+			chars += utf8.RuneCountInString(f)
+		}
+		words += len(fields)
+	}
+	stats.MeanWordsPerLine = float64(words) / float64(len(lines))
+	if words > 0 {
+		stats.CharsPerWord = float64(chars) / float64(words)
+	}
+	return AnalysisResult{
+		NameAnalyzer: d.Name(),
+		Data:         stats,
+	}
+}
+
+// Поиск файлов, плотность которых отклоняется от среднего по корпусу
+// больше чем на sigma стандартных отклонений.
+// Выполняется после обработки всех файлов.
+func FindDensityOutliers(results []FileAnalysisResult, sigma float64) []string {
+	type entry struct {
+		name  string
+		stats DensityStats
+	}
+	var entries []entry
+	for _, res := range results {
+		for _, r := range res.Results {
+			if stats, ok := r.Data.(DensityStats); ok && r.NameAnalyzer == "density" {
+				entries = append(entries, entry{res.FilePath, stats})
+			}
+		}
+	}
+	if len(entries) < 2 || sigma <= 0 {
+		return nil
+	}
+
+	meanStd := func(get func(DensityStats) float64) (float64, float64) {
+		var sum, sq float64
+		for _, e := range entries {
+			sum += get(e.stats)
+		}
+		mean := sum / float64(len(entries))
+		for _, e := range entries {
+			d := get(e.stats) - mean
+			sq += d * d
+		}
+		return mean, math.Sqrt(sq / float64(len(entries)))
+	}
+	metrics := []func(DensityStats) float64{
+		func(s DensityStats) float64 { return s.MeanWordsPerLine },
+		func(s DensityStats) float64 { return s.CharsPerWord },
+	}
+	means := make([]float64, len(metrics))
+	stds := make([]float64, len(metrics))
+	for i, get := range metrics {
+		means[i], stds[i] = meanStd(get)
+	}
+
+	var flagged []string
+	for _, e := range entries {
+		for i, get := range metrics {
+			if stds[i] > 0 && math.Abs(get(e.stats)-means[i]) > sigma*stds[i] {
+				flagged = append(flagged, e.name)
+				break
+			}
+		}
+	}
+	sort.Strings(flagged)
+	return flagged
+}
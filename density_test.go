@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDensityAnalyzer(t *testing.T) {
+	res := DensityAnalyzer{}.Analyze("one two three\nfour\n")
+	d := res.Data.(DensityStats)
+	if d.MaxWordsPerLine != 3 {
+		t.Errorf("expected max 3 words per line, got %d", d.MaxWordsPerLine)
+	}
+	if d.MeanWordsPerLine != 4.0/3 {
+		t.Errorf("expected mean %.2f words per line, got %.2f", 4.0/3, d.MeanWordsPerLine)
+	}
+	if d.CharsPerWord != 15.0/4 {
+		t.Errorf("expected %.2f chars per word, got %.2f", 15.0/4, d.CharsPerWord)
+	}
+}
+
+func TestFindDensityOutliers(t *testing.T) {
+	prose := "The quick brown fox jumps over the lazy dog.\nIt was a bright cold day in April.\nAnd the clocks were striking thirteen.\n"
+
+	var results []FileAnalysisResult
+	for i := 0; i < 9; i++ {
+		results = append(results, FileAnalysisResult{
+			FileName: fmt.Sprintf("prose%d.txt", i),
+			FilePath: fmt.Sprintf("prose%d.txt", i),
+			Results:  []AnalysisResult{DensityAnalyzer{}.Analyze(prose)},
+		})
+	}
+	results = append(results, FileAnalysisResult{
+		FileName: "minified.txt",
+		FilePath: "minified.txt",
+		Results:  []AnalysisResult{DensityAnalyzer{}.Analyze(strings.Repeat("word ", 10000))},
+	})
+
+	outliers := FindDensityOutliers(results, 2)
+	if len(outliers) != 1 || outliers[0] != "minified.txt" {
+		t.Errorf("expected only minified.txt flagged, got %v", outliers)
+	}
+}
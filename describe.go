@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// Поле данных результата анализатора. Для скалярного результата одно поле "value"
+type SchemaField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Unit string `json:"unit,omitempty"`
+}
+
+// Анализатор с описанием для describe и манифеста JSON отчёта
+type DescribedAnalyzer interface {
+	Analyzer
+	Description() string
+	OutputSchema() []SchemaField
+}
+
+func scalar(typ, unit string) []SchemaField {
+	return []SchemaField{{Name: "value", Type: typ, Unit: unit}}
+}
+
+func (w WordCountAnalyzer) Description() string {
+	return "число слов, разделённых пробельными символами"
+}
+func (w WordCountAnalyzer) OutputSchema() []SchemaField { return scalar("int", "words") }
+
+func (l LineCountAnalyzer) Description() string         { return "число строк" }
+func (l LineCountAnalyzer) OutputSchema() []SchemaField { return scalar("int", "lines") }
+
+func (m MostFrequentWordsAnalyzer) Description() string {
+	return "частоты слов в нижнем регистре"
+}
+func (m MostFrequentWordsAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string]int", "occurrences")
+}
+
+func (c CaseSensitiveFreqAnalyzer) Description() string {
+	return "частоты слов с учётом регистра"
+}
+func (c CaseSensitiveFreqAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string]int", "occurrences")
+}
+
+func (d DensityAnalyzer) Description() string {
+	return "плотность текста: слов на строку и символов на слово"
+}
+func (d DensityAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"mean_words_per_line", "float64", "words/line"},
+		{"max_words_per_line", "int", "words"},
+		{"chars_per_word", "float64", "chars/word"},
+	}
+}
+
+func (t TermExtractorAnalyzer) Description() string {
+	return "термины: аббревиатуры и CamelCase идентификаторы"
+}
+func (t TermExtractorAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string]int", "occurrences")
+}
+
+func (q QuoteAnalyzer) Description() string {
+	return "цитаты в кавычках и непарные кавычки"
+}
+func (q QuoteAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"count", "int", "quotes"},
+		{"total_length", "int", "chars"},
+		{"longest", "string", ""},
+		{"unbalanced", "int", "quotes"},
+		{"quotes", "[]string", ""},
+		{"examples", "[]LineExample", ""},
+	}
+}
+
+func (l LanguageDetectorAnalyzer) Description() string {
+	return "язык текста, уверенность в поле confidence"
+}
+func (l LanguageDetectorAnalyzer) OutputSchema() []SchemaField { return scalar("string", "") }
+
+func (t TypeAnalyzer) Description() string {
+	return "MIME-тип по содержимому файла"
+}
+func (t TypeAnalyzer) OutputSchema() []SchemaField { return scalar("string", "") }
+
+func (s ShebangAnalyzer) Description() string {
+	return "язык скрипта по строке shebang"
+}
+func (s ShebangAnalyzer) OutputSchema() []SchemaField { return scalar("string", "") }
+
+func (l LineEndingAnalyzer) Description() string {
+	return "виды переводов строки: \\n, \\r\\n и \\r"
+}
+func (l LineEndingAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"lf", "int", "lines"},
+		{"crlf", "int", "lines"},
+		{"cr", "int", "lines"},
+		{"mixed", "bool", ""},
+		{"examples", "[]LineExample", ""},
+	}
+}
+
+func (i IndentationAnalyzer) Description() string {
+	return "стиль и ширина отступов"
+}
+func (i IndentationAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"style", "string", ""},
+		{"width", "int", "chars"},
+		{"tab_lines", "int", "lines"},
+		{"space_lines", "int", "lines"},
+		{"mixed", "bool", ""},
+		{"violations", "int", "lines"},
+		{"examples", "[]LineExample", ""},
+	}
+}
+
+func (f FinalNewlineAnalyzer) Description() string {
+	return "оканчивается ли файл переводом строки"
+}
+func (f FinalNewlineAnalyzer) OutputSchema() []SchemaField { return scalar("bool", "") }
+
+func (m MarkupAnalyzer) Description() string {
+	return "теги HTML/XML: частоты, вложенность, незакрытые теги"
+}
+func (m MarkupAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"tags", "int", "tags"},
+		{"tag_counts", "map[string]int", "tags"},
+		{"max_depth", "int", ""},
+		{"unclosed", "int", "tags"},
+		{"text_ratio", "float64", ""},
+	}
+}
+
+func (m MarkdownBlockAnalyzer) Description() string {
+	return "структура markdown: блоки кода, заголовки по уровням, пункты списков"
+}
+func (m MarkdownBlockAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"code_blocks", "int", "blocks"},
+		{"headings", "[6]int", "headings"},
+		{"list_items", "int", "items"},
+		{"unclosed_fence", "bool", ""},
+	}
+}
+
+func (c CharCountAnalyzer) Description() string {
+	return "количество символов в файле"
+}
+func (c CharCountAnalyzer) OutputSchema() []SchemaField { return scalar("int", "chars") }
+
+func (l LicenseHeaderAnalyzer) Description() string {
+	return "лицензия и годы по заголовку файла"
+}
+func (l LicenseHeaderAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"license", "string", ""},
+		{"years_from", "int", "year"},
+		{"years_to", "int", "year"},
+	}
+}
+
+func (s SentenceAnalyzer) Description() string {
+	return "уникальные предложения файла"
+}
+func (s SentenceAnalyzer) OutputSchema() []SchemaField { return scalar("[]string", "") }
+
+func (s SentenceDiversityAnalyzer) Description() string {
+	return "доля уникальных предложений среди всех"
+}
+func (s SentenceDiversityAnalyzer) OutputSchema() []SchemaField { return scalar("float64", "ratio") }
+
+func (t TokenIndexAnalyzer) Description() string {
+	return "позиции каждого слова в файле"
+}
+func (t TokenIndexAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string][]int", "word positions")
+}
+
+func (p ParagraphHashAnalyzer) Description() string {
+	return "отпечатки абзацев для поиска похожих"
+}
+func (p ParagraphHashAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"index", "int", "paragraph"},
+		{"hash", "uint64", "simhash"},
+		{"preview", "string", ""},
+	}
+}
+
+func (m MinHashAnalyzer) Description() string {
+	return "подпись MinHash по шинглам из трёх слов для поиска почти одинаковых файлов"
+}
+func (m MinHashAnalyzer) OutputSchema() []SchemaField {
+	return scalar("[]uint32", "minhash")
+}
+
+func (p ParagraphLengthAnalyzer) Description() string {
+	return "число слов в каждом абзаце по порядку"
+}
+func (p ParagraphLengthAnalyzer) OutputSchema() []SchemaField {
+	return scalar("[]int", "words per paragraph")
+}
+
+func (s SummaryAnalyzer) Description() string {
+	return "самое характерное предложение файла"
+}
+func (s SummaryAnalyzer) OutputSchema() []SchemaField { return scalar("string", "") }
+
+func (g GeoMentionAnalyzer) Description() string {
+	return "упоминания стран и столиц"
+}
+func (g GeoMentionAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string]int", "mentions")
+}
+
+func (f FilteredFreqAnalyzer) Description() string {
+	return "частоты слов, прошедших фильтр Filter"
+}
+func (f FilteredFreqAnalyzer) OutputSchema() []SchemaField {
+	return scalar("map[string]int", "occurrences")
+}
+
+func (r ReadabilityAnalyzer) Description() string {
+	return "индекс удобочитаемости Флеша"
+}
+func (r ReadabilityAnalyzer) OutputSchema() []SchemaField { return scalar("float64", "score") }
+
+func (f FKGradeAnalyzer) Description() string         { return "уровень Флеша-Кинкейда" }
+func (f FKGradeAnalyzer) OutputSchema() []SchemaField { return scalar("float64", "grade") }
+
+func (e ExamplesAnalyzer) Description() string {
+	return "случайные примеры предложений для слов -top-words"
+}
+func (e ExamplesAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{{"file", "string", ""}, {"text", "string", ""}}
+}
+
+func (d DiffFromReferenceAnalyzer) Description() string {
+	return "пословное отличие от эталонного файла -diff-from-ref"
+}
+func (d DiffFromReferenceAnalyzer) OutputSchema() []SchemaField {
+	return []SchemaField{
+		{"added", "int", "words"},
+		{"removed", "int", "words"},
+		{"common", "int", "words"},
+		{"approximate", "bool", ""},
+	}
+}
+
+// Описание анализатора в манифесте JSON отчёта
+type AnalyzerInfo struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Schema      []SchemaField `json:"schema,omitempty"`
+	Config      []string      `json:"config,omitempty"`
+}
+
+func describeAnalyzer(a Analyzer) AnalyzerInfo {
+	if lazy, ok := a.(LazyAnalyzer); ok {
+		a = lazy.Analyzer
+	}
+	info := AnalyzerInfo{Name: a.Name(), Config: configKeys(a)}
+	if d, ok := a.(DescribedAnalyzer); ok {
+		info.Description = d.Description()
+		info.Schema = d.OutputSchema()
+	}
+	return info
+}
+
+// Манифест использованных анализаторов в порядке запуска
+func analyzerManifest(analyzers []Analyzer) []AnalyzerInfo {
+	infos := make([]AnalyzerInfo, len(analyzers))
+	for i, a := range analyzers {
+		infos[i] = describeAnalyzer(a)
+	}
+	return infos
+}
+
+// Настраиваемые параметры анализатора - его экспортируемые поля
+func configKeys(a Analyzer) []string {
+	t := reflect.TypeOf(a)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() {
+			keys = append(keys, fmt.Sprintf("%s %s", f.Name, f.Type))
+		}
+	}
+	return keys
+}
+
+// Текст, на котором describe показывает пример результата каждого анализатора
+const describeSample = `# MIT License, Copyright (c) 2020-2024
+The quick brown fox visits Paris. The API returns JSON for "HTTP requests".
+
+Second paragraph: the fox sleeps.
+`
+
+// Максимальная длина примера результата в выводе describe
+const describeExampleLen = 120
+
+// Подкоманда describe [анализатор]: описание, схема, параметры и пример результата
+// зарегистрированных анализаторов
+func runDescribe(args []string, out io.Writer) error {
+	names := registry.Names()
+	if len(args) > 1 {
+		return errors.New("describe принимает не больше одного имени анализатора")
+	}
+	if len(args) == 1 {
+		if _, ok := registry.Get(args[0]); !ok {
+			return fmt.Errorf("неизвестный анализатор %q, доступны: %s", args[0], strings.Join(names, ", "))
+		}
+		names = args
+	}
+	for _, name := range names {
+		a, _ := registry.Get(name)
+		writeDescription(out, describeAnalyzer(a), runAnalyzer(a, describeSample))
+	}
+	return nil
+}
+
+func writeDescription(out io.Writer, info AnalyzerInfo, example AnalysisResult) {
+	fmt.Fprintf(out, "%s: %s\n", info.Name, info.Description)
+	for _, f := range info.Schema {
+		if f.Unit != "" {
+			fmt.Fprintf(out, " %s %s (%s)\n", f.Name, f.Type, f.Unit)
+		} else {
+			fmt.Fprintf(out, " %s %s\n", f.Name, f.Type)
+		}
+	}
+	if len(info.Config) > 0 {
+		fmt.Fprintln(out, " параметры:", strings.Join(info.Config, ", "))
+	}
+	data, err := json.Marshal(example.Data)
+	if err != nil {
+		data = []byte(err.Error())
+	}
+	if s := string(data); utf8.RuneCountInString(s) > describeExampleLen {
+		data = append([]byte(string([]rune(s)[:describeExampleLen])), "..."...)
+	}
+	fmt.Fprintf(out, " пример: %s\n\n", data)
+}
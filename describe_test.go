@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisteredAnalyzersDescribed(t *testing.T) {
+	for _, name := range registry.Names() {
+		a, _ := registry.Get(name)
+		d, ok := a.(DescribedAnalyzer)
+		if !ok {
+			t.Errorf("%s: no Description/OutputSchema", name)
+			continue
+		}
+		if d.Description() == "" || len(d.OutputSchema()) == 0 {
+			t.Errorf("%s: empty metadata", name)
+		}
+	}
+	for _, a := range []Analyzer{CaseSensitiveFreqAnalyzer{}, ExamplesAnalyzer{}, DiffFromReferenceAnalyzer{}} {
+		if _, ok := a.(DescribedAnalyzer); !ok {
+			t.Errorf("%s: no Description/OutputSchema", a.Name())
+		}
+	}
+}
+
+func TestDescribeListsEachAnalyzerOnce(t *testing.T) {
+	var out bytes.Buffer
+	if err := runDescribe(nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	headers := make(map[string]int)
+	for _, line := range strings.Split(out.String(), "\n") {
+		if name, _, ok := strings.Cut(line, ": "); ok && !strings.HasPrefix(line, " ") {
+			headers[name]++
+		}
+	}
+	for _, name := range registry.Names() {
+		if headers[name] != 1 {
+			t.Errorf("expected %s exactly once, got %d", name, headers[name])
+		}
+	}
+	if len(headers) != len(registry.Names()) {
+		t.Errorf("expected %d analyzers, got %v", len(registry.Names()), headers)
+	}
+}
+
+func TestDescribeOne(t *testing.T) {
+	var out bytes.Buffer
+	if err := runDescribe([]string{"terms"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	for _, want := range []string{"terms: ", "параметры: MinLen int, MaxLen int, Exclude []string", `пример: {"API":1`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+	if err := runDescribe([]string{"no_such"}, &out); err == nil {
+		t.Error("expected an error for an unknown analyzer")
+	}
+}
+
+func TestReportAnalyzerManifest(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Analyzers: "word_count,density"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Analyzers) != 2 || report.Analyzers[0].Name != "word_count" || report.Analyzers[1].Name != "density" {
+		t.Fatalf("expected word_count and density in the manifest, got %+v", report.Analyzers)
+	}
+	if s := report.Analyzers[1].Schema; len(s) != 3 || s[0].Name != "mean_words_per_line" {
+		t.Errorf("unexpected density schema %+v", s)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Подкаталоги path для -include-dirs, сам path не включается.
+// Каталоги из -exclude пропускаются вместе с содержимым
+func listDirs(path string, exclude []string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || p == path {
+			return nil
+		}
+		if rel, err := filepath.Rel(path, p); err == nil && excluded(rel, exclude) {
+			return fs.SkipDir
+		}
+		dirs = append(dirs, p)
+		return nil
+	})
+	return dirs, err
+}
+
+// Запись каталога в отчёте: нулевой размер и без результатов анализаторов
+func dirResult(path string) (FileAnalysisResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return FileAnalysisResult{}, err
+	}
+	return FileAnalysisResult{
+		FileName: NormalizePath(filepath.Base(path)),
+		FilePath: NormalizePath(path),
+		ModTime:  info.ModTime(),
+		IsDir:    true,
+		Results:  []AnalysisResult{},
+	}, nil
+}
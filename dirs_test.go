@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunIncludeDirs(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "a.txt"), []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", IncludeDirs: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, f := range report.Files {
+		if f.FileName == "sub" {
+			found = true
+			if !f.IsDir || f.Size != 0 || len(f.Results) != 0 {
+				t.Errorf("expected zero-size directory entry without results, got %+v", f)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("expected sub directory in results, got %+v", report.Files)
+	}
+	if report.Summary.Files != 1 {
+		t.Errorf("directories should not count as files, got %d", report.Summary.Files)
+	}
+
+	out.Reset()
+	opts.Format = "text"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Каталог: sub") {
+		t.Errorf("expected directory line in text output:\n%s", out.String())
+	}
+}
+
+func TestListDirsExclude(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{"keep/inner", "vendor/pkg", "docs"} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs, err := listDirs(dir, parseExclude("vendor,docs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "keep"), filepath.Join(dir, "keep", "inner")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("expected %v, got %v", want, dirs)
+	}
+}
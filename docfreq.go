@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Документная частота: слово -> число файлов, в которых оно встречается
+type DocumentFrequency map[string]int
+
+// Учёт частот слов одного файла: каждое слово засчитывается один раз
+func (df DocumentFrequency) Add(freq map[string]int) {
+	for word, count := range freq {
+		if count > 0 {
+			df[word]++
+		}
+	}
+}
+
+// Слова, встречающиеся ровно в одном файле
+func (df DocumentFrequency) SingleFile() int {
+	n := 0
+	for _, files := range df {
+		if files == 1 {
+			n++
+		}
+	}
+	return n
+}
+
+type WordDocFreq struct {
+	Word  string `json:"word"`
+	Files int    `json:"files"`
+}
+
+// N слов с наибольшей документной частотой, при равенстве - по алфавиту
+func (df DocumentFrequency) Top(n int) []WordDocFreq {
+	words := make([]WordDocFreq, 0, len(df))
+	for w, files := range df {
+		words = append(words, WordDocFreq{w, files})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Files != words[j].Files {
+			return words[i].Files > words[j].Files
+		}
+		return words[i].Word < words[j].Word
+	})
+	if n < len(words) {
+		words = words[:n]
+	}
+	return words
+}
+
+// Сводка по документной частоте для отчёта
+type DocFreqReport struct {
+	SingleFileWords int           `json:"single_file_words"`
+	TotalWords      int           `json:"total_words"`
+	Words           []WordDocFreq `json:"words"`
+}
+
+func writeDocFreqText(out io.Writer, c colorizer, report DocFreqReport) {
+	fmt.Fprintf(out, "Документная частота слов (в одном файле: %d из %d):\n", report.SingleFileWords, report.TotalWords)
+	for i, w := range report.Words {
+		fmt.Fprintf(out, " %s: %d\n", c.rank(i, w.Word), w.Files)
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestDocumentFrequencyTop(t *testing.T) {
+	df := make(DocumentFrequency)
+	df.Add(map[string]int{"go": 3, "fast": 1})
+	df.Add(map[string]int{"go": 1, "slow": 2})
+	want := []WordDocFreq{{"go", 2}, {"fast", 1}}
+	if got := df.Top(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if n := df.SingleFile(); n != 2 {
+		t.Errorf("expected 2 single-file words, got %d", n)
+	}
+}
+
+func TestRunWordDocumentFrequency(t *testing.T) {
+	files := map[string]string{
+		"a.txt": "shared alpha alpha",
+		"b.txt": "shared shared beta",
+	}
+	dir := testutil.CreateTempDir(t, files)
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", WordDocFreq: 10}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Summary SummaryReport `json:"summary"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	df := report.Summary.DocumentFrequency
+	if df == nil {
+		t.Fatal("expected document_frequency in summary")
+	}
+	got := make(map[string]int)
+	for _, w := range df.Words {
+		got[w.Word] = w.Files
+	}
+	if got["shared"] != 2 {
+		t.Errorf("expected \"shared\" in 2 files, got %d", got["shared"])
+	}
+	if got["alpha"] != 1 || got["beta"] != 1 {
+		t.Errorf("expected unique words in 1 file, got %v", got)
+	}
+	if df.SingleFileWords != 2 || df.TotalWords != 3 {
+		t.Errorf("expected 2 of 3 single-file words, got %d of %d", df.SingleFileWords, df.TotalWords)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// Режим -dump-tokens: поток токенов одного файла в том виде, в каком его видят
+// word_count и most_frequent_words, с учётом -preview-bytes, -normalize,
+// -max-token-length, -max-line-length, -case-sensitive, -locale и -min-word-len.
+// Строка вывода: номер, токен, ключ частотного словаря и "short", если
+// токен короче -min-word-len и не считается в word_count
+func dumpTokens(out io.Writer, opts Options) error {
+	info, err := os.Stat(opts.Path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("-dump-tokens работает с одним файлом, %s - каталог", opts.Path)
+	}
+	read := readSource
+	if opts.PreviewBytes > 0 {
+		read = func(path string) (fileContent, error) {
+			return readPrefix(path, opts.PreviewBytes)
+		}
+	}
+	fc, err := read(opts.Path)
+	if err != nil {
+		return err
+	}
+	normalize, err := unicodeNormalizer(opts.Normalize)
+	if err != nil {
+		return err
+	}
+
+	// тот же конвейер, что у воркеров, последним звеном забирается итоговый текст
+	var content string
+	capture := func(text string, _ []Analyzer) []AnalysisResult {
+		content = text
+		return nil
+	}
+	normalizingAnalyzer(truncatingAnalyzer(capture, opts.MaxTokenLength, opts.MaxLineLength), normalize)(fc.Text, nil)
+
+	key := frequencyKey(opts)
+	for i, tok := range strings.Fields(content) {
+		line := fmt.Sprintf("%d\t%s\t%s", i+1, tok, key(tok))
+		if opts.MinWordLength > 1 && utf8.RuneCountInString(tok) < opts.MinWordLength {
+			line += "\tshort"
+		}
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestDumpTokens(t *testing.T) {
+	// "ﬁ" - лигатура: NFKC и свёртка регистра с -locale раскладывают её в "fi".
+	// -max-token-length считается в байтах, "İ" занимает два
+	file := testutil.CreateTempFile(t, "Go is ﬁne\nİstanbul a supercalifragilistic")
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"default", Options{}, "1\tGo\tgo\n2\tis\tis\n3\tﬁne\tﬁne\n4\tİstanbul\tistanbul\n5\ta\ta\n6\tsupercalifragilistic\tsupercalifragilistic\n"},
+		{"case sensitive", Options{CaseSensitive: true, Normalize: "nfkc"}, "1\tGo\tGo\n2\tis\tis\n3\tfine\tfine\n4\tİstanbul\tİstanbul\n5\ta\ta\n6\tsupercalifragilistic\tsupercalifragilistic\n"},
+		{"turkish, short and truncated", Options{Locale: "tr", MinWordLength: 2, MaxTokenLength: 5}, "1\tGo\tgo\n2\tis\tis\n3\tﬁne\tfine\n4\tİsta\tista\n5\ta\ta\tshort\n6\tsuper\tsuper\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			tt.opts.Path, tt.opts.DumpTokens, tt.opts.Workers = file, true, 1
+			if err := run(context.Background(), tt.opts, &out); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("expected\n%q\ngot\n%q", tt.want, out.String())
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	err := run(context.Background(), Options{Path: t.TempDir(), Workers: 1, DumpTokens: true}, &out)
+	if err == nil || !strings.Contains(err.Error(), "-dump-tokens") {
+		t.Errorf("expected error for a directory, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import "os"
+
+// Разделение файлов на непустые и файлы нулевого размера для -report-empty.
+// Файлы, которые не удалось прочитать через Stat, остаются в списке анализа,
+// чтобы ошибка появилась там же, где и для остальных файлов. FIFO всегда нулевого
+// размера, поэтому в пустые не попадает
+func splitEmpty(files []string) (nonEmpty, empty []string) {
+	nonEmpty = files[:0:0]
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil && info.Size() == 0 && info.Mode().IsRegular() {
+			empty = append(empty, NormalizePath(f))
+			continue
+		}
+		nonEmpty = append(nonEmpty, f)
+	}
+	return nonEmpty, empty
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestRunReportEmpty(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"empty.txt": "",
+		"full.txt":  "some words here",
+	})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", ReportEmpty: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Summary.EmptyFiles) != 1 || !strings.HasSuffix(report.Summary.EmptyFiles[0], "/empty.txt") {
+		t.Errorf("expected empty.txt in empty_files, got %v", report.Summary.EmptyFiles)
+	}
+	for _, f := range report.Files {
+		if f.FileName == "empty.txt" {
+			t.Errorf("empty file was analyzed: %+v", f)
+		}
+	}
+	if len(report.Files) != 1 || report.Files[0].FileName != "full.txt" {
+		t.Errorf("expected only full.txt to be analyzed, got %v", report.Files)
+	}
+}
+
+func TestRunReportEmptyText(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"empty.txt": ""})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "text", Color: "never", ReportEmpty: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.Contains(got, "Пустые файлы:") || strings.Contains(got, "не найдены") {
+		t.Errorf("unexpected text output:\n%s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Количество строк, которые изменятся при приведении переводов строк к LF
+func eolChanges(content string) int {
+	e := LineEndingAnalyzer{}.Analyze(content).Data.(LineEndings)
+	return e.CRLF + e.CR
+}
+
+// Приведение всех переводов строк к LF
+func normalizeEOL(content string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(content, "\r\n", "\n"), "\r", "\n")
+}
+
+// Перезапись файла с LF переводами строк; оригинал сохраняется в path.bak.
+// Существующая резервная копия не перезаписывается.
+func fixEOL(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	backup := path + ".bak"
+	if _, err := os.Lstat(backup); err == nil {
+		return fmt.Errorf("резервная копия %s уже существует", backup)
+	}
+	if err := os.WriteFile(backup, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	// запись через временный файл, чтобы сбой не оставил файл наполовину записанным
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".eol-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(normalizeEOL(string(data))); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Режим -normalize-eol: отчёт о строках, которые изменятся, и при fix - перезапись файлов
+func normalizeEOLReport(out io.Writer, c colorizer, files []string, fix bool) error {
+	total := 0
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		n := eolChanges(string(data))
+		if n == 0 {
+			continue
+		}
+		total += n
+		if fix {
+			if err := fixEOL(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s: исправлено строк: %d\n", c.name(path), n)
+		} else {
+			fmt.Fprintf(out, "%s: изменится строк: %d\n", c.name(path), n)
+		}
+	}
+	fmt.Fprintf(out, "\nTOTAL: %d lines\n", total)
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEOLReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "crlf.txt")
+	original := "one\r\ntwo\r\nthree\r\n"
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, NormalizeEOL: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), path+": изменится строк: 3") {
+		t.Errorf("expected 3 lines to change, got:\n%s", out.String())
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != original {
+		t.Error("file modified without -fix")
+	}
+
+	opts.Fix = true
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != "one\ntwo\nthree\n" {
+		t.Errorf("expected LF-only content, got %q", data)
+	}
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil || string(backup) != original {
+		t.Errorf("expected original content in backup, got %q (%v)", backup, err)
+	}
+}
+
+func TestFixEOLKeepsExistingBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	os.WriteFile(path, []byte("a\r\n"), 0o644)
+	os.WriteFile(path+".bak", []byte("older"), 0o644)
+
+	if err := fixEOL(path); err == nil {
+		t.Error("expected error when backup exists")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "a\r\n" {
+		t.Error("file rewritten despite backup conflict")
+	}
+}
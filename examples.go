@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Пример употребления слова: предложение и файл, из которого оно взято
+type WordExample struct {
+	File string `json:"file"`
+	Text string `json:"text"`
+}
+
+// Выборка примеров слова методом bottom-k: остаются n примеров с наименьшим
+// приоритетом, приоритет - хеш зерна, слова и предложения. Слияние выборок
+// не зависит от порядка, поэтому файлы добавляются сразу по мере готовности
+type wordReservoir struct {
+	items []sampledExample // по возрастанию приоритета, не больше n
+}
+
+type sampledExample struct {
+	WordExample
+	priority uint64
+}
+
+func (a sampledExample) less(b sampledExample) bool {
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	return a.Text < b.Text
+}
+
+func (r *wordReservoir) add(ex sampledExample, n int) {
+	i := sort.Search(len(r.items), func(i int) bool { return !r.items[i].less(ex) })
+	if i >= n || (i < len(r.items) && r.items[i] == ex) {
+		return
+	}
+	r.items = slices.Insert(r.items, i, ex)
+	if len(r.items) > n {
+		r.items = r.items[:n]
+	}
+}
+
+// Приоритет примера: одинаков для одного предложения в любом файле и при любом порядке
+func examplePriority(seed int64, word, text string) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d\x00%s\x00%s", seed, word, text)
+	// перемешивание splitmix64, как в simHash
+	sum := h.Sum64()
+	sum = (sum ^ sum>>30) * 0xbf58476d1ce4e5b9
+	sum = (sum ^ sum>>27) * 0x94d049bb133111eb
+	return sum ^ sum>>31
+}
+
+// Во сколько раз больше -top-words слов файла хранят примеры: слово из общего топа
+// обычно входит в топ файла с запасом
+const exampleCandidates = 4
+
+// Анализатор примеров: для Candidates самых частых слов файла до N предложений
+// с этим словом, 0 - для всех слов. Слова приводятся к виду как в most_frequent_words,
+// чтобы совпадать с -top-words. Результат нужен только для сводки и не попадает в вывод файлов
+type ExamplesAnalyzer struct {
+	N             int
+	Candidates    int
+	Seed          int64
+	CaseSensitive bool
+	Locale        string
+}
+
+func (e ExamplesAnalyzer) Name() string {
+	return "word_examples"
+}
+func (e ExamplesAnalyzer) Analyze(content string) AnalysisResult {
+	key := strings.ToLower
+	if e.CaseSensitive {
+		key = func(s string) string { return s }
+	} else if e.Locale != "" {
+		key = newCaseFolder(e.Locale).Fold
+	}
+	sentences := splitSentences(content)
+	counts := make(map[string]int)
+	for _, sentence := range sentences {
+		for _, tok := range strings.Fields(sentence) {
+			counts[key(tok)]++
+		}
+	}
+	// примеры хранятся только для слов, которые могут попасть в -top-words
+	if e.Candidates > 0 && len(counts) > e.Candidates {
+		kept := make(map[string]int, e.Candidates)
+		for _, w := range topWords(counts, e.Candidates) {
+			kept[w.Word] = w.Count
+		}
+		counts = kept
+	}
+
+	words := make(map[string]*wordReservoir)
+	for _, sentence := range sentences {
+		text := paragraphPreview(sentence)
+		for _, tok := range strings.Fields(sentence) {
+			w := key(tok)
+			if _, ok := counts[w]; !ok {
+				continue
+			}
+			if words[w] == nil {
+				words[w] = &wordReservoir{}
+			}
+			words[w].add(sampledExample{WordExample{Text: text}, examplePriority(e.Seed, w, text)}, e.N)
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: e.Name(),
+		Data:         words,
+	}
+}
+
+// Выборка примеров по всему корпусу. Файл вливается в неё сразу после анализа,
+// от файла остаются только отобранные примеры
+type exampleSampler struct {
+	n     int
+	words map[string]*wordReservoir
+}
+
+func newExampleSampler(n int) *exampleSampler {
+	return &exampleSampler{n: n, words: make(map[string]*wordReservoir)}
+}
+
+func (s *exampleSampler) add(file string, words map[string]*wordReservoir) {
+	for w, r := range words {
+		if s.words[w] == nil {
+			s.words[w] = &wordReservoir{}
+		}
+		for _, ex := range r.items {
+			ex.File = file
+			s.words[w].add(ex, s.n)
+		}
+	}
+}
+
+// Примеры для слов из top
+func (s *exampleSampler) examples(top []WordCount) map[string][]WordExample {
+	out := make(map[string][]WordExample)
+	for _, w := range top {
+		if r := s.words[w.Word]; r != nil && len(r.items) > 0 {
+			for _, ex := range r.items {
+				out[w.Word] = append(out[w.Word], ex.WordExample)
+			}
+		}
+	}
+	return out
+}
+
+// Изъятие результата word_examples из результатов файла.
+// Срез копируется: memo делит его между файлами с одинаковым содержимым
+func takeExamples(res *FileAnalysisResult) (map[string]*wordReservoir, bool) {
+	for i, r := range res.Results {
+		if words, ok := r.Data.(map[string]*wordReservoir); ok && r.NameAnalyzer == "word_examples" {
+			rest := make([]AnalysisResult, 0, len(res.Results)-1)
+			rest = append(rest, res.Results[:i]...)
+			res.Results = append(rest, res.Results[i+1:]...)
+			return words, true
+		}
+	}
+	return nil, false
+}
+
+func writeExamplesText(out io.Writer, c colorizer, examples []WordExample) {
+	for _, ex := range examples {
+		fmt.Fprintf(out, "   %s: %q\n", c.name(ex.File), ex.Text)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunExamples(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.txt": "Go is fun. Rust is fast.",
+		"b.txt": "Go compiles quickly. Nothing else here.",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", TopWords: 2, Examples: 2}
+	var out bytes.Buffer
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	// go встречается в двух предложениях, is - в двух, выборка забирает все
+	goExamples := report.Summary.Examples["go"]
+	if len(goExamples) != 2 {
+		t.Fatalf("expected 2 examples for go, got %+v", report.Summary.Examples)
+	}
+	texts := map[string]string{}
+	for _, ex := range goExamples {
+		texts[filepath.Base(ex.File)] = ex.Text
+	}
+	if want := map[string]string{"a.txt": "Go is fun.", "b.txt": "Go compiles quickly."}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("expected %v, got %v", want, texts)
+	}
+	for _, f := range report.Files {
+		for _, r := range f.Results {
+			if r.NameAnalyzer == "word_examples" {
+				t.Errorf("word_examples should not appear in file results: %s", f.FileName)
+			}
+		}
+	}
+
+	out.Reset()
+	opts.Format, opts.Color = "text", "never"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Количество слов \"go\": 2\n   ") || !strings.Contains(out.String(), `: "Go is fun."`) {
+		t.Errorf("expected indented examples under top word, got:\n%s", out.String())
+	}
+
+	opts.TopWords = 0
+	if err := run(context.Background(), opts, &out); err == nil {
+		t.Error("expected error for -examples without -top-words")
+	}
+}
+
+// Без -deterministic выборка тоже не зависит от порядка прихода файлов от воркеров
+func TestRunExamplesIndependentOfArrival(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 30; i++ {
+		content := fmt.Sprintf("Go file %d first. Go file %d second. Go file %d third.", i, i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%02d.txt", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sample := func() []WordExample {
+		opts := Options{Path: dir, Ext: ".txt", Workers: 8, Format: "json", TopWords: 1, Examples: 3, Seed: 7}
+		var out bytes.Buffer
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		return report.Summary.Examples["go"]
+	}
+	first := sample()
+	for i := 0; i < 5; i++ {
+		if got := sample(); !reflect.DeepEqual(got, first) {
+			t.Fatalf("expected the same examples for the same seed, got %v and %v", first, got)
+		}
+	}
+}
+
+func TestExampleSamplerBoundedAndSeeded(t *testing.T) {
+	sample := func(seed int64, reverse bool) []WordExample {
+		s := newExampleSampler(3)
+		for k := 0; k < 20; k++ {
+			i := k
+			if reverse {
+				i = 19 - k
+			}
+			var b strings.Builder
+			for j := 0; j < 10; j++ {
+				fmt.Fprintf(&b, "word line%d-%d. ", i, j)
+			}
+			res := runAnalyzer(ExamplesAnalyzer{N: 3, Seed: seed}, b.String())
+			words := res.Data.(map[string]*wordReservoir)
+			if r := words["word"]; len(r.items) != 3 {
+				t.Fatalf("per-file reservoir should keep 3 of 10, got %d", len(r.items))
+			}
+			s.add(fmt.Sprintf("f%d.txt", i), words)
+		}
+		for w, r := range s.words {
+			if len(r.items) > 3 {
+				t.Fatalf("word %q retains %d examples, bound is 3", w, len(r.items))
+			}
+		}
+		return s.examples([]WordCount{{"word", 200}})["word"]
+	}
+	first := sample(7, false)
+	if len(first) != 3 || !reflect.DeepEqual(first, sample(7, false)) {
+		t.Errorf("expected the same 3 examples for the same seed, got %v", first)
+	}
+	if got := sample(7, true); !reflect.DeepEqual(got, first) {
+		t.Errorf("expected the same examples in reverse file order, got %v and %v", first, got)
+	}
+}
+
+func TestExamplesAnalyzerCandidates(t *testing.T) {
+	res := runAnalyzer(ExamplesAnalyzer{N: 2, Candidates: 1}, "go go rust. go python.")
+	words := res.Data.(map[string]*wordReservoir)
+	if len(words) != 1 || words["go"] == nil {
+		t.Errorf("expected examples only for the most frequent word, got %v", words)
+	}
+}
+
+func TestWordReservoirUnbiased(t *testing.T) {
+	// из 10 примеров файла a и 30 примеров файла b остаётся один: доля a около 1/4
+	fromA := 0
+	const runs = 4000
+	for seed := int64(0); seed < runs; seed++ {
+		s := newExampleSampler(1)
+		for _, f := range []struct {
+			name string
+			n    int
+		}{{"a", 10}, {"b", 30}} {
+			r := &wordReservoir{}
+			for i := 0; i < f.n; i++ {
+				text := fmt.Sprintf("%s sentence %d", f.name, i)
+				r.add(sampledExample{WordExample{Text: text}, examplePriority(seed, "w", text)}, 1)
+			}
+			s.add(f.name, map[string]*wordReservoir{"w": r})
+		}
+		if s.words["w"].items[0].File == "a" {
+			fromA++
+		}
+	}
+	if share := float64(fromA) / runs; share < 0.22 || share > 0.28 {
+		t.Errorf("expected about 25%% of examples from a, got %.1f%%", share*100)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Шаблоны -exclude через запятую.
+// Разделители приводятся к "/", поэтому "logs\*.txt" и "logs/*.txt" равнозначны на любой ОС
+func parseExclude(s string) []string {
+	var patterns []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, strings.ReplaceAll(p, `\`, "/"))
+		}
+	}
+	return patterns
+}
+
+// Путь rel (относительно корня обхода) совпадает с шаблоном целиком,
+// по имени файла или по одному из родительских каталогов
+func excluded(rel string, patterns []string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, path.Base(rel)); ok {
+			return true
+		}
+		for dir := rel; dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if ok, _ := path.Match(p, dir); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Имена устройств Windows: открытие CON или NUL читает консоль
+// или устройство вместо файла, и обход может зависнуть
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Зарезервированное имя с любым расширением: "nul", "con.txt", "COM1.log"
+func isWindowsReservedName(name string) bool {
+	base, _, _ := strings.Cut(name, ".")
+	return windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))]
+}
+
+// Пропуск устройств при обходе, на других ОС такие имена обычные
+func skipReservedName(name string) bool {
+	return runtime.GOOS == "windows" && isWindowsReservedName(name)
+}
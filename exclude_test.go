@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestExcludedSeparatorAgnostic(t *testing.T) {
+	cases := []struct {
+		pattern string
+		rel     string
+		want    bool
+	}{
+		{"logs/*.txt", "logs/a.txt", true},
+		{`logs\*.txt`, "logs/a.txt", true},
+		{"logs", "logs/sub/a.txt", true},
+		{"*.bak", "deep/dir/old.bak", true},
+		{"logs/*.txt", "other/a.txt", false},
+		{"logs/*.txt", "logs/sub/a.txt", false},
+	}
+	for _, c := range cases {
+		rel := filepath.FromSlash(c.rel)
+		if got := excluded(rel, parseExclude(c.pattern)); got != c.want {
+			t.Errorf("pattern %q, path %q: expected %v, got %v", c.pattern, rel, c.want, got)
+		}
+	}
+}
+
+func TestWalkFilesExclude(t *testing.T) {
+	root := testutil.CreateTempDir(t, map[string]string{
+		"keep.txt":     "a",
+		"tmp/drop.txt": "b",
+		"notes.bak":    "c",
+	})
+	got, _, err := walkFiles(context.Background(), root, "", 0, 0, parseExclude(`tmp, *.bak`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(root, "keep.txt")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	// исключённый каталог не обходится: его нечитаемость не попадает в отчёт
+	private := filepath.Join(root, "tmp")
+	if err := os.Chmod(private, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(private, 0o755)
+	if _, err := os.ReadDir(private); err == nil {
+		t.Skip("directory mode does not restrict reading (root or no Unix permissions)")
+	}
+	if _, unreadable, err := walkFiles(context.Background(), root, "", 0, 0, parseExclude("tmp")); err != nil || len(unreadable) != 0 {
+		t.Errorf("expected excluded directory to be pruned, got %v and %v", unreadable, err)
+	}
+}
+
+func TestWalkFilesCancelled(t *testing.T) {
+	root := testutil.CreateTempDir(t, map[string]string{"a.txt": "a", "b.txt": "b"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := walkFiles(ctx, root, ".txt", 0, 0, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWindowsReservedNames(t *testing.T) {
+	for _, name := range []string{"CON", "nul", "con.txt", "COM1.log", "lpt9", "AUX .txt"} {
+		if !isWindowsReservedName(name) {
+			t.Errorf("%q should be reserved", name)
+		}
+	}
+	for _, name := range []string{"console.txt", "a.txt", "COM10", "nul_file.txt"} {
+		if isWindowsReservedName(name) {
+			t.Errorf("%q should not be reserved", name)
+		}
+	}
+}
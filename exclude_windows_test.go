@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+import "testing"
+
+func TestExcludedWindowsPaths(t *testing.T) {
+	if !excluded(`logs\2024\app.log`, parseExclude(`logs\2024\*.log`)) {
+		t.Error("backslash pattern should match backslash path")
+	}
+	if !excluded(`logs\2024\app.log`, parseExclude(`logs/2024/*.log`)) {
+		t.Error("slash pattern should match backslash path")
+	}
+	if !skipReservedName("NUL") {
+		t.Error("reserved device names should be skipped during traversal on Windows")
+	}
+}
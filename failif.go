@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Условие провала запуска вида "license_none>0"
+type FailCondition struct {
+	Metric string
+	Op     string
+	Value  int
+}
+
+// Сработавшее условие -fail-if, main завершает программу с ненулевым кодом
+type FailConditionError struct {
+	Condition FailCondition
+	Actual    int
+}
+
+func (e *FailConditionError) Error() string {
+	c := e.Condition
+	return fmt.Sprintf("условие -fail-if сработало: %s%s%d (значение %d)", c.Metric, c.Op, c.Value, e.Actual)
+}
+
+// Разбор списка условий через запятую
+func parseFailIf(s string) ([]FailCondition, error) {
+	var conds []FailCondition
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.IndexAny(part, "<>=")
+		if i <= 0 {
+			return nil, fmt.Errorf("неверное условие -fail-if %q", part)
+		}
+		op := part[i:]
+		rest := strings.TrimLeft(op, "<>=")
+		op = op[:len(op)-len(rest)]
+		switch op {
+		case ">", ">=", "<", "<=", "=", "==":
+		default:
+			return nil, fmt.Errorf("неверный оператор в условии -fail-if %q", part)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("неверное значение в условии -fail-if %q", part)
+		}
+		metric := strings.TrimSpace(part[:i])
+		if _, ok := summaryMetrics(SummaryReport{})[metric]; !ok {
+			return nil, unknownMetricError(metric)
+		}
+		conds = append(conds, FailCondition{metric, op, value})
+	}
+	return conds, nil
+}
+
+func unknownMetricError(metric string) error {
+	var known []string
+	for name := range summaryMetrics(SummaryReport{}) {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+	return fmt.Errorf("неизвестная метрика -fail-if %q, доступны: %s", metric, strings.Join(known, ", "))
+}
+
+// Метрики сводки, доступные для -fail-if
+func summaryMetrics(s SummaryReport) map[string]int {
+	return map[string]int{
+		"files":              s.Files,
+		"failed_files":       len(s.FailedFiles),
+		"skipped_permission": s.SkippedPermission,
+		"vanished_files":     len(s.VanishedFiles),
+		"density_outliers":   len(s.DensityOutliers),
+		"license_none":       len(s.Licenses["none"]),
+		"type_mismatches":    len(s.TypeMismatches),
+		"indentation_mixed":  len(s.IndentationStyles["mixed"]),
+	}
+}
+
+// Флаг, включающий анализатор метрики, и включён ли он
+func failIfRequirement(metric string, opts Options) (flagName string, ok bool) {
+	switch {
+	case strings.HasPrefix(metric, "license_"):
+		return "-license", opts.License
+	case metric == "density_outliers":
+		return "-density", opts.Density
+	case metric == "type_mismatches":
+		return "-content-type", opts.ContentType
+	}
+	return "", true
+}
+
+// Проверка условий, возвращает *FailConditionError для первого сработавшего
+func checkFailIf(conds []FailCondition, metrics map[string]int) error {
+	for _, c := range conds {
+		actual, ok := metrics[c.Metric]
+		if !ok {
+			return unknownMetricError(c.Metric)
+		}
+		var hit bool
+		switch c.Op {
+		case ">":
+			hit = actual > c.Value
+		case ">=":
+			hit = actual >= c.Value
+		case "<":
+			hit = actual < c.Value
+		case "<=":
+			hit = actual <= c.Value
+		case "=", "==":
+			hit = actual == c.Value
+		}
+		if hit {
+			return &FailConditionError{c, actual}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Колонка вывода -fields и -format csv.
+// Analyzer - имя анализатора, результат которого нужен колонке
+type outputField struct {
+	Name     string
+	Analyzer string
+	Value    func(res FileAnalysisResult) string
+}
+
+func fileField(name string, value func(FileAnalysisResult) string) outputField {
+	return outputField{Name: name, Value: value}
+}
+
+// Колонка из результата анализатора, пусто если анализатор не запускался
+func analyzerField(name, analyzer string, format func(any) string) outputField {
+	return outputField{name, analyzer, func(res FileAnalysisResult) string {
+		for _, r := range res.Results {
+			if r.NameAnalyzer == analyzer {
+				return format(resolveResult(r).Data)
+			}
+		}
+		return ""
+	}}
+}
+
+func formatAny(v any) string {
+	return fmt.Sprint(v)
+}
+
+var outputFields = []outputField{
+	fileField("name", func(res FileAnalysisResult) string { return res.FileName }),
+	fileField("path", func(res FileAnalysisResult) string { return res.FilePath }),
+	fileField("size", func(res FileAnalysisResult) string { return strconv.FormatInt(res.Size, 10) }),
+	fileField("mod_time", func(res FileAnalysisResult) string { return res.ModTime.Format(time.RFC3339) }),
+	fileField("hash", func(res FileAnalysisResult) string { return res.ContentHash }),
+	analyzerField("words", "word_count", formatAny),
+	analyzerField("lines", "line_count", formatAny),
+	analyzerField("chars", "char_count", formatAny),
+	analyzerField("density", "density", func(v any) string {
+		return strconv.FormatFloat(v.(DensityStats).MeanWordsPerLine, 'f', 2, 64)
+	}),
+	analyzerField("language", "language", formatAny),
+	analyzerField("type", "type", formatAny),
+	analyzerField("script", "script_language", formatAny),
+	analyzerField("quotes", "quotes", func(v any) string { return strconv.Itoa(v.(QuoteStats).Count) }),
+	analyzerField("final_newline", "has_final_newline", formatAny),
+	analyzerField("indentation", "indentation", func(v any) string { return v.(Indentation).Style }),
+}
+
+// Колонки CSV, если -fields не задан
+const defaultCSVFields = "name,path,size,words,lines"
+
+// Разбор списка колонок через запятую
+func parseFields(s string) ([]outputField, error) {
+	var fields []outputField
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, f := range outputFields {
+			if f.Name == name {
+				fields = append(fields, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var known []string
+			for _, f := range outputFields {
+				known = append(known, f.Name)
+			}
+			return nil, fmt.Errorf("неизвестное поле %q, доступны: %s", name, strings.Join(known, ", "))
+		}
+	}
+	return fields, nil
+}
+
+// Анализаторы колонок, которых нет в наборе по умолчанию (например char_count),
+// добавляются к analyzers из реестра. Колонки анализаторов по умолчанию,
+// отключённых через -analyze или -analyzers, остаются пустыми
+func withFieldAnalyzers(analyzers []Analyzer, fields []outputField, opts Options) []Analyzer {
+	skip := make(map[string]bool)
+	for _, a := range defaultAnalyzers(opts) {
+		skip[a.Name()] = true
+	}
+	for _, a := range analyzers {
+		skip[a.Name()] = true
+	}
+	for _, f := range fields {
+		if f.Analyzer == "" || skip[f.Analyzer] {
+			continue
+		}
+		if a, ok := registry.Get(f.Analyzer); ok {
+			analyzers = append(analyzers, a)
+			skip[f.Analyzer] = true
+		}
+	}
+	return analyzers
+}
+
+// Строка текстового вывода файла только с выбранными полями
+func writeFieldsText(out io.Writer, c colorizer, fields []outputField, res FileAnalysisResult) {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Name + ": " + c.name(f.Value(res))
+	}
+	fmt.Fprintln(out, strings.Join(parts, ", "))
+}
+
+// Вывод результатов в CSV с заголовком
+func writeCSV(out io.Writer, fields []outputField, results []FileAnalysisResult) error {
+	w := csv.NewWriter(out)
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		row[i] = f.Name
+	}
+	w.Write(row)
+	for _, res := range results {
+		for i, f := range fields {
+			row[i] = f.Value(res)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return w.Error()
+}
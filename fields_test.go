@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRunCSVFields(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world\nhello go"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "csv", Fields: "lines,name,words"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"lines", "name", "words"}, {"2", "a.txt", "4"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("expected %v, got %v", want, rows)
+	}
+}
+
+func TestRunTextFields(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Fields: "name,words"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "name: a.txt, words: 2\n") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestParseFieldsUnknown(t *testing.T) {
+	_, err := parseFields("name,bogus")
+	if err == nil || !strings.Contains(err.Error(), "bogus") || !strings.Contains(err.Error(), "words") {
+		t.Errorf("expected error listing valid fields, got %v", err)
+	}
+}
+
+func TestRunCSVColumns(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("привет мир\nhello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		columns string
+		want    [][]string
+	}{
+		{"name,size,words,lines,chars", [][]string{{"name", "size", "words", "lines", "chars"}, {"a.txt", "25", "3", "2", "16"}}},
+		{"chars,name", [][]string{{"chars", "name"}, {"16", "a.txt"}}},
+		{"lines", [][]string{{"lines"}, {"2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.columns, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "csv", CSVColumns: tt.columns}
+			if err := run(context.Background(), opts, &out); err != nil {
+				t.Fatal(err)
+			}
+			rows, err := csv.NewReader(&out).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, rows)
+			}
+		})
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "csv", CSVColumns: "name,bytes"}
+	if err := run(context.Background(), opts, &out); err == nil || !strings.Contains(err.Error(), "bytes") {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
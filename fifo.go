@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Сколько ждать писателя и данных при чтении FIFO в режиме -fifo read
+var fifoReadTimeout = 5 * time.Second
+
+// Проверка значения -fifo
+func validFIFOPolicy(s string) error {
+	switch s {
+	case "", "skip", "read":
+		return nil
+	}
+	return fmt.Errorf("неизвестный режим -fifo %q, ожидается skip или read", s)
+}
+
+func isFIFO(info fs.FileInfo) bool {
+	return info.Mode()&fs.ModeNamedPipe != 0
+}
+
+func isFIFOPath(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && isFIFO(info)
+}
+
+// Удаление именованных каналов из списка файлов: os.ReadFile на FIFO
+// без писателя блокируется навсегда
+func skipFIFOs(files []string) []string {
+	out := files[:0:0]
+	for _, f := range files {
+		if isFIFOPath(f) {
+			slog.Warn("пропущен именованный канал", "path", f)
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
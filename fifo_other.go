@@ -0,0 +1,13 @@
+//go:build !unix
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// На других ОС именованных каналов в файловой системе нет
+func readFIFO(path string, timeout time.Duration) ([]byte, error) {
+	return nil, fmt.Errorf("%s: чтение именованных каналов не поддерживается", path)
+}
@@ -0,0 +1,55 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Чтение FIFO как потока с ограничением времени.
+// Открытие FIFO блокируется до появления писателя; по таймауту открытие
+// снимается собственным писателем, чтобы не оставлять зависшую горутину
+func readFIFO(path string, timeout time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	type opened struct {
+		f   *os.File
+		err error
+	}
+	ch := make(chan opened, 1)
+	go func() {
+		f, err := os.Open(path)
+		ch <- opened{f, err}
+	}()
+
+	var f *os.File
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return nil, r.err
+		}
+		f = r.f
+	case <-time.After(timeout):
+		if w, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0); err == nil {
+			w.Close()
+		}
+		if r := <-ch; r.f != nil {
+			r.f.Close()
+		}
+		return nil, fmt.Errorf("%s: нет писателя в именованном канале за %v", path, timeout)
+	}
+	defer f.Close()
+
+	if err := f.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return nil, fmt.Errorf("%s: чтение именованного канала не завершилось за %v", path, timeout)
+	}
+	return data, err
+}
@@ -0,0 +1,116 @@
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func fifoDir(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("regular file words"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pipe := filepath.Join(dir, "pipe.txt")
+	if err := syscall.Mkfifo(pipe, 0o644); err != nil {
+		t.Skip("mkfifo not supported:", err)
+	}
+	return dir, pipe
+}
+
+func runFIFO(t *testing.T, opts Options) (Report, error) {
+	t.Helper()
+	var out bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- run(context.Background(), opts, &out) }()
+	select {
+	case err := <-done:
+		var report Report
+		if err == nil {
+			if jerr := json.Unmarshal(out.Bytes(), &report); jerr != nil {
+				t.Fatal(jerr)
+			}
+		}
+		return report, err
+	case <-time.After(10 * time.Second):
+		t.Fatal("run hung on a FIFO")
+	}
+	return Report{}, nil
+}
+
+func TestRunSkipsFIFO(t *testing.T) {
+	dir, _ := fifoDir(t)
+	report, err := runFIFO(t, Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 || report.Files[0].FileName != "a.txt" {
+		t.Errorf("expected only a.txt, got %+v", report.Files)
+	}
+}
+
+func TestRunReadsFIFO(t *testing.T) {
+	dir, pipe := fifoDir(t)
+	go func() {
+		f, err := os.OpenFile(pipe, os.O_WRONLY, 0)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		f.WriteString("streamed through the pipe")
+		f.Close()
+	}()
+	report, err := runFIFO(t, Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", FIFO: "read"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var words int
+	for _, f := range report.Files {
+		if f.FileName == "pipe.txt" {
+			words, _ = numericResult(f, "word_count")
+		}
+	}
+	if words != 4 {
+		t.Errorf("expected 4 words read from the FIFO, got %d in %+v", words, report.Files)
+	}
+}
+
+func TestReadFIFOTimeout(t *testing.T) {
+	_, pipe := fifoDir(t)
+	start := time.Now()
+	_, err := readFIFO(pipe, 100*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "нет писателя") {
+		t.Errorf("expected no-writer timeout, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("timeout took %v", time.Since(start))
+	}
+}
+
+// Фильтры по заголовку не открывают канал без писателя
+func TestRunFIFOWithHeaderFilters(t *testing.T) {
+	dir, _ := fifoDir(t)
+	for _, opts := range []Options{
+		{Path: dir, Ext: ".txt", Workers: 1, Format: "json", FIFO: "read", Lang: "sh"},
+		{Path: dir, Ext: ".txt", Workers: 1, Format: "json", FIFO: "read", Types: "text/plain"},
+	} {
+		report, err := runFIFO(t, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range report.Files {
+			if f.FileName == "pipe.txt" {
+				t.Errorf("FIFO should be skipped by header filters, got %+v", f)
+			}
+		}
+	}
+}
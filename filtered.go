@@ -0,0 +1,124 @@
+package main
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Анализатор частоты слов с предварительной фильтрацией токенов.
+// Filter применяется к каждому слову (в нижнем регистре) параллельно,
+// nil Filter пропускает все слова.
+type FilteredFreqAnalyzer struct {
+	Filter func(string) bool
+}
+
+func (f FilteredFreqAnalyzer) Name() string {
+	return "filtered_frequent_words"
+}
+func (f FilteredFreqAnalyzer) Analyze(content string) AnalysisResult {
+	return f.AnalyzeWithArtifacts(content, &Artifacts{Tokens: strings.Fields(content)})
+}
+func (f FilteredFreqAnalyzer) Artifacts() []string {
+	return []string{ArtifactTokens}
+}
+func (f FilteredFreqAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	counter := newShardedCounter(32)
+	countTokensParallel(art.Tokens, f.Filter, counter, runtime.NumCPU())
+	return AnalysisResult{
+		NameAnalyzer: f.Name(),
+		Data:         counter.Map(),
+	}
+}
+
+// Счётчик слов, безопасный для конкурентного использования
+type wordCounter interface {
+	Inc(word string)
+	Map() map[string]int
+}
+
+// Делит токены на части и обрабатывает их в workers горутинах
+func countTokensParallel(words []string, filter func(string) bool, counter wordCounter, workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+	chunk := (len(words) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(words); start += chunk {
+		end := min(start+chunk, len(words))
+		wg.Add(1)
+		go func(part []string) {
+			defer wg.Done()
+			for _, w := range part {
+				w = strings.ToLower(w)
+				if filter == nil || filter(w) {
+					counter.Inc(w)
+				}
+			}
+		}(words[start:end])
+	}
+	wg.Wait()
+}
+
+// Карта под одним мьютексом
+type mutexCounter struct {
+	mu sync.Mutex
+	m  map[string]int
+}
+
+func newMutexCounter() *mutexCounter {
+	return &mutexCounter{m: make(map[string]int)}
+}
+
+func (c *mutexCounter) Inc(word string) {
+	c.mu.Lock()
+	c.m[word]++
+	c.mu.Unlock()
+}
+
+func (c *mutexCounter) Map() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]int, len(c.m))
+	for w, n := range c.m {
+		out[w] = n
+	}
+	return out
+}
+
+// Карта, разбитая на шарды по хешу слова, чтобы горутины реже ждали друг друга
+type shardedCounter struct {
+	shards []*mutexCounter
+}
+
+func newShardedCounter(n int) *shardedCounter {
+	c := &shardedCounter{shards: make([]*mutexCounter, n)}
+	for i := range c.shards {
+		c.shards[i] = newMutexCounter()
+	}
+	return c
+}
+
+func (c *shardedCounter) shard(word string) *mutexCounter {
+	// FNV-1a без аллокаций
+	h := uint32(2166136261)
+	for i := 0; i < len(word); i++ {
+		h ^= uint32(word[i])
+		h *= 16777619
+	}
+	return c.shards[h%uint32(len(c.shards))]
+}
+
+func (c *shardedCounter) Inc(word string) {
+	c.shard(word).Inc(word)
+}
+
+func (c *shardedCounter) Map() map[string]int {
+	out := make(map[string]int)
+	for _, s := range c.shards {
+		for w, n := range s.Map() {
+			out[w] = n
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFilteredFreqAnalyzer(t *testing.T) {
+	stopWords := map[string]bool{"the": true, "a": true}
+	a := FilteredFreqAnalyzer{Filter: func(w string) bool { return !stopWords[w] }}
+
+	freq := a.Analyze("The cat and a dog and the CAT").Data.(map[string]int)
+
+	want := map[string]int{"cat": 2, "and": 2, "dog": 1}
+	if len(freq) != len(want) {
+		t.Fatalf("expected %v, got %v", want, freq)
+	}
+	for w, c := range want {
+		if freq[w] != c {
+			t.Errorf("expected %s=%d, got %d", w, c, freq[w])
+		}
+	}
+}
+
+func TestFilteredFreqAnalyzerNilFilter(t *testing.T) {
+	freq := FilteredFreqAnalyzer{}.Analyze("a b a").Data.(map[string]int)
+	if freq["a"] != 2 || freq["b"] != 1 {
+		t.Errorf("expected a=2 b=1, got %v", freq)
+	}
+}
+
+func benchmarkWords() []string {
+	var words []string
+	for i := 0; i < 100000; i++ {
+		words = append(words, fmt.Sprintf("word%d", i%5000))
+	}
+	return words
+}
+
+func BenchmarkCounterMutex(b *testing.B) {
+	words := benchmarkWords()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countTokensParallel(words, nil, newMutexCounter(), 8)
+	}
+}
+
+func BenchmarkCounterSharded(b *testing.B) {
+	words := benchmarkWords()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countTokensParallel(words, nil, newShardedCounter(32), 8)
+	}
+}
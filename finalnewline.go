@@ -0,0 +1,17 @@
+package main
+
+import "strings"
+
+// Анализатор перевода строки в конце файла.
+// Пустой файл считается корректным и даёт true
+type FinalNewlineAnalyzer struct{}
+
+func (f FinalNewlineAnalyzer) Name() string {
+	return "has_final_newline"
+}
+func (f FinalNewlineAnalyzer) Analyze(content string) AnalysisResult {
+	return AnalysisResult{
+		NameAnalyzer: f.Name(),
+		Data:         content == "" || strings.HasSuffix(content, "\n") || strings.HasSuffix(content, "\r"),
+	}
+}
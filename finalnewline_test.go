@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestFinalNewlineAnalyzer(t *testing.T) {
+	cases := map[string]bool{
+		"abc":     false,
+		"abc\n":   true,
+		"abc\r\n": true,
+		"":        true,
+		"a\nb":    false,
+	}
+	for content, want := range cases {
+		if got := (FinalNewlineAnalyzer{}).Analyze(content).Data.(bool); got != want {
+			t.Errorf("%q: expected %v, got %v", content, want, got)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Уровень важности замечания, по возрастанию
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = []string{"info", "warning", "error", "critical"}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(b []byte) error {
+	v, err := parseSeverity(string(b))
+	*s = v
+	return err
+}
+
+func parseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if s == name {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("неизвестный уровень %q, ожидается info, warning, error или critical", s)
+}
+
+// Замечание анализатора по файлу
+type Finding struct {
+	File     string   `json:"file"`
+	Analyzer string   `json:"analyzer"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Уровни по умолчанию для анализаторов, дающих замечания
+var defaultSeverities = map[string]Severity{
+	"line_endings":      SeverityWarning,
+	"indentation":       SeverityWarning,
+	"has_final_newline": SeverityInfo,
+	"license":           SeverityInfo,
+}
+
+// Добавление недостающих анализаторов, дающих замечания: без них -min-severity
+// и -fail-on-severity при отфильтрованном наборе молча ничего не находят
+func withFindingAnalyzers(analyzers []Analyzer, opts Options) []Analyzer {
+	have := make(map[string]bool)
+	for _, a := range analyzers {
+		have[a.Name()] = true
+	}
+	for _, a := range []Analyzer{
+		LineEndingAnalyzer{Examples: opts.CollectExamples},
+		IndentationAnalyzer{Examples: opts.CollectExamples},
+		FinalNewlineAnalyzer{},
+		LicenseHeaderAnalyzer{},
+	} {
+		if !have[a.Name()] {
+			analyzers = append(analyzers, a)
+		}
+	}
+	return analyzers
+}
+
+// Разбор -severity вида "line_endings=error,license=critical"
+func parseSeverityOverrides(s string) (map[string]Severity, error) {
+	levels := make(map[string]Severity)
+	for name, level := range defaultSeverities {
+		levels[name] = level
+	}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, level, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("неверное значение -severity %q, ожидается анализатор=уровень", part)
+		}
+		if _, known := defaultSeverities[name]; !known {
+			return nil, fmt.Errorf("анализатор %q не даёт замечаний, известны: %s", name, strings.Join(sortedSeverityNames(), ", "))
+		}
+		sev, err := parseSeverity(level)
+		if err != nil {
+			return nil, err
+		}
+		levels[name] = sev
+	}
+	return levels, nil
+}
+
+func sortedSeverityNames() []string {
+	names := make([]string, 0, len(defaultSeverities))
+	for name := range defaultSeverities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Замечания по результатам файла с уровнями из levels
+func fileFindings(res FileAnalysisResult, levels map[string]Severity) []Finding {
+	var findings []Finding
+	add := func(analyzer, msg string) {
+		findings = append(findings, Finding{res.FilePath, analyzer, levels[analyzer], msg})
+	}
+	for _, r := range res.Results {
+		switch data := resolveResult(r).Data.(type) {
+		case LineEndings:
+			if data.Mixed {
+				add(r.NameAnalyzer, fmt.Sprintf("mixed line endings (lf=%d crlf=%d cr=%d)", data.LF, data.CRLF, data.CR))
+			}
+		case Indentation:
+			if data.Mixed {
+				add(r.NameAnalyzer, fmt.Sprintf("mixed indentation (tabs=%d spaces=%d)", data.TabLines, data.SpaceLines))
+			}
+		case bool:
+			if r.NameAnalyzer == "has_final_newline" && !data {
+				add(r.NameAnalyzer, "no final newline")
+			}
+		case LicenseInfo:
+			if data.License == "none" {
+				add(r.NameAnalyzer, "no license header")
+			}
+		}
+	}
+	return findings
+}
+
+// Сработавший -fail-on-severity, main завершает программу с ненулевым кодом
+type FindingsError struct {
+	Level Severity
+	Count int
+}
+
+func (e *FindingsError) Error() string {
+	return fmt.Sprintf("найдено замечаний уровня %s и выше: %d", e.Level, e.Count)
+}
+
+// Замечания всех файлов: в отчёт попадают не ниже min, в счётчики по уровням - все
+func collectFindings(results []FileAnalysisResult, levels map[string]Severity, min Severity) ([]Finding, map[string]int) {
+	var shown []Finding
+	counts := make(map[string]int)
+	for _, res := range results {
+		for _, f := range fileFindings(res, levels) {
+			counts[f.Severity.String()]++
+			if f.Severity >= min {
+				shown = append(shown, f)
+			}
+		}
+	}
+	sort.Slice(shown, func(i, j int) bool {
+		if shown[i].Severity != shown[j].Severity {
+			return shown[i].Severity > shown[j].Severity
+		}
+		if shown[i].File != shown[j].File {
+			return shown[i].File < shown[j].File
+		}
+		return shown[i].Analyzer < shown[j].Analyzer
+	})
+	return shown, counts
+}
+
+// Проверка -fail-on-severity по счётчикам всех замечаний
+func checkFailOnSeverity(counts map[string]int, level Severity) error {
+	n := 0
+	for s := level; s <= SeverityCritical; s++ {
+		n += counts[s.String()]
+	}
+	if n > 0 {
+		return &FindingsError{level, n}
+	}
+	return nil
+}
+
+// Метка уровня: error и critical выделяются красным, warning - жёлтым
+func (c colorizer) severity(s Severity) string {
+	tag := "[" + s.String() + "]"
+	switch {
+	case s >= SeverityError:
+		return c.wrap(ansiBold+ansiRed, tag)
+	case s == SeverityWarning:
+		return c.wrap(ansiYellow, tag)
+	}
+	return tag
+}
+
+func writeFindingsText(out io.Writer, c colorizer, findings []Finding, counts map[string]int) {
+	var parts []string
+	for _, name := range severityNames {
+		if counts[name] > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", name, counts[name]))
+		}
+	}
+	fmt.Fprintf(out, "Замечания (%s):\n", strings.Join(parts, ", "))
+	for _, f := range findings {
+		fmt.Fprintf(out, " %s %s: %s: %s\n", c.severity(f.Severity), c.name(f.File), f.Analyzer, f.Message)
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestParseSeverityOverrides(t *testing.T) {
+	levels, err := parseSeverityOverrides("line_endings=error, license=critical")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if levels["line_endings"] != SeverityError || levels["license"] != SeverityCritical {
+		t.Errorf("overrides not applied: %v", levels)
+	}
+	if levels["indentation"] != SeverityWarning || levels["has_final_newline"] != SeverityInfo {
+		t.Errorf("defaults lost: %v", levels)
+	}
+	for _, bad := range []string{"line_endings", "words=error", "license=fatal"} {
+		if _, err := parseSeverityOverrides(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCollectFindings(t *testing.T) {
+	results := []FileAnalysisResult{
+		{FileName: "a.txt", FilePath: "a.txt", Results: []AnalysisResult{
+			{NameAnalyzer: "line_endings", Data: LineEndings{LF: 1, CRLF: 1, Mixed: true}},
+			{NameAnalyzer: "has_final_newline", Data: false},
+		}},
+		{FileName: "b.txt", FilePath: "b.txt", Results: []AnalysisResult{
+			{NameAnalyzer: "indentation", Data: Indentation{TabLines: 2, SpaceLines: 1, Mixed: true}},
+			{NameAnalyzer: "has_final_newline", Data: true},
+		}},
+	}
+	levels, _ := parseSeverityOverrides("indentation=error")
+	shown, counts := collectFindings(results, levels, SeverityWarning)
+	want := []Finding{
+		{"b.txt", "indentation", SeverityError, "mixed indentation (tabs=2 spaces=1)"},
+		{"a.txt", "line_endings", SeverityWarning, "mixed line endings (lf=1 crlf=1 cr=0)"},
+	}
+	if !reflect.DeepEqual(shown, want) {
+		t.Errorf("expected %v, got %v", want, shown)
+	}
+	if want := map[string]int{"info": 1, "warning": 1, "error": 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("filtered findings must still be counted: expected %v, got %v", want, counts)
+	}
+
+	for level, fail := range map[Severity]bool{
+		SeverityInfo: true, SeverityWarning: true, SeverityError: true, SeverityCritical: false,
+	} {
+		err := checkFailOnSeverity(counts, level)
+		if (err != nil) != fail {
+			t.Errorf("fail-on-severity=%s: expected fail=%v, got %v", level, fail, err)
+		}
+	}
+}
+
+func TestRunMinSeverity(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt": "one two\r\nthree four\n",
+		"b.txt": "alpha beta",
+	})
+
+	var out bytes.Buffer
+	// анализаторы замечаний добавляются к набору из -analyzers
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", MinSeverity: "warning", Analyzers: "word_count"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Summary SummaryReport `json:"summary"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Summary.Findings) != 1 || report.Summary.Findings[0].Analyzer != "line_endings" ||
+		report.Summary.Findings[0].File != filepath.Join(dir, "a.txt") {
+		t.Errorf("expected only the line_endings warning for %s, got %v", filepath.Join(dir, "a.txt"), report.Summary.Findings)
+	}
+	// info: нет перевода строки в конце b.txt и заголовка лицензии в обоих файлах
+	if want := map[string]int{"info": 3, "warning": 1}; !reflect.DeepEqual(report.Summary.FindingCounts, want) {
+		t.Errorf("expected counts %v, got %v", want, report.Summary.FindingCounts)
+	}
+
+	out.Reset()
+	opts = Options{Path: dir, Ext: ".txt", Workers: 1, Format: "text", FailOnSeverity: "error"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatalf("no error-level findings, unexpected %v", err)
+	}
+	if !strings.Contains(out.String(), "Замечания (info=3, warning=1):") {
+		t.Errorf("expected findings section, got:\n%s", out.String())
+	}
+
+	opts.Severity = "line_endings=error"
+	err := run(context.Background(), opts, &out)
+	var findingsErr *FindingsError
+	if !errors.As(err, &findingsErr) || findingsErr.Count != 1 {
+		t.Fatalf("expected findings error with 1 finding, got %v", err)
+	}
+	if code := exitCode(err); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+}
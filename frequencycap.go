@@ -0,0 +1,37 @@
+package main
+
+import "sort"
+
+// Точные частоты не больше чем capacity самых частых слов файла: полная карта
+// строится только на время подсчёта и сразу обрезается, хранится лишь обрезанная.
+// Топ-N файла для N <= capacity точный; слова за пределами capacity теряются,
+// поэтому в сумме по корпусу хвост частот приблизителен
+func cappedFrequencies(words []string, capacity int) map[string]int {
+	freq := make(map[string]int)
+	for _, w := range words {
+		freq[w]++
+	}
+	return pruneFrequencies(freq, capacity)
+}
+
+// Оставить в freq capacity самых частых слов, при равенстве - меньшие по алфавиту
+func pruneFrequencies(freq map[string]int, capacity int) map[string]int {
+	if len(freq) <= capacity {
+		return freq
+	}
+	words := make([]string, 0, len(freq))
+	for w := range freq {
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if freq[words[i]] != freq[words[j]] {
+			return freq[words[i]] > freq[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	pruned := make(map[string]int, capacity)
+	for _, w := range words[:capacity] {
+		pruned[w] = freq[w]
+	}
+	return pruned
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func exactCounts(words []string) map[string]int {
+	freq := make(map[string]int)
+	for _, w := range words {
+		freq[w]++
+	}
+	return freq
+}
+
+func TestCappedFrequencies(t *testing.T) {
+	words := strings.Fields("a b a c a b d e a b f")
+	if got, want := cappedFrequencies(words, 100), exactCounts(words); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected exact counts when vocabulary fits, got %v", got)
+	}
+	got := cappedFrequencies(words, 3)
+	if len(got) != 3 || got["a"] != 4 {
+		t.Errorf("expected the exact count of a among 3 words, got %v", got)
+	}
+	// частые слова в начале и много одиночных после них остаются в топе
+	words = strings.Fields("a a b b c c x0 x1 x2 x3 x4 x5 x6 x7 x8 x9")
+	if got := cappedFrequencies(words, 3); !reflect.DeepEqual(got, map[string]int{"a": 2, "b": 2, "c": 2}) {
+		t.Errorf("expected exact top-3 a, b, c, got %v", got)
+	}
+	if got := pruneFrequencies(map[string]int{"x": 1, "y": 5, "z": 1}, 2); !reflect.DeepEqual(got, map[string]int{"y": 5, "x": 1}) {
+		t.Errorf("unexpected prune result %v", got)
+	}
+}
+
+func zipfCorpus(files, wordsPerFile int) map[string]string {
+	rng := rand.New(rand.NewSource(7))
+	zipf := rand.NewZipf(rng, 1.2, 1, 5000)
+	corpus := make(map[string]string)
+	for f := 0; f < files; f++ {
+		var b strings.Builder
+		for i := 0; i < wordsPerFile; i++ {
+			fmt.Fprintf(&b, "w%d ", zipf.Uint64())
+		}
+		corpus[fmt.Sprintf("f%d.txt", f)] = b.String()
+	}
+	return corpus
+}
+
+func TestCappedFrequenciesZipfTop10(t *testing.T) {
+	dir := testutil.CreateTempDir(t, zipfCorpus(5, 20000))
+	top := func(capped bool) []WordCount {
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", TopWords: 10, CappedFrequencies: capped, FrequencyCap: 200}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		if capped && report.Summary.FrequencyCap != 200 {
+			t.Errorf("expected frequency_cap 200 in the summary, got %d", report.Summary.FrequencyCap)
+		}
+		return report.Summary.TopWords
+	}
+	exact, capped := top(false), top(true)
+	if !reflect.DeepEqual(exact, capped) {
+		t.Errorf("top-10 differs:\nexact  %v\ncapped %v", exact, capped)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Список стран и столиц, строки с # - комментарии
+//
+//go:embed geo_names.txt
+var geoNamesData string
+
+// Названия по первому слову в нижнем регистре, длинные названия раньше коротких
+var (
+	geoNamesOnce  sync.Once
+	geoNamesIndex map[string][]geoName
+)
+
+type geoName struct {
+	name  string
+	words []string
+}
+
+func loadGeoNames() map[string][]geoName {
+	geoNamesOnce.Do(func() {
+		geoNamesIndex = make(map[string][]geoName)
+		for _, line := range strings.Split(geoNamesData, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			words := paragraphWords(line)
+			first := words[0]
+			names := append(geoNamesIndex[first], geoName{line, words})
+			// жадный поиск: сначала "Mexico City", потом "Mexico"
+			for i := len(names) - 1; i > 0 && len(names[i].words) > len(names[i-1].words); i-- {
+				names[i], names[i-1] = names[i-1], names[i]
+			}
+			geoNamesIndex[first] = names
+		}
+	})
+	return geoNamesIndex
+}
+
+// Анализатор упоминаний стран и столиц: название -> число упоминаний.
+// Простое сравнение слов без учёта регистра, не распознавание сущностей
+type GeoMentionAnalyzer struct{}
+
+func (g GeoMentionAnalyzer) Name() string {
+	return "geo_mentions"
+}
+func (g GeoMentionAnalyzer) Analyze(content string) AnalysisResult {
+	index := loadGeoNames()
+	words := paragraphWords(content)
+	mentions := make(map[string]int)
+	for i := 0; i < len(words); i++ {
+		for _, n := range index[words[i]] {
+			if i+len(n.words) <= len(words) && equalWords(words[i:i+len(n.words)], n.words) {
+				mentions[n.name]++
+				i += len(n.words) - 1
+				break
+			}
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: g.Name(),
+		Data:         mentions,
+	}
+}
+
+func equalWords(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Счётчики через запятую: по убыванию, при равенстве - по имени
+func formatCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", name, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeoMentionAnalyzer(t *testing.T) {
+	content := "We flew from Paris to Buenos Aires, then to Mexico City.\n" +
+		"France and PARIS again; Mexico is large. New Delhi is in India."
+	got := runAnalyzer(GeoMentionAnalyzer{}, content).Data
+	want := map[string]int{
+		"Paris":        2,
+		"Buenos Aires": 1,
+		"Mexico City":  1,
+		"France":       1,
+		"Mexico":       1,
+		"New Delhi":    1,
+		"India":        1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := runAnalyzer(GeoMentionAnalyzer{}, "no places mentioned, Parisian food").Data.(map[string]int); len(got) != 0 {
+		t.Errorf("expected no mentions, got %v", got)
+	}
+}
+
+func TestFormatCounts(t *testing.T) {
+	if got, want := formatCounts(map[string]int{"Rome": 1, "Oslo": 3, "Lima": 1}), "Oslo=3, Lima=1, Rome=1"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,14 @@
+package main
+
+import "runtime"
+
+// Установка runtime.GOMAXPROCS на время запуска, restore возвращает прежнее значение.
+// При n <= 0 ничего не меняется. Значение общее для процесса, поэтому в режимах
+// сервера с параллельными запросами -gomaxprocs не принимается
+func setGOMAXPROCS(n int) (restore func()) {
+	if n <= 0 {
+		return func() {}
+	}
+	prev := runtime.GOMAXPROCS(n)
+	return func() { runtime.GOMAXPROCS(prev) }
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestSetGOMAXPROCS(t *testing.T) {
+	prev := runtime.GOMAXPROCS(0)
+	want := prev + 1
+	restore := setGOMAXPROCS(want)
+	if got := runtime.GOMAXPROCS(0); got != want {
+		t.Errorf("expected GOMAXPROCS %d during run, got %d", want, got)
+	}
+	restore()
+	if got := runtime.GOMAXPROCS(0); got != prev {
+		t.Errorf("expected GOMAXPROCS restored to %d, got %d", prev, got)
+	}
+
+	setGOMAXPROCS(0)()
+	if got := runtime.GOMAXPROCS(0); got != prev {
+		t.Errorf("expected 0 to keep GOMAXPROCS %d, got %d", prev, got)
+	}
+}
+
+func TestRunRestoresGOMAXPROCS(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": "hello world"})
+	prev := runtime.GOMAXPROCS(0)
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, GOMAXPROCS: 1}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := runtime.GOMAXPROCS(0); got != prev {
+		t.Errorf("expected GOMAXPROCS restored to %d after run, got %d", prev, got)
+	}
+}
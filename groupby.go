@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Подытоги по каталогу для -group-by
+type DirectoryGroup struct {
+	Dir   string `json:"dir"`
+	Files int    `json:"files"`
+	Lines int    `json:"lines"`
+	Words int    `json:"words"`
+}
+
+// Проверка значения -group-by
+func validGroupBy(s string) error {
+	switch s {
+	case "", "dir", "top":
+		return nil
+	}
+	return fmt.Errorf("неизвестная группировка -group-by %q, ожидается dir или top", s)
+}
+
+// Группы для режима -group-by: dir - по каталогу файла, top - по подкаталогу первого уровня root
+func groupResults(results []FileAnalysisResult, mode, root string) map[string][]FileAnalysisResult {
+	if mode == "top" {
+		return GroupByTopLevel(results, root)
+	}
+	return GroupByDirectory(results)
+}
+
+// Группировка результатов по каталогу файла
+func GroupByDirectory(results []FileAnalysisResult) map[string][]FileAnalysisResult {
+	groups := make(map[string][]FileAnalysisResult)
+	for _, res := range results {
+		dir := filepath.Dir(res.FilePath)
+		groups[dir] = append(groups[dir], res)
+	}
+	return groups
+}
+
+// Группировка по подкаталогу первого уровня относительно root.
+// Файлы в самом root попадают в группу ".", подкаталоги (-include-dirs) - в свою группу
+func GroupByTopLevel(results []FileAnalysisResult, root string) map[string][]FileAnalysisResult {
+	groups := make(map[string][]FileAnalysisResult)
+	for _, res := range results {
+		group := "."
+		if rel, err := filepath.Rel(root, res.FilePath); err == nil {
+			parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+			if len(parts) == 2 || res.IsDir {
+				group = parts[0]
+			}
+		}
+		groups[group] = append(groups[group], res)
+	}
+	return groups
+}
+
+// Подытоги групп в порядке имён каталогов, записи каталогов не считаются файлами
+func directoryGroups(groups map[string][]FileAnalysisResult) []DirectoryGroup {
+	dirs := make([]string, 0, len(groups))
+	for dir := range groups {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	var out []DirectoryGroup
+	for _, dir := range dirs {
+		g := DirectoryGroup{Dir: dir}
+		for _, res := range groups[dir] {
+			if res.IsDir {
+				continue
+			}
+			g.Files++
+			for _, r := range res.Results {
+				switch r.NameAnalyzer {
+				case "word_count":
+					if n, ok := r.Data.(int); ok {
+						g.Words += n
+					}
+				case "line_count":
+					if n, ok := r.Data.(int); ok {
+						g.Lines += n
+					}
+				}
+			}
+		}
+		out = append(out, g)
+	}
+	return out
+}
+
+// Вывод файлов по каталогам: заголовок с подытогами, затем файлы по имени
+func writeGroupsText(out io.Writer, c colorizer, groups map[string][]FileAnalysisResult, write func(FileAnalysisResult)) {
+	for _, g := range directoryGroups(groups) {
+		fmt.Fprintf(out, "%s: files = %d, lines = %d, words = %d\n", c.bold(c.name(g.Dir)+string(filepath.Separator)), g.Files, g.Lines, g.Words)
+		files := groups[g.Dir]
+		sort.Slice(files, func(i, j int) bool { return files[i].FileName < files[j].FileName })
+		for _, res := range files {
+			write(res)
+		}
+		fmt.Fprintln(out)
+	}
+}
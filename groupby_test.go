@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupByDirectory(t *testing.T) {
+	results := []FileAnalysisResult{
+		{FileName: "a.txt", FilePath: filepath.Join("root", "a.txt")},
+		{FileName: "b.txt", FilePath: filepath.Join("root", "sub", "b.txt")},
+		{FileName: "c.txt", FilePath: filepath.Join("root", "sub", "c.txt")},
+	}
+	groups := GroupByDirectory(results)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %v", groups)
+	}
+	if got := groups["root"]; len(got) != 1 || got[0].FileName != "a.txt" {
+		t.Errorf("unexpected root group %v", got)
+	}
+	if got := groups[filepath.Join("root", "sub")]; len(got) != 2 {
+		t.Errorf("unexpected sub group %v", got)
+	}
+}
+
+func TestRunGroupByDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"top.txt":     "one two three",
+		"sub/x.txt":   "alpha beta\ngamma delta",
+		"sub/y.txt":   "first second\nthird",
+		"sub/one.txt": "single",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", GroupBy: "dir"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	want := []DirectoryGroup{
+		{Dir: dir, Files: 1, Lines: 1, Words: 3},
+		{Dir: filepath.Join(dir, "sub"), Files: 2, Lines: 4, Words: 7},
+	}
+	if !reflect.DeepEqual(report.Summary.Groups, want) {
+		t.Errorf("expected groups %+v, got %+v", want, report.Summary.Groups)
+	}
+
+	out.Reset()
+	opts.Format = "text"
+	opts.Color = "never"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	header := filepath.Join(dir, "sub") + string(filepath.Separator) + ": files = 2, lines = 4, words = 7"
+	i, j, k := strings.Index(text, header), strings.Index(text, "Файл: x.txt"), strings.Index(text, "Файл: y.txt")
+	if i < 0 || !(i < j && j < k) {
+		t.Errorf("expected sub header followed by x.txt and y.txt, got:\n%s", text)
+	}
+
+	opts.GroupBy = "ext"
+	if err := run(context.Background(), opts, &out); err == nil {
+		t.Error("expected error for unknown -group-by value")
+	}
+}
+
+func TestRunGroupByTopLevel(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a", "deep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"top.txt":        "root level file",
+		"a/x.txt":        "alpha beta\ngamma",
+		"a/deep/y.txt":   "nested words here\nand here",
+		"b/z.txt":        "bravo charlie",
+		"b/ignored.txt":  "single",
+		"a/deep/w.txt":   "one two",
+		"a/deep/skip.md": "not analyzed at all",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", GroupBy: "top"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	want := []DirectoryGroup{
+		{Dir: ".", Files: 1, Lines: 1, Words: 3},
+		{Dir: "a", Files: 3, Lines: 5, Words: 10},
+		{Dir: "b", Files: 1, Lines: 1, Words: 2},
+	}
+	if !reflect.DeepEqual(report.Summary.Groups, want) {
+		t.Errorf("expected groups %+v, got %+v", want, report.Summary.Groups)
+	}
+}
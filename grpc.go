@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"stage5/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative api/textanalyzer.proto
+
+// Сервис описан в api/textanalyzer.proto, сообщения - сгенерированные типы пакета api.
+// protoc-gen-go-grpc не используется, описание сервиса задано ниже вручную
+const textAnalyzerMethod = "/textanalyzer.TextAnalyzer/AnalyzeDirectory"
+
+type textAnalyzerServer struct{}
+
+var textAnalyzerServiceDesc = grpc.ServiceDesc{
+	ServiceName: "textanalyzer.TextAnalyzer",
+	HandlerType: (*any)(nil),
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "AnalyzeDirectory",
+		Handler:       analyzeDirectoryHandler,
+		ServerStreams: true,
+	}},
+	Metadata: "api/textanalyzer.proto",
+}
+
+func analyzeDirectoryHandler(srv any, stream grpc.ServerStream) error {
+	req := new(api.AnalyzeRequest)
+	if err := stream.RecvMsg(req); err != nil {
+		return err
+	}
+	return srv.(textAnalyzerServer).AnalyzeDirectory(req, stream)
+}
+
+// Анализ каталога с отправкой результатов по мере готовности.
+// После ошибки отправки или отмены вызова клиентом оставшиеся файлы не анализируются
+func (textAnalyzerServer) AnalyzeDirectory(in *api.AnalyzeRequest, stream grpc.ServerStream) error {
+	files, req, err := requestFiles(SocketRequest{Path: in.GetPath(), Ext: in.GetExt(), Workers: int(in.GetWorkers())})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var sendErr error
+	err = AnalyzeParallelContext(ctx, files, defaultAnalyzers(Options{}), req.Workers, func(r FileAnalysisResult) {
+		if sendErr != nil {
+			return
+		}
+		msg, err := fileResponse(r)
+		if err == nil {
+			err = stream.SendMsg(msg)
+		}
+		if err != nil {
+			sendErr = err
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
+}
+
+// Результат файла в сообщении FileAnalysisResponse. Данные анализатора
+// передаются тем же JSON-значением, что и в отчёте
+func fileResponse(r FileAnalysisResult) (*api.FileAnalysisResponse, error) {
+	msg := &api.FileAnalysisResponse{
+		FileName:    r.FileName,
+		FilePath:    r.FilePath,
+		Size:        r.Size,
+		ModTime:     timestamppb.New(r.ModTime),
+		ContentHash: r.ContentHash,
+	}
+	for _, res := range r.Results {
+		raw, err := json.Marshal(res.Data)
+		if err != nil {
+			return nil, err
+		}
+		var data any
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, err
+		}
+		value, err := structpb.NewValue(data)
+		if err != nil {
+			return nil, err
+		}
+		msg.Results = append(msg.Results, &api.AnalysisResult{Name: res.NameAnalyzer, Data: value, Confidence: res.Confidence})
+	}
+	return msg, nil
+}
+
+func newGRPCServer() *grpc.Server {
+	s := grpc.NewServer()
+	s.RegisterService(&textAnalyzerServiceDesc, textAnalyzerServer{})
+	return s
+}
+
+// gRPC-сервер анализа, работает до отмены ctx
+func serveGRPC(ctx context.Context, addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return serveGRPCListener(ctx, ln)
+}
+
+func serveGRPCListener(ctx context.Context, ln net.Listener) error {
+	s := newGRPCServer()
+	go func() {
+		<-ctx.Done()
+		s.GracefulStop()
+	}()
+	return s.Serve(ln)
+}
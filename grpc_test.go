@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stage5/api"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Клиент потока AnalyzeDirectory к серверу на ln
+func analyzeDirectoryStream(t *testing.T, ctx context.Context, ln net.Listener, req *api.AnalyzeRequest) grpc.ClientStream {
+	t.Helper()
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, textAnalyzerMethod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.SendMsg(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	return stream
+}
+
+func TestGRPCAnalyzeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 5; i++ {
+		content := fmt.Sprintf("file number %d\nhello go", i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.txt", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveGRPCListener(ctx, ln) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}()
+
+	stream := analyzeDirectoryStream(t, ctx, ln, &api.AnalyzeRequest{Path: dir, Ext: ".txt", Workers: 2})
+	seen := make(map[string]bool)
+	for {
+		res := new(api.FileAnalysisResponse)
+		err := stream.RecvMsg(res)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[res.GetFileName()] = true
+		for _, r := range res.GetResults() {
+			if r.GetName() == "word_count" && r.GetData().GetNumberValue() != 5 {
+				t.Errorf("%s: expected 5 words, got %v", res.GetFileName(), r.GetData())
+			}
+		}
+		if res.GetModTime().AsTime().IsZero() || res.GetContentHash() == "" {
+			t.Errorf("%s: expected mod time and content hash, got %v", res.GetFileName(), res)
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected 5 streamed files, got %v", seen)
+	}
+}
+
+func TestGRPCInvalidRequest(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go serveGRPCListener(ctx, ln)
+
+	stream := analyzeDirectoryStream(t, ctx, ln, &api.AnalyzeRequest{})
+	err = stream.RecvMsg(new(api.FileAnalysisResponse))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for empty path, got %v", err)
+	}
+}
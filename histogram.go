@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Bucket struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// Распределение значений: квантили и гистограмма
+type Distribution struct {
+	Min     float64  `json:"min"`
+	Median  float64  `json:"median"`
+	P90     float64  `json:"p90"`
+	Max     float64  `json:"max"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Гистограмма с корзинами [bounds[i-1], bounds[i]) и последней корзиной >= bounds[len-1].
+// Количество в корзинах считается точно, квантили - по выборке значений:
+// все значения, если reservoir == 0, иначе случайная выборка из reservoir значений.
+type Histogram struct {
+	bounds    []float64
+	labels    []string
+	counts    []int
+	min, max  float64
+	seen      int
+	reservoir int
+	sample    []float64
+	rng       *rand.Rand
+}
+
+func newHistogram(bounds []float64, labels []string, reservoir int, rng *rand.Rand) *Histogram {
+	return &Histogram{
+		bounds:    bounds,
+		labels:    labels,
+		counts:    make([]int, len(bounds)+1),
+		reservoir: reservoir,
+		rng:       rng,
+	}
+}
+
+// Гистограмма размеров файлов с логарифмическими корзинами
+func newSizeHistogram() *Histogram {
+	return newHistogram(
+		[]float64{1 << 10, 10 << 10, 100 << 10, 1 << 20, 10 << 20},
+		[]string{"<1KB", "1-10KB", "10-100KB", "100KB-1MB", "1-10MB", ">=10MB"},
+		0, nil,
+	)
+}
+
+// Гистограмма количества слов с логарифмическими корзинами
+func newWordHistogram() *Histogram {
+	return newHistogram(
+		[]float64{10, 100, 1000, 10000, 100000},
+		[]string{"<10", "10-100", "100-1K", "1K-10K", "10K-100K", ">=100K"},
+		0, nil,
+	)
+}
+
+// Номер корзины для значения v
+func (h *Histogram) bucketIndex(v float64) int {
+	return sort.Search(len(h.bounds), func(i int) bool { return v < h.bounds[i] })
+}
+
+func (h *Histogram) Add(v float64) {
+	h.counts[h.bucketIndex(v)]++
+	if h.seen == 0 || v < h.min {
+		h.min = v
+	}
+	if h.seen == 0 || v > h.max {
+		h.max = v
+	}
+	h.seen++
+
+	switch {
+	case h.reservoir == 0 || len(h.sample) < h.reservoir:
+		h.sample = append(h.sample, v)
+	default:
+		// алгоритм R: каждое из seen значений остаётся в выборке с вероятностью reservoir/seen
+		if j := h.rng.Intn(h.seen); j < h.reservoir {
+			h.sample[j] = v
+		}
+	}
+}
+
+// Квантиль q (0..1) отсортированных значений с линейной интерполяцией
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + frac*(sorted[lo+1]-sorted[lo])
+}
+
+func (h *Histogram) Distribution() Distribution {
+	sorted := append([]float64(nil), h.sample...)
+	sort.Float64s(sorted)
+	d := Distribution{
+		Min:    h.min,
+		Median: quantile(sorted, 0.5),
+		P90:    quantile(sorted, 0.9),
+		Max:    h.max,
+	}
+	for i, c := range h.counts {
+		d.Buckets = append(d.Buckets, Bucket{h.labels[i], c})
+	}
+	return d
+}
+
+// Число с округлением до сотых без лишних нулей
+func formatStat(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// Наибольшая длина столбца гистограммы в символах
+const histogramWidth = 30
+
+// Печать распределения со столбцами из символов блока
+func writeDistributionText(out io.Writer, title string, d Distribution) {
+	fmt.Fprintf(out, "%s: min=%s median=%s p90=%s max=%s\n", title, formatStat(d.Min), formatStat(d.Median), formatStat(d.P90), formatStat(d.Max))
+	maxCount, labelWidth := 0, 0
+	for _, b := range d.Buckets {
+		maxCount = max(maxCount, b.Count)
+		labelWidth = max(labelWidth, len(b.Label))
+	}
+	for _, b := range d.Buckets {
+		bar := 0
+		if maxCount > 0 {
+			bar = (b.Count*histogramWidth + maxCount - 1) / maxCount
+		}
+		fmt.Fprintf(out, " %-*s %s %d\n", labelWidth, b.Label, strings.Repeat("█", bar), b.Count)
+	}
+	fmt.Fprintln(out)
+}
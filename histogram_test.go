@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update-golden", false, "перезаписать golden-файлы в testdata")
+
+func TestHistogramBucketBoundaries(t *testing.T) {
+	h := newSizeHistogram()
+	tests := map[float64]string{
+		0:           "<1KB",
+		1023:        "<1KB",
+		1024:        "1-10KB",
+		10*1024 - 1: "1-10KB",
+		10 * 1024:   "10-100KB",
+		1 << 20:     "1-10MB",
+		10 << 20:    ">=10MB",
+		100 << 20:   ">=10MB",
+	}
+	for v, want := range tests {
+		if got := h.labels[h.bucketIndex(v)]; got != want {
+			t.Errorf("value %g: expected bucket %s, got %s", v, want, got)
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	tests := []struct {
+		q, want float64
+	}{
+		{0, 1},
+		{0.5, 5.5},
+		{0.9, 9.1},
+		{1, 10},
+	}
+	for _, tt := range tests {
+		if got := quantile(sorted, tt.q); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("quantile(%g) = %g, want %g", tt.q, got, tt.want)
+		}
+	}
+	if got := quantile(nil, 0.5); got != 0 {
+		t.Errorf("expected 0 for empty input, got %g", got)
+	}
+}
+
+func TestHistogramReservoir(t *testing.T) {
+	h := newHistogram([]float64{10}, []string{"<10", ">=10"}, 5, rand.New(rand.NewSource(1)))
+	for i := 0; i < 100; i++ {
+		h.Add(float64(i))
+	}
+	if len(h.sample) != 5 {
+		t.Errorf("expected reservoir of 5, got %d", len(h.sample))
+	}
+	d := h.Distribution()
+	if d.Min != 0 || d.Max != 99 || d.Buckets[0].Count != 10 || d.Buckets[1].Count != 90 {
+		t.Errorf("expected exact min/max and bucket counts, got %+v", d)
+	}
+}
+
+func TestDistributionTextGolden(t *testing.T) {
+	h := newWordHistogram()
+	for _, v := range []float64{3, 7, 15, 40, 80, 95, 120, 2500} {
+		h.Add(v)
+	}
+	var out bytes.Buffer
+	writeDistributionText(&out, "Слов в файле", h.Distribution())
+
+	golden := filepath.Join("testdata", "histogram.golden")
+	if *updateGolden {
+		if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(want) {
+		t.Errorf("histogram output differs from golden:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
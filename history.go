@@ -0,0 +1,115 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Уровни спарклайна от меньшего к большему
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Спарклайн ряда значений, NaN - пропуск (пробел).
+// Ряд из одинаковых значений рисуется средним уровнем
+func sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case hi == lo:
+			b.WriteRune(sparkLevels[len(sparkLevels)/2])
+		default:
+			b.WriteRune(sparkLevels[int(math.Round((v-lo)/(hi-lo)*float64(len(sparkLevels)-1)))])
+		}
+	}
+	return b.String()
+}
+
+// Значение метрики в сохранённом JSON отчёте. С file метрикой служит
+// результат анализатора этого файла, файла нет в отчёте - ok == false
+func historyValue(report Report, metric, file string) (float64, bool, error) {
+	if file != "" {
+		for _, res := range report.Files {
+			if res.FilePath == NormalizePath(file) || res.FileName == file {
+				n, ok := numericResult(res, metric)
+				return float64(n), ok, nil
+			}
+		}
+		return 0, false, nil
+	}
+	metrics := summaryMetrics(report.Summary)
+	metrics["total_words"] = report.Summary.TotalWords
+	metrics["total_lines"] = report.Summary.TotalLines
+	v, ok := metrics[metric]
+	if !ok {
+		return 0, false, fmt.Errorf("неизвестная метрика %q", metric)
+	}
+	return float64(v), true, nil
+}
+
+// Подкоманда history: таблица метрики по сохранённым JSON отчётам (-format json)
+// в порядке аргументов, изменение к предыдущему запуску и спарклайн.
+// Хранилища запусков нет, история - это набор файлов отчётов
+func runHistory(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	metric := fs.String("metric", "total_words", "метрика сводки или анализатора файла при -file")
+	file := fs.String("file", "", "показать метрику анализатора для одного файла, например -metric word_count -file a.txt")
+	last := fs.Int("last", 30, "сколько последних запусков показать")
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("использование: history [-metric total_words] [-file путь] [-last N] отчёт.json...: %w", err)
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		return errors.New("использование: history [-metric total_words] [-file путь] [-last N] отчёт.json...")
+	}
+	if *last > 0 && len(paths) > *last {
+		paths = paths[len(paths)-*last:]
+	}
+
+	values := make([]float64, len(paths))
+	width := len("run")
+	for i, path := range paths {
+		report, err := loadReport(path)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения отчёта %s: %w", path, err)
+		}
+		v, ok, err := historyValue(report, *metric, *file)
+		if err != nil {
+			return err
+		}
+		values[i] = math.NaN()
+		if ok {
+			values[i] = v
+		}
+		width = max(width, len(filepath.Base(path)))
+	}
+
+	fmt.Fprintf(out, "%-*s  %12s  %8s\n", width, "run", *metric, "delta")
+	prev := math.NaN()
+	for i, path := range paths {
+		v := values[i]
+		value, delta := "-", ""
+		if !math.IsNaN(v) {
+			value = fmt.Sprintf("%.0f", v)
+			if !math.IsNaN(prev) {
+				delta = fmt.Sprintf("%+.0f", v-prev)
+			}
+			prev = v
+		}
+		fmt.Fprintf(out, "%-*s  %12s  %8s\n", width, filepath.Base(path), value, delta)
+	}
+	fmt.Fprintln(out, sparkline(values))
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		values []float64
+		want   string
+	}{
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8}, "▁▂▃▄▅▆▇█"},
+		{[]float64{8, 1}, "█▁"},
+		{[]float64{5, 5, 5}, "▅▅▅"},
+		{[]float64{0, nan, 10}, "▁ █"},
+		{[]float64{nan, nan}, "  "},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.values); got != tt.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestRunHistory(t *testing.T) {
+	corpus, reports := t.TempDir(), t.TempDir()
+	var paths []string
+	steps := []map[string]string{
+		{"a.txt": "one two"},
+		{"a.txt": "one two three four", "b.txt": "new file here"},
+		{"a.txt": "one two three", "b.txt": "new file here and more"},
+	}
+	for i, files := range steps {
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(corpus, name), []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var out bytes.Buffer
+		if err := run(context.Background(), Options{Path: corpus, Ext: ".txt", Workers: 1, Format: "json"}, &out); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(reports, fmt.Sprintf("run%d.json", i+1))
+		if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	var out bytes.Buffer
+	if err := runHistory(append([]string{"-metric", "total_words"}, paths...), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"run         total_words     delta\n" +
+		"run1.json             2          \n" +
+		"run2.json             7        +5\n" +
+		"run3.json             8        +1\n" +
+		"▁▇█\n"
+	if out.String() != want {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	if err := runHistory(append([]string{"-metric", "word_count", "-file", "b.txt", "-last", "3"}, paths...), &out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 5 || !strings.Contains(lines[1], " - ") || !strings.HasSuffix(lines[3], "+2") || lines[4] != " ▁█" {
+		t.Errorf("expected a gap for the run without b.txt, got:\n%s", out.String())
+	}
+
+	if err := runHistory(append([]string{"-metric", "nope"}, paths...), &out); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
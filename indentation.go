@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Стиль отступов файла
+type Indentation struct {
+	Style      string `json:"style"`
+	Width      int    `json:"width"`
+	TabLines   int    `json:"tab_lines"`
+	SpaceLines int    `json:"space_lines"`
+	Mixed      bool   `json:"mixed"`
+	// строки с отступом не основного стиля и строки пробелами не кратные Width
+	Violations int `json:"violations"`
+	// строки с отступом не основного стиля, заполняется при Examples > 0
+	Examples []LineExample `json:"examples,omitempty"`
+}
+
+// Анализатор отступов: табы или пробелы и типичная ширина отступа.
+// Учитываются только непустые строки с отступом. Строки продолжения (после
+// "\" в конце строки или внутри незакрытых скобок) не влияют на ширину.
+// При Examples > 0 и смешанных отступах в результат попадают до Examples
+// строк с отступом другого стиля
+type IndentationAnalyzer struct {
+	Examples int
+}
+
+// Ширина отступа пробелами: наибольшее число до 8, которому кратны
+// не меньше 90% отступов. Допуск нужен для выравнивания вроде " * " в комментариях
+const (
+	maxIndentWidth  = 8
+	indentTolerance = 0.9
+)
+
+func (a IndentationAnalyzer) Name() string {
+	return "indentation"
+}
+func (a IndentationAnalyzer) Analyze(content string) AnalysisResult {
+	var ind Indentation
+	var widths []int
+	var tabs, spaces []LineExample
+	depth, continued := 0, false
+	for n, line := range strings.Split(content, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		continuation := continued || depth > 0
+		depth = max(0, depth+strings.Count(line, "(")+strings.Count(line, "[")-
+			strings.Count(line, ")")-strings.Count(line, "]"))
+		continued = strings.HasSuffix(line, "\\")
+
+		lead := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		switch {
+		case lead == "":
+			continue
+		case lead[0] == '\t':
+			ind.TabLines++
+			tabs = appendExample(tabs, a.Examples, n+1, line)
+			continue
+		}
+		ind.SpaceLines++
+		spaces = appendExample(spaces, a.Examples, n+1, line)
+		if !continuation {
+			widths = append(widths, len(lead)-len(strings.TrimLeft(lead, " ")))
+		}
+	}
+
+	switch {
+	case ind.TabLines == 0 && ind.SpaceLines == 0:
+		ind.Style = "none"
+	case ind.TabLines >= ind.SpaceLines:
+		ind.Style = "tabs"
+		ind.Width = 1
+		ind.Violations = ind.SpaceLines
+	default:
+		ind.Style = "spaces"
+		ind.Width = indentWidth(widths)
+		ind.Violations = ind.TabLines
+		for _, w := range widths {
+			if w%ind.Width != 0 {
+				ind.Violations++
+			}
+		}
+	}
+	ind.Mixed = ind.TabLines > 0 && ind.SpaceLines > 0
+	if ind.Mixed {
+		if ind.Style == "tabs" {
+			ind.Examples = spaces
+		} else {
+			ind.Examples = tabs
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: a.Name(),
+		Data:         ind,
+	}
+}
+
+func indentWidth(widths []int) int {
+	if len(widths) == 0 {
+		return 1
+	}
+	for w := maxIndentWidth; w > 1; w-- {
+		fit := 0
+		for _, n := range widths {
+			if n%w == 0 {
+				fit++
+			}
+		}
+		if float64(fit) >= indentTolerance*float64(len(widths)) {
+			return w
+		}
+	}
+	return 1
+}
+
+// Группа файла по стилю отступов: tabs, spaces-N или mixed.
+// Файлы без отступов не группируются
+func indentationGroup(ind Indentation) string {
+	switch {
+	case ind.Mixed:
+		return "mixed"
+	case ind.Style == "spaces":
+		return fmt.Sprintf("spaces-%d", ind.Width)
+	case ind.Style == "tabs":
+		return "tabs"
+	}
+	return ""
+}
+
+// Файлы, сгруппированные по стилю отступов
+func groupByIndentation(results []FileAnalysisResult) map[string][]string {
+	groups := make(map[string][]string)
+	for _, res := range results {
+		for _, r := range res.Results {
+			if ind, ok := r.Data.(Indentation); ok {
+				if g := indentationGroup(ind); g != "" {
+					groups[g] = append(groups[g], res.FileName)
+				}
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	return groups
+}
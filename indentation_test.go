@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestIndentationAnalyzerSpaces(t *testing.T) {
+	content := "def f():\n    if x:\n        return 1\n\n    return 2\n"
+	got := IndentationAnalyzer{}.Analyze(content).Data.(Indentation)
+	want := Indentation{Style: "spaces", Width: 4, SpaceLines: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestIndentationAnalyzerTabsMixed(t *testing.T) {
+	content := "func f() {\n\tif x {\n\t\treturn\n  }\n}\n"
+	got := IndentationAnalyzer{}.Analyze(content).Data.(Indentation)
+	if got.Style != "tabs" || !got.Mixed || got.TabLines != 2 || got.SpaceLines != 1 {
+		t.Errorf("expected mixed tabs, got %+v", got)
+	}
+	got = IndentationAnalyzer{}.Analyze("plain\ntext").Data.(Indentation)
+	if got.Style != "none" {
+		t.Errorf("expected none, got %+v", got)
+	}
+}
+
+func TestIndentationExamplesCapped(t *testing.T) {
+	content := "f {\n\ta\n\tb\n  c\n\td\n  e\n  f\n"
+	got := IndentationAnalyzer{Examples: 2}.Analyze(content).Data.(Indentation)
+	want := []LineExample{{4, "  c"}, {6, "  e"}}
+	if got.SpaceLines != 3 || !reflect.DeepEqual(got.Examples, want) {
+		t.Errorf("expected 3 space lines with examples %v, got %+v", want, got)
+	}
+}
+
+func TestIndentationFixtures(t *testing.T) {
+	tests := map[string]Indentation{
+		"tabs.go":     {Style: "tabs", Width: 1, TabLines: 4},
+		"spaces.py":   {Style: "spaces", Width: 4, SpaceLines: 6},
+		"config.yaml": {Style: "spaces", Width: 2, SpaceLines: 6},
+		"mixed.txt":   {Style: "spaces", Width: 4, TabLines: 1, SpaceLines: 3, Mixed: true, Violations: 1},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join("testdata", "indentation", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := IndentationAnalyzer{}.Analyze(string(content)).Data.(Indentation)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestIndentationWidthTolerance(t *testing.T) {
+	content := "/*\n" + strings.Repeat("    a\n", 9) + " * b\n"
+	got := IndentationAnalyzer{}.Analyze(content).Data.(Indentation)
+	if got.Width != 4 || got.Violations != 1 {
+		t.Errorf("expected width 4 with 1 violation, got %+v", got)
+	}
+}
+
+func TestRunIndentationStyles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"tabs.go", "spaces.py", "config.yaml", "mixed.txt"} {
+		content, err := os.ReadFile(filepath.Join("testdata", "indentation", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Workers: 2, Format: "json"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"tabs":     {"tabs.go"},
+		"spaces-4": {"spaces.py"},
+		"spaces-2": {"config.yaml"},
+		"mixed":    {"mixed.txt"},
+	}
+	if !reflect.DeepEqual(report.Summary.IndentationStyles, want) {
+		t.Errorf("expected %v, got %v", want, report.Summary.IndentationStyles)
+	}
+
+	opts.FailIf = "indentation_mixed>0"
+	var failed *FailConditionError
+	if err := run(context.Background(), opts, &out); !errors.As(err, &failed) || failed.Actual != 1 {
+		t.Fatalf("expected fail-if with 1 mixed file, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Формат файла индекса: сигнатура, версия, таблица файлов и списки вхождений.
+// Номера файлов в списках хранятся разностями, все числа - uvarint.
+// Пустой путь в таблице файлов означает удалённый файл (см. indexupdate.go)
+const (
+	indexMagic   = "TXIX"
+	indexVersion = 1
+
+	// пределы для чтения: длины из повреждённого файла не должны приводить к огромным выделениям
+	maxIndexString = 1 << 16
+	maxIndexCount  = 1 << 30
+)
+
+// Вхождение слова в файл
+type Posting struct {
+	FileID int
+	Count  int
+}
+
+// Обратный индекс: слово -> файлы, в которых оно встречается
+type InvertedIndex struct {
+	Files    []string
+	Postings map[string][]Posting
+}
+
+// Построение индекса по частотным картам файлов
+func BuildIndex(results []FileAnalysisResult) *InvertedIndex {
+	idx := &InvertedIndex{Postings: make(map[string][]Posting)}
+
+	sorted := append([]FileAnalysisResult(nil), results...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FilePath < sorted[j].FilePath })
+	for _, res := range sorted {
+		freq := frequencyMap(res)
+		if freq == nil {
+			continue
+		}
+		id := len(idx.Files)
+		idx.Files = append(idx.Files, res.FilePath)
+		for w, c := range freq {
+			idx.Postings[w] = append(idx.Postings[w], Posting{id, c})
+		}
+	}
+	return idx
+}
+
+// Частотная карта слов из результатов файла
+func frequencyMap(res FileAnalysisResult) map[string]int {
+	for _, r := range res.Results {
+		if freq, ok := r.Data.(map[string]int); ok && r.NameAnalyzer == "most_frequent_words" {
+			return freq
+		}
+	}
+	return nil
+}
+
+func writeString(w *bufio.Writer, buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf[:0], uint64(len(s)))
+	w.Write(buf)
+	w.WriteString(s)
+	return buf
+}
+
+// Счётчик записанных байтов для WriteTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Запись индекса в бинарном формате
+func (idx *InvertedIndex) WriteTo(out io.Writer) (int64, error) {
+	counter := &countingWriter{w: out}
+	w := bufio.NewWriter(counter)
+	buf := make([]byte, 0, binary.MaxVarintLen64)
+	putUvarint := func(v int) {
+		buf = binary.AppendUvarint(buf[:0], uint64(v))
+		w.Write(buf)
+	}
+
+	w.WriteString(indexMagic)
+	putUvarint(indexVersion)
+	putUvarint(len(idx.Files))
+	for _, f := range idx.Files {
+		buf = writeString(w, buf, f)
+	}
+
+	terms := make([]string, 0, len(idx.Postings))
+	for t := range idx.Postings {
+		terms = append(terms, t)
+	}
+	sort.Strings(terms)
+	putUvarint(len(terms))
+	for _, t := range terms {
+		buf = writeString(w, buf, t)
+		postings := idx.Postings[t]
+		putUvarint(len(postings))
+		prev := 0
+		for _, p := range postings {
+			putUvarint(p.FileID - prev)
+			putUvarint(p.Count)
+			prev = p.FileID
+		}
+	}
+	err := w.Flush()
+	return counter.n, err
+}
+
+// Чтение индекса, записанного WriteTo
+func ReadIndex(in io.Reader) (*InvertedIndex, error) {
+	r := bufio.NewReader(in)
+	magic := make([]byte, len(indexMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic) != indexMagic {
+		return nil, errors.New("файл не является индексом")
+	}
+
+	var err error
+	readUvarint := func() int {
+		if err != nil {
+			return 0
+		}
+		var v uint64
+		v, err = binary.ReadUvarint(r)
+		if err == nil && v > maxIndexCount {
+			err = fmt.Errorf("число %d больше допустимого", v)
+			return 0
+		}
+		return int(v)
+	}
+	readString := func() string {
+		n := readUvarint()
+		if err == nil && n > maxIndexString {
+			err = fmt.Errorf("длина строки %d больше допустимой", n)
+		}
+		if err != nil {
+			return ""
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return string(b)
+	}
+
+	if v := readUvarint(); err == nil && v != indexVersion {
+		return nil, fmt.Errorf("неподдерживаемая версия индекса %d", v)
+	}
+	idx := &InvertedIndex{Postings: make(map[string][]Posting)}
+	files := readUvarint()
+	for i := 0; i < files && err == nil; i++ {
+		idx.Files = append(idx.Files, readString())
+	}
+	terms := readUvarint()
+	for i := 0; i < terms && err == nil; i++ {
+		t := readString()
+		n := readUvarint()
+		// ёмкость по объявленной длине не выделяется: до конца файла её не проверить
+		postings := make([]Posting, 0, min(n, 1024))
+		id := 0
+		for j := 0; j < n && err == nil; j++ {
+			id += readUvarint()
+			postings = append(postings, Posting{id, readUvarint()})
+			if id >= len(idx.Files) {
+				err = fmt.Errorf("номер файла %d вне таблицы файлов", id)
+			}
+		}
+		idx.Postings[t] = postings
+	}
+	if err != nil {
+		return nil, fmt.Errorf("повреждённый индекс: %w", err)
+	}
+	return idx, nil
+}
+
+// Сохранение индекса в файл.
+// Запись через временный файл, чтобы сбой не оставил повреждённый индекс
+func writeIndexFile(path string, idx *InvertedIndex) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".index-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := idx.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readIndexFile(path string) (*InvertedIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadIndex(f)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"path/filepath"
+	"reflect"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func buildFixtureIndex(t *testing.T) *InvertedIndex {
+	t.Helper()
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt": "go go rust",
+		"b.txt": "go python",
+		"c.txt": "rust python python",
+	})
+	indexPath := filepath.Join(dir, "words.idx")
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", Index: indexPath}
+	if err := run(context.Background(), opts, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := readIndexFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, f := range idx.Files {
+		idx.Files[i] = filepath.Base(f)
+	}
+	return idx
+}
+
+func TestIndexQuery(t *testing.T) {
+	idx := buildFixtureIndex(t)
+
+	cases := []struct {
+		query string
+		want  []QueryMatch
+	}{
+		{"go", []QueryMatch{{"a.txt", 2}, {"b.txt", 1}}},
+		{"go AND rust", []QueryMatch{{"a.txt", 3}}},
+		{"go rust", []QueryMatch{{"a.txt", 3}}},
+		{"go OR python", []QueryMatch{{"a.txt", 2}, {"b.txt", 2}, {"c.txt", 2}}},
+		{"python AND NOT go", []QueryMatch{{"c.txt", 2}}},
+		{"NOT rust", []QueryMatch{{"b.txt", 0}}},
+		{"go AND (rust OR python)", []QueryMatch{{"a.txt", 3}, {"b.txt", 2}}},
+		{"GO", []QueryMatch{{"a.txt", 2}, {"b.txt", 1}}},
+		{"java", []QueryMatch{}},
+	}
+	for _, c := range cases {
+		got, err := idx.Query(c.query)
+		if err != nil {
+			t.Fatalf("%q: %v", c.query, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q: got %v, want %v", c.query, got, c.want)
+		}
+	}
+}
+
+func TestIndexQueryErrors(t *testing.T) {
+	idx := buildFixtureIndex(t)
+	for _, q := range []string{"", "go AND", "(go", "OR go", "go )"} {
+		if _, err := idx.Query(q); err == nil {
+			t.Errorf("%q: expected error", q)
+		}
+	}
+}
+
+func TestIndexRoundTrip(t *testing.T) {
+	idx := &InvertedIndex{
+		Files: []string{"a", "b", "c", "d"},
+		Postings: map[string][]Posting{
+			"x":     {{0, 1}, {3, 300}},
+			"y":     {{1, 2}, {2, 5}, {3, 1}},
+			"слово": {{2, 7}},
+		},
+	}
+	var buf bytes.Buffer
+	n, err := idx.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+	}
+	got, err := ReadIndex(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, idx) {
+		t.Errorf("round trip mismatch: got %v, want %v", got, idx)
+	}
+
+	if _, err := ReadIndex(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Error("expected error for truncated index")
+	}
+	if _, err := ReadIndex(bytes.NewReader([]byte("XXXX"))); err == nil {
+		t.Error("expected error for bad magic")
+	}
+
+	// огромные длины в повреждённом файле - ошибка, а не паника при выделении памяти
+	huge := binary.AppendUvarint(nil, math.MaxUint64)
+	for name, data := range map[string][]byte{
+		"file count":  append(append([]byte(indexMagic), 1), huge...),
+		"path length": append(append([]byte(indexMagic), 1, 1), huge...),
+		"postings":    append(append([]byte(indexMagic), 1, 0, 1, 1, 'x'), binary.AppendUvarint(nil, maxIndexCount)...),
+	} {
+		if _, err := ReadIndex(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: expected error for corrupted index", name)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// Инкрементальное обновление индекса без полной перестройки.
+// Изменённый файл помечается удалённым и добавляется заново с новым номером,
+// поэтому списки вхождений остаются отсортированными по номеру файла.
+// Вхождения удалённых файлов остаются в индексе до сжатия
+
+// Доля удалённых файлов, после которой индекс сжимается
+const indexCompactRatio = 0.5
+
+// Номер файла по пути, -1 если файла нет в индексе
+func (idx *InvertedIndex) fileID(path string) int {
+	for id, f := range idx.Files {
+		if f == path {
+			return id
+		}
+	}
+	return -1
+}
+
+// Удаление файла из индекса, вхождения остаются до Compact
+func (idx *InvertedIndex) RemoveFile(path string) bool {
+	id := idx.fileID(NormalizePath(path))
+	if id < 0 || path == "" {
+		return false
+	}
+	idx.Files[id] = ""
+	return true
+}
+
+// Замена или добавление файла в индексе
+func (idx *InvertedIndex) UpdateFile(res FileAnalysisResult) {
+	idx.RemoveFile(res.FilePath)
+	freq := frequencyMap(res)
+	if freq == nil {
+		return
+	}
+	id := len(idx.Files)
+	idx.Files = append(idx.Files, res.FilePath)
+	for w, c := range freq {
+		idx.Postings[w] = append(idx.Postings[w], Posting{id, c})
+	}
+}
+
+// Количество удалённых файлов, ожидающих сжатия
+func (idx *InvertedIndex) Tombstones() int {
+	n := 0
+	for _, f := range idx.Files {
+		if f == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Удаление вхождений удалённых файлов и перенумерация оставшихся
+func (idx *InvertedIndex) Compact() {
+	remap := make([]int, len(idx.Files))
+	var files []string
+	for id, f := range idx.Files {
+		remap[id] = -1
+		if f != "" {
+			remap[id] = len(files)
+			files = append(files, f)
+		}
+	}
+	for w, postings := range idx.Postings {
+		kept := postings[:0]
+		for _, p := range postings {
+			if id := remap[p.FileID]; id >= 0 {
+				kept = append(kept, Posting{id, p.Count})
+			}
+		}
+		if len(kept) == 0 {
+			delete(idx.Postings, w)
+		} else {
+			idx.Postings[w] = kept
+		}
+	}
+	idx.Files = files
+}
+
+// Обновление индекса на диске по списку изменённых и удалённых файлов.
+// Изменённый файл, которого уже нет на диске, считается удалённым
+func updateIndexFile(indexPath string, changed, removed []string) error {
+	idx, err := readIndexFile(indexPath)
+	if err != nil {
+		return err
+	}
+
+	analyzers := []Analyzer{WordCountAnalyzer{}, MostFrequentWordsAnalyzer{}}
+	for _, path := range removed {
+		idx.RemoveFile(path)
+	}
+	for _, path := range changed {
+		res, err := analyzeFile(path, analyzers, newContentMemo(), 0)
+		if errors.Is(err, fs.ErrNotExist) {
+			idx.RemoveFile(path)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// те же правила, что при полном анализе: файлы короче двух слов не индексируются
+		if res.Results[0].Data.(int) < 2 {
+			idx.RemoveFile(path)
+			continue
+		}
+		idx.UpdateFile(res)
+	}
+
+	if len(idx.Files) > 0 && float64(idx.Tombstones())/float64(len(idx.Files)) > indexCompactRatio {
+		idx.Compact()
+	}
+	return writeIndexFile(indexPath, idx)
+}
+
+// Подкоманда index-update: обновление индекса -index без полного анализа каталога
+func runIndexUpdate(args []string, out io.Writer) error {
+	const usage = "использование: index-update [-removed a.txt,b.txt] <индекс> изменённый_файл..."
+	flags := flag.NewFlagSet("index-update", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	removedList := flags.String("removed", "", "удалённые файлы через запятую")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%s: %w", usage, err)
+	}
+	var removed []string
+	for _, path := range strings.Split(*removedList, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			removed = append(removed, path)
+		}
+	}
+	if flags.NArg() == 0 || (flags.NArg() == 1 && len(removed) == 0) {
+		return errors.New(usage)
+	}
+	if err := updateIndexFile(flags.Arg(0), flags.Args()[1:], removed); err != nil {
+		return fmt.Errorf("ошибка обновления индекса %w", err)
+	}
+	fmt.Fprintf(out, "Индекс обновлён: изменено %d, удалено %d\n", flags.NArg()-1, len(removed))
+	return nil
+}
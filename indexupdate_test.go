@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Индекс каталога с нуля через полный запуск
+func rebuildIndex(t *testing.T, dir string) *InvertedIndex {
+	t.Helper()
+	indexPath := filepath.Join(t.TempDir(), "full.idx")
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", Index: indexPath}
+	if err := run(context.Background(), opts, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	idx, err := readIndexFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return idx
+}
+
+func TestIncrementalIndexMatchesRebuild(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a.txt", "go go rust")
+	b := write("b.txt", "go python")
+	c := write("c.txt", "rust python python")
+
+	indexPath := filepath.Join(t.TempDir(), "words.idx")
+	if err := writeIndexFile(indexPath, rebuildIndex(t, dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	write("a.txt", "python java java")
+	d := write("d.txt", "go rust java")
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := updateIndexFile(indexPath, []string{a, d}, []string{b}); err != nil {
+		t.Fatal(err)
+	}
+	write("c.txt", "rust")
+	if err := runIndexUpdate([]string{indexPath, c}, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := readIndexFile(indexPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := rebuildIndex(t, dir)
+	for _, q := range []string{"go", "java", "python OR rust", "go AND rust", "NOT java", "NOT (go OR java)"} {
+		got, err := updated.Query(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := full.Query(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: incremental %v, rebuild %v", q, got, want)
+		}
+	}
+}
+
+func TestIndexCompact(t *testing.T) {
+	idx := &InvertedIndex{
+		Files: []string{"a", "b", "c"},
+		Postings: map[string][]Posting{
+			"x": {{0, 1}, {2, 3}},
+			"y": {{1, 2}},
+		},
+	}
+	idx.RemoveFile("b")
+	if idx.Tombstones() != 1 {
+		t.Fatalf("expected 1 tombstone, got %d", idx.Tombstones())
+	}
+	idx.Compact()
+	want := &InvertedIndex{
+		Files:    []string{"a", "c"},
+		Postings: map[string][]Posting{"x": {{0, 1}, {1, 3}}},
+	}
+	if !reflect.DeepEqual(idx, want) {
+		t.Errorf("expected %v, got %v", want, idx)
+	}
+}
+
+func TestRunIndexUpdateUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"words.idx"}, {"-bogus", "words.idx", "a.txt"}} {
+		if err := runIndexUpdate(args, io.Discard); err == nil {
+			t.Errorf("%v: expected usage error", args)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Пакет testutil - общие помощники тестов и бенчмарков анализатора
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Временный .txt файл с content, удаляется вместе с временным каталогом теста
+func CreateTempFile(t testing.TB, content string) string {
+	t.Helper()
+
+	f, err := os.CreateTemp(t.TempDir(), "*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	return f.Name()
+}
+
+// count временных файлов с одинаковым содержимым для бенчмарков
+func BenchmarkFiles(b *testing.B, count int, content string) []string {
+	b.Helper()
+
+	files := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		files = append(files, CreateTempFile(b, content))
+	}
+	return files
+}
+
+// Временный каталог с файлами: имя (можно с подкаталогами через /) -> содержимое
+func CreateTempDir(t testing.TB, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// Результат файла, в котором можно найти данные анализатора по имени
+type ResultLookup interface {
+	Result(name string) (any, bool)
+}
+
+// Проверка, что анализатор name вернул want
+func AssertAnalyzerResult(t testing.TB, result ResultLookup, name string, want any) {
+	t.Helper()
+
+	got, ok := result.Result(name)
+	if !ok {
+		t.Errorf("no %s result", name)
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: expected %v (%T), got %v (%T)", name, want, want, got, got)
+	}
+}
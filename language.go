@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Грубое определение языка по алфавиту: "ru" для кириллицы, "en" для латиницы.
+// Уверенность - доля букв преобладающего алфавита, сниженная для коротких текстов.
+type LanguageDetectorAnalyzer struct{}
+
+// Количество слов, начиная с которого длина текста не снижает уверенность
+const languageMinWords = 10
+
+func (l LanguageDetectorAnalyzer) Name() string {
+	return "language"
+}
+func (l LanguageDetectorAnalyzer) ReportsConfidence() bool {
+	return true
+}
+func (l LanguageDetectorAnalyzer) Analyze(content string) AnalysisResult {
+	var cyrillic, latin int
+	for _, r := range content {
+		switch {
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+	if cyrillic+latin == 0 {
+		return AnalysisResult{NameAnalyzer: l.Name(), Data: "unknown"}
+	}
+
+	lang, dominant := "en", latin
+	if cyrillic > latin {
+		lang, dominant = "ru", cyrillic
+	}
+	confidence := float64(dominant) / float64(cyrillic+latin)
+	if words := len(strings.Fields(content)); words < languageMinWords {
+		confidence *= float64(words) / languageMinWords
+	}
+	return AnalysisResult{
+		NameAnalyzer: l.Name(),
+		Data:         lang,
+		Confidence:   confidence,
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestLanguageDetectorAnalyzer(t *testing.T) {
+	res := runAnalyzer(LanguageDetectorAnalyzer{}, "Это достаточно длинное предложение, написанное полностью на русском языке без исключений.")
+	if res.Data != "ru" || res.Confidence != 1 {
+		t.Errorf("expected ru with confidence 1, got %v (%.2f)", res.Data, res.Confidence)
+	}
+}
+
+func TestLanguageDetectorAmbiguous(t *testing.T) {
+	res := runAnalyzer(LanguageDetectorAnalyzer{}, "ok да")
+	if res.Confidence >= 1 {
+		t.Errorf("expected confidence < 1 for ambiguous text, got %.2f", res.Confidence)
+	}
+}
+
+// Нулевая уверенность детектора сохраняется, остальным анализаторам ставится 1
+func TestRunAnalyzerKeepsReportedZeroConfidence(t *testing.T) {
+	if res := runAnalyzer(LanguageDetectorAnalyzer{}, "123 456"); res.Data != "unknown" || res.Confidence != 0 {
+		t.Errorf("expected unknown with confidence 0, got %v (%.2f)", res.Data, res.Confidence)
+	}
+	if res := runAnalyzer(WordCountAnalyzer{}, ""); res.Confidence != 1 {
+		t.Errorf("expected default confidence 1, got %.2f", res.Confidence)
+	}
+}
+
+func TestFilterByConfidence(t *testing.T) {
+	results := []AnalysisResult{
+		runAnalyzer(WordCountAnalyzer{}, "ok да"),
+		runAnalyzer(LanguageDetectorAnalyzer{}, "ok да"),
+	}
+	filtered := filterByConfidence(results, 0.9)
+	if len(filtered) != 1 || filtered[0].NameAnalyzer != "word_count" {
+		t.Errorf("expected only word_count to pass, got %v", filtered)
+	}
+}
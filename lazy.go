@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Анализатор, откладывающий работу до первого обращения к результату.
+// Analyze возвращает *LazyData вместо данных, настоящий Analyze вызывается
+// один раз при первом Result() (или при кодировании в JSON)
+type LazyAnalyzer struct {
+	Analyzer Analyzer
+}
+
+func (l LazyAnalyzer) Name() string {
+	return l.Analyzer.Name()
+}
+func (l LazyAnalyzer) Analyze(content string) AnalysisResult {
+	return AnalysisResult{
+		NameAnalyzer: l.Name(),
+		Data:         &LazyData{compute: func() AnalysisResult { return runAnalyzer(l.Analyzer, content) }},
+	}
+}
+
+// Отложенный результат анализатора
+type LazyData struct {
+	once    sync.Once
+	compute func() AnalysisResult
+	res     AnalysisResult
+}
+
+func (d *LazyData) Result() AnalysisResult {
+	d.once.Do(func() {
+		d.res = d.compute()
+		d.compute = nil
+	})
+	return d.res
+}
+
+// Отказ от невычисленного результата: замыкание с текстом файла больше не
+// удерживается. Result после release возвращает пустой результат
+func (d *LazyData) release() {
+	d.once.Do(func() { d.compute = nil })
+}
+
+func (d *LazyData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Result().Data)
+}
+
+// Отложенный ли результат, такие результаты пропускаются в сводке
+func isLazy(r AnalysisResult) bool {
+	_, ok := r.Data.(*LazyData)
+	return ok
+}
+
+// Результат с вычисленными данными
+func resolveResult(r AnalysisResult) AnalysisResult {
+	if d, ok := r.Data.(*LazyData); ok {
+		return d.Result()
+	}
+	return r
+}
+
+// Результаты без отложенных: их данные освобождаются, чтобы собранные результаты
+// не держали в памяти текст каждого файла. results не изменяется, он может
+// быть общим с memo
+func withoutLazy(results []AnalysisResult) []AnalysisResult {
+	kept := make([]AnalysisResult, 0, len(results))
+	for _, r := range results {
+		if d, ok := r.Data.(*LazyData); ok {
+			d.release()
+			continue
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// Обёртка в LazyAnalyzer всех анализаторов, кроме needed
+func lazyAnalyzers(analyzers []Analyzer, needed map[string]bool) []Analyzer {
+	out := make([]Analyzer, len(analyzers))
+	for i, a := range analyzers {
+		if needed[a.Name()] {
+			out[i] = a
+		} else {
+			out[i] = LazyAnalyzer{a}
+		}
+	}
+	return out
+}
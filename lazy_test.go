@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazyAnalyzerComputesOnce(t *testing.T) {
+	var calls atomic.Int32
+	res := runAnalyzer(LazyAnalyzer{countingAnalyzer{&calls}}, "some content")
+	if calls.Load() != 0 {
+		t.Fatalf("analyzer ran before access, %d calls", calls.Load())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := resolveResult(res); got.Data.(int) != len("some content") || got.Confidence != 1 {
+				t.Errorf("unexpected resolved result %+v", got)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Errorf("expected exactly one call, got %d", calls.Load())
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"data":12`) {
+		t.Errorf("lazy data should marshal as the computed value, got %s", data)
+	}
+}
+
+func TestRunCSVSkipsUnselectedAnalyzers(t *testing.T) {
+	var calls atomic.Int32
+	saved := extraAnalyzers
+	extraAnalyzers = append(extraAnalyzers, func(Options) Analyzer { return countingAnalyzer{&calls} })
+	defer func() { extraAnalyzers = saved }()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello lazy world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "csv", Fields: "name,words"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "name,words\na.txt,3\n" {
+		t.Errorf("unexpected CSV:\n%s", out.String())
+	}
+	if calls.Load() != 0 {
+		t.Errorf("unselected analyzer should not run, got %d calls", calls.Load())
+	}
+}
+
+// Только колонка words: отложенные анализаторы против полного набора
+func BenchmarkLazyWordCountOnly(b *testing.B) {
+	content := strings.Repeat("Hello \"quoted\" world, this is HTTP text.\n", 2000)
+	words, _ := parseFields("words")
+	eager := defaultAnalyzers(Options{})
+	lazy := lazyAnalyzers(eager, map[string]bool{"word_count": true})
+
+	for _, bc := range []struct {
+		name      string
+		analyzers []Analyzer
+	}{
+		{"eager", eager},
+		{"lazy", lazy},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			res := FileAnalysisResult{}
+			for i := 0; i < b.N; i++ {
+				res.Results = analyzeContent(content, bc.analyzers)
+				words[0].Value(res)
+			}
+		})
+	}
+}
+
+func TestWithoutLazyReleasesContent(t *testing.T) {
+	var calls atomic.Int32
+	lazy := runAnalyzer(LazyAnalyzer{countingAnalyzer{&calls}}, "some content")
+	results := []AnalysisResult{{NameAnalyzer: "word_count", Data: 2, Confidence: 1}, lazy}
+
+	kept := withoutLazy(results)
+	if len(kept) != 1 || kept[0].NameAnalyzer != "word_count" || len(results) != 2 {
+		t.Fatalf("expected only eager results kept without touching the input, got %+v", kept)
+	}
+	if d := lazy.Data.(*LazyData); d.compute != nil {
+		t.Error("released lazy result still holds the file content")
+	}
+	if calls.Load() != 0 {
+		t.Errorf("released analyzer should never run, got %d calls", calls.Load())
+	}
+}
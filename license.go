@@ -0,0 +1,105 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Лицензия и годы копирайта из заголовка файла
+type LicenseInfo struct {
+	License   string `json:"license"`
+	YearsFrom int    `json:"years_from,omitempty"`
+	YearsTo   int    `json:"years_to,omitempty"`
+}
+
+// Отпечатки заголовков распространённых лицензий, проверяются по порядку
+var licenseFingerprints = []struct {
+	license string
+	phrases []string
+}{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"LGPL", []string{"gnu lesser general public license"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+}
+
+var (
+	spdxRe      = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+\-]+)`)
+	copyrightRe = regexp.MustCompile(`(?i)copyright\s+(?:\(c\)\s*|©\s*)?(\d{4})(?:\s*[-–]\s*(\d{4}))?`)
+)
+
+// Анализатор лицензионного заголовка в первых Lines строках (0 - 30 строк)
+type LicenseHeaderAnalyzer struct {
+	Lines int
+}
+
+func (l LicenseHeaderAnalyzer) Name() string {
+	return "license"
+}
+func (l LicenseHeaderAnalyzer) Analyze(content string) AnalysisResult {
+	n := l.Lines
+	if n == 0 {
+		n = 30
+	}
+	lines := strings.SplitN(content, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	header := strings.Join(lines, "\n")
+
+	info := LicenseInfo{License: "none"}
+	if m := spdxRe.FindStringSubmatch(header); m != nil {
+		info.License = m[1]
+	} else {
+		// переносы строк и комментарии не должны мешать сравнению фраз
+		normalized := strings.ToLower(strings.Join(strings.FieldsFunc(header, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '#' || r == '*' || r == '/'
+		}), " "))
+		for _, fp := range licenseFingerprints {
+			matched := true
+			for _, p := range fp.phrases {
+				if !strings.Contains(normalized, p) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				info.License = fp.license
+				break
+			}
+		}
+	}
+
+	if m := copyrightRe.FindStringSubmatch(header); m != nil {
+		info.YearsFrom = atoiYear(m[1])
+		info.YearsTo = info.YearsFrom
+		if m[2] != "" {
+			info.YearsTo = atoiYear(m[2])
+		}
+	}
+
+	return AnalysisResult{
+		NameAnalyzer: l.Name(),
+		Data:         info,
+	}
+}
+
+func atoiYear(s string) int {
+	year, _ := strconv.Atoi(s)
+	return year
+}
+
+// Группировка файлов по найденной лицензии
+func groupByLicense(results []FileAnalysisResult) map[string][]string {
+	groups := make(map[string][]string)
+	for _, res := range results {
+		for _, r := range res.Results {
+			if info, ok := r.Data.(LicenseInfo); ok {
+				groups[info.License] = append(groups[info.License], res.FilePath)
+			}
+		}
+	}
+	return groups
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestLicenseHeaderAnalyzer(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    LicenseInfo
+	}{
+		{
+			"mit",
+			"// Copyright (c) 2019-2021 Example Authors\n//\n// Permission is hereby granted, free of charge, to any person obtaining a copy\n",
+			LicenseInfo{"MIT", 2019, 2021},
+		},
+		{
+			"apache",
+			"# Copyright 2020 Example\n#\n# Licensed under the Apache License, Version 2.0 (the \"License\");\n",
+			LicenseInfo{"Apache-2.0", 2020, 2020},
+		},
+		{
+			"gpl3",
+			"/*\n * This program is free software: you can redistribute it under the terms of the\n * GNU General Public License as published by the Free Software Foundation,\n * either version 3 of the License, or (at your option) any later version.\n */\n",
+			LicenseInfo{"GPL-3.0", 0, 0},
+		},
+		{
+			"copyright only",
+			"// Copyright © 2023 Someone\npackage main\n",
+			LicenseInfo{"none", 2023, 2023},
+		},
+		{
+			"after shebang",
+			"#!/usr/bin/env python3\n# SPDX-License-Identifier: BSD-3-Clause\nprint('hi')\n",
+			LicenseInfo{"BSD-3-Clause", 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LicenseHeaderAnalyzer{}.Analyze(tt.content).Data.(LicenseInfo)
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLicenseHeaderAnalyzerLines(t *testing.T) {
+	content := "line\nline\nline\n// SPDX-License-Identifier: MIT\n"
+	got := LicenseHeaderAnalyzer{Lines: 3}.Analyze(content).Data.(LicenseInfo)
+	if got.License != "none" {
+		t.Errorf("expected header beyond 3 lines to be ignored, got %s", got.License)
+	}
+}
+
+func TestRunFailIfLicenseNone(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"licensed.txt":   "SPDX-License-Identifier: MIT\nsome text here",
+		"unlicensed.txt": "just some text here",
+	})
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, License: true, FailIf: "license_none>0"}
+	err := run(context.Background(), opts, &out)
+
+	var failErr *FailConditionError
+	if !errors.As(err, &failErr) || failErr.Actual != 1 {
+		t.Fatalf("expected fail-if with 1 unlicensed file, got %v", err)
+	}
+
+	opts.FailIf = "license_none>1"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Errorf("expected no failure, got %v", err)
+	}
+}
+
+func TestParseFailIf(t *testing.T) {
+	conds, err := parseFailIf("license_none>0, failed_files>=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conds) != 2 || conds[1] != (FailCondition{"failed_files", ">=", 2}) {
+		t.Errorf("unexpected conditions %+v", conds)
+	}
+	for _, bad := range []string{"license_none", ">1", "x>y", "x=>1"} {
+		if _, err := parseFailIf(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
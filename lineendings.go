@@ -0,0 +1,67 @@
+package main
+
+// Количество переводов строк каждого вида
+type LineEndings struct {
+	LF    int  `json:"lf"`
+	CRLF  int  `json:"crlf"`
+	CR    int  `json:"cr"`
+	Mixed bool `json:"mixed"`
+	// строки с переводами не основного вида, заполняется при Examples > 0
+	Examples []LineExample `json:"examples,omitempty"`
+}
+
+// Анализатор видов перевода строки: \n, \r\n и одиночный \r.
+// При Examples > 0 и смешанных переводах в результат попадают до Examples строк
+// с переводом не самого частого вида
+type LineEndingAnalyzer struct {
+	Examples int
+}
+
+func (l LineEndingAnalyzer) Name() string {
+	return "line_endings"
+}
+func (l LineEndingAnalyzer) Analyze(content string) AnalysisResult {
+	var e LineEndings
+	var lf, crlf, cr []LineExample
+	line, start := 1, 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\r':
+			if i+1 < len(content) && content[i+1] == '\n' {
+				e.CRLF++
+				crlf = appendExample(crlf, l.Examples, line, content[start:i])
+				i++
+			} else {
+				e.CR++
+				cr = appendExample(cr, l.Examples, line, content[start:i])
+			}
+		case '\n':
+			e.LF++
+			lf = appendExample(lf, l.Examples, line, content[start:i])
+		default:
+			continue
+		}
+		line, start = line+1, i+1
+	}
+	kinds := 0
+	for _, n := range []int{e.LF, e.CRLF, e.CR} {
+		if n > 0 {
+			kinds++
+		}
+	}
+	e.Mixed = kinds > 1
+	if e.Mixed && l.Examples > 0 {
+		switch {
+		case e.LF >= e.CRLF && e.LF >= e.CR:
+			e.Examples = firstExamples(l.Examples, crlf, cr)
+		case e.CRLF >= e.CR:
+			e.Examples = firstExamples(l.Examples, lf, cr)
+		default:
+			e.Examples = firstExamples(l.Examples, lf, crlf)
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: l.Name(),
+		Data:         e,
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLineEndingAnalyzer(t *testing.T) {
+	got := LineEndingAnalyzer{}.Analyze("a\nb\r\nc\n").Data.(LineEndings)
+	want := LineEndings{LF: 2, CRLF: 1, CR: 0, Mixed: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLineEndingAnalyzerConsistent(t *testing.T) {
+	got := LineEndingAnalyzer{}.Analyze("a\r\nb\r\n").Data.(LineEndings)
+	want := LineEndings{CRLF: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	got = LineEndingAnalyzer{}.Analyze("old\rmac\r").Data.(LineEndings)
+	if got.CR != 2 || got.Mixed {
+		t.Errorf("expected 2 lone CR, got %+v", got)
+	}
+}
+
+func TestLineEndingExamplesCapped(t *testing.T) {
+	content := "a\nb\r\nc\nd\r\ne\r\nf\n" + strings.Repeat("x\n", 10)
+	got := LineEndingAnalyzer{Examples: 2}.Analyze(content).Data.(LineEndings)
+	want := []LineExample{{2, "b"}, {4, "d"}}
+	if got.CRLF != 3 || !reflect.DeepEqual(got.Examples, want) {
+		t.Errorf("expected 3 crlf with examples %v, got %+v", want, got)
+	}
+	if got := (LineEndingAnalyzer{}).Analyze(content).Data.(LineEndings); got.Examples != nil {
+		t.Errorf("expected no examples without Examples, got %v", got.Examples)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Пример строки для -collect-examples: номер строки с 1 и её начало
+type LineExample struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// Длина текста примера в рунах, длинные строки обрезаются
+const lineExampleLen = 60
+
+func newLineExample(line int, text string) LineExample {
+	text = strings.TrimRight(text, "\r")
+	if utf8.RuneCountInString(text) > lineExampleLen {
+		text = string([]rune(text)[:lineExampleLen]) + "..."
+	}
+	return LineExample{Line: line, Text: text}
+}
+
+// Добавление примера, если их меньше limit
+func appendExample(examples []LineExample, limit, line int, text string) []LineExample {
+	if len(examples) >= limit {
+		return examples
+	}
+	return append(examples, newLineExample(line, text))
+}
+
+// Первые limit примеров из нескольких списков в порядке номеров строк
+func firstExamples(limit int, lists ...[]LineExample) []LineExample {
+	var all []LineExample
+	for _, l := range lists {
+		all = append(all, l...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Line < all[j].Line })
+	if len(all) > limit {
+		all = all[:limit]
+	}
+	return all
+}
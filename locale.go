@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// Разбор -locale, пустая строка - поведение без локали
+func parseLocale(s string) (language.Tag, error) {
+	if s == "" {
+		return language.Und, nil
+	}
+	tag, err := language.Parse(s)
+	if err != nil {
+		return language.Und, fmt.Errorf("неизвестная локаль -locale %q", s)
+	}
+	return tag, nil
+}
+
+// Приведение слов к одному виду с учётом локали: нижний регистр по правилам языка
+// (турецкие I и İ), затем полный Unicode case folding (ß -> ss).
+// Не потокобезопасен, создаётся на каждый вызов анализатора
+type caseFolder struct {
+	lower cases.Caser
+	fold  cases.Caser
+}
+
+func newCaseFolder(locale string) *caseFolder {
+	tag, _ := parseLocale(locale)
+	return &caseFolder{lower: cases.Lower(tag), fold: cases.Fold()}
+}
+
+func (f *caseFolder) Fold(word string) string {
+	return f.fold.String(f.lower.String(word))
+}
+
+// Порядок слов с равной частотой в -top-words: по правилам сортировки локали
+func sortWordsCollated(words []WordCount, locale string) {
+	tag, _ := parseLocale(locale)
+	coll := collate.New(tag)
+	sort.SliceStable(words, func(i, j int) bool {
+		if words[i].Count != words[j].Count {
+			return words[i].Count > words[j].Count
+		}
+		return coll.CompareString(words[i].Word, words[j].Word) < 0
+	})
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMostFrequentWordsLocaleFolding(t *testing.T) {
+	tests := []struct {
+		locale, content string
+		want            map[string]int
+	}{
+		{"", "Straße STRASSE", map[string]int{"straße": 1, "strasse": 1}},
+		{"de", "Straße STRASSE strasse", map[string]int{"strasse": 3}},
+		{"", "ISPARTA ısparta", map[string]int{"isparta": 1, "ısparta": 1}},
+		{"tr", "İstanbul istanbul ISPARTA ısparta", map[string]int{"istanbul": 2, "ısparta": 2}},
+		{"ru", "ЁЖ ёж Ёж", map[string]int{"ёж": 3}},
+	}
+	for _, tt := range tests {
+		got := runAnalyzer(MostFrequentWordsAnalyzer{Locale: tt.locale}, tt.content).Data
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("locale %q, content %q: expected %v, got %v", tt.locale, tt.content, tt.want, got)
+		}
+	}
+}
+
+func TestTopWordsLocaleCollation(t *testing.T) {
+	freq := map[string]int{"яма": 1, "ёж": 1, "еда": 1, "жук": 1, "арбуз": 2}
+	words := func(ws []WordCount) []string {
+		var out []string
+		for _, w := range ws {
+			out = append(out, w.Word)
+		}
+		return out
+	}
+	if got, want := words(topWordsLocale(freq, 5, "ru")), []string{"арбуз", "еда", "ёж", "жук", "яма"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ru collation: expected %v, got %v", want, got)
+	}
+	// без локали порядок по кодам символов, как раньше
+	if got, want := words(topWords(freq, 5)), []string{"арбуз", "еда", "жук", "яма", "ёж"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("default order: expected %v, got %v", want, got)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	if _, err := parseLocale("tr"); err != nil {
+		t.Error(err)
+	}
+	if _, err := parseLocale("not a locale!"); err == nil {
+		t.Error("expected error for invalid locale")
+	}
+}
@@ -2,50 +2,76 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"runtime"
-	"sort"
+	"stage5/analysis"
 	"stage5/feature"
 	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
-// Интерфейсы анализаторов
+// Интерфейсы анализаторов.
+// Один экземпляр вызывается из многих горутин, поэтому Analyze должен быть
+// безопасен для конкурентного использования. Анализатор с изменяемым состоянием
+// либо синхронизирует его сам, либо реализует CloneableAnalyzer.
 type Analyzer interface {
 	Analyze(content string) AnalysisResult
 	Name() string
 }
 
-type AnalysisResult struct {
-	NameAnalyzer string
-	Data         any
-}
+// Результаты анализаторов определены в пакете analysis, чтобы их можно было импортировать
+type (
+	AnalysisResult     = analysis.AnalysisResult
+	FileAnalysisResult = analysis.FileAnalysisResult
+)
 
-// Структура, содержащая результаты работы всех анализаторов для файла
-type FileAnalysisResult struct {
-	FileName string
-	Size     int64
-	Results  []AnalysisResult
+// Анализаторы количества слов, линий, общих слов.
+// WordCountAnalyzer при MinWordLength > 0 не считает слова короче MinWordLength рун
+type WordCountAnalyzer struct {
+	MinWordLength int
 }
-
-// Анализаторы количества слов, линий, общих слов
-type WordCountAnalyzer struct{}
 type LineCountAnalyzer struct{}
-type MostFrequentWordsAnalyzer struct{}
+
+// Без Locale слова приводятся через strings.ToLower, с Locale - через caseFolder.
+// Видит текст после -normalize: без неё NFC и NFD формы одного слова считаются разными.
+// При Cap > 0 в результате остаётся не больше Cap самых частых слов (cappedFrequencies)
+type MostFrequentWordsAnalyzer struct {
+	Locale string
+	Cap    int
+}
 
 func (w WordCountAnalyzer) Name() string {
 	return "word_count"
 }
 func (w WordCountAnalyzer) Analyze(content string) AnalysisResult {
-	words := strings.Fields(content)
+	return w.AnalyzeWithArtifacts(content, &Artifacts{Tokens: strings.Fields(content)})
+}
+func (w WordCountAnalyzer) Artifacts() []string {
+	return []string{ArtifactTokens}
+}
+func (w WordCountAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	count := len(art.Tokens)
+	if w.MinWordLength > 1 {
+		count = 0
+		for _, word := range art.Tokens {
+			if utf8.RuneCountInString(word) >= w.MinWordLength {
+				count++
+			}
+		}
+	}
 	return AnalysisResult{
 		NameAnalyzer: w.Name(),
-		Data:         len(words),
+		Data:         count,
 	}
 }
 
@@ -64,9 +90,31 @@ func (m MostFrequentWordsAnalyzer) Name() string {
 	return "most_frequent_words"
 }
 func (m MostFrequentWordsAnalyzer) Analyze(content string) AnalysisResult {
+	return m.AnalyzeWithArtifacts(content, buildArtifacts(content, []Analyzer{m}))
+}
+func (m MostFrequentWordsAnalyzer) Artifacts() []string {
+	if m.Locale != "" {
+		return []string{ArtifactTokens}
+	}
+	return []string{ArtifactLowercased}
+}
+func (m MostFrequentWordsAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	if m.Cap > 0 {
+		return AnalysisResult{
+			NameAnalyzer: m.Name(),
+			Data:         cappedFrequencies(m.words(art), m.Cap),
+		}
+	}
 	freq := make(map[string]int)
-	for _, word := range strings.Fields(content) {
-		freq[strings.ToLower(word)]++
+	if m.Locale != "" {
+		folder := newCaseFolder(m.Locale)
+		for _, word := range art.Tokens {
+			freq[folder.Fold(word)]++
+		}
+	} else {
+		for _, word := range art.Lowercased {
+			freq[word]++
+		}
 	}
 	return AnalysisResult{
 		NameAnalyzer: m.Name(),
@@ -74,13 +122,31 @@ func (m MostFrequentWordsAnalyzer) Analyze(content string) AnalysisResult {
 	}
 }
 
-// Поиск файлов
-func dirTraversal(path, ext string, minSize, maxSize int64) ([]string, error) {
-	var files []string
+// Слова для подсчёта частот в том виде, в каком они попадают в результат
+func (m MostFrequentWordsAnalyzer) words(art *Artifacts) []string {
+	if m.Locale == "" {
+		return art.Lowercased
+	}
+	folder := newCaseFolder(m.Locale)
+	words := make([]string, len(art.Tokens))
+	for i, w := range art.Tokens {
+		words[i] = folder.Fold(w)
+	}
+	return words
+}
+
+// Поиск файлов. Файлы и подкаталоги без прав на чтение пропускаются сразу, чтобы
+// не занимать воркер ошибкой чтения и не прерывать обход, они возвращаются в unreadable
+func dirTraversal(path, ext string, minSize, maxSize int64) (files []string, unreadable []FileError, err error) {
+	return walkFiles(context.Background(), path, ext, minSize, maxSize, nil)
+}
 
+// То же, что dirTraversal, с отменой по ctx. Каталоги из exclude не обходятся,
+// файлы из exclude не попадают в результат
+func walkFiles(ctx context.Context, path, ext string, minSize, maxSize int64, exclude []string) (files []string, unreadable []FileError, err error) {
 	info, err := os.Stat(path)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	checkSize := func(info fs.FileInfo) bool {
@@ -94,17 +160,38 @@ func dirTraversal(path, ext string, minSize, maxSize int64) ([]string, error) {
 	}
 
 	if !info.IsDir() {
-		if strings.HasSuffix(path, ext) && checkSize(info) {
-			return []string{path}, nil
+		if !strings.HasSuffix(path, ext) || !checkSize(info) {
+			return nil, nil, nil
+		}
+		if info.Mode().IsRegular() {
+			if err := readable(path); err != nil {
+				return nil, []FileError{{path, err}}, nil
+			}
 		}
-		return nil, nil
+		return []string{path}, nil, nil
 	}
 
 	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if p != path && len(exclude) > 0 {
+			if rel, relErr := filepath.Rel(path, p); relErr == nil && excluded(rel, exclude) {
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
 		if err != nil {
+			// нечитаемый подкаталог пропускается, корень обхода - ошибка
+			if d != nil && d.IsDir() && p != path && errors.Is(err, fs.ErrPermission) {
+				unreadable = append(unreadable, FileError{p, err})
+				return fs.SkipDir
+			}
 			return err
 		}
-		if d.IsDir() {
+		if d.IsDir() || skipReservedName(d.Name()) {
 			return nil
 		}
 		if !strings.HasSuffix(d.Name(), ext) {
@@ -114,196 +201,413 @@ func dirTraversal(path, ext string, minSize, maxSize int64) ([]string, error) {
 		if err != nil {
 			return err
 		}
-		if checkSize(info) {
-			files = append(files, p)
+		if !checkSize(info) {
+			return nil
 		}
+		// именованный канал блокирует os.Open до появления писателя, его проверяет -fifo
+		if info.Mode().IsRegular() {
+			if err := readable(p); err != nil {
+				unreadable = append(unreadable, FileError{p, err})
+				return nil
+			}
+		}
+		files = append(files, p)
 		return nil
 	})
-	return files, err
+	return files, unreadable, err
+}
+
+// Ошибка открытия файла на чтение, только если не хватает прав.
+// Остальные ошибки открытия достаются воркеру и попадают в отчёт как обычно
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return err
+		}
+		return nil
+	}
+	f.Close()
+	return nil
 }
 
 // Чтение файлов
 func readFileContent(path string) (string, int64, error) {
-	data, err := os.ReadFile(path)
+	fc, err := readFile(path)
 	if err != nil {
 		return "", 0, err
 	}
+	return fc.Text, fc.Info.Size(), nil
+}
+
+// Прочитанный файл с метаданными
+type fileContent struct {
+	Text string
+	Info fs.FileInfo
+	Hash string // SHA-256 исходных байт в hex
+	BOM  int    // длина снятой с Text метки порядка байтов
+}
+
+// Чтение содержимого файла вместе с его метаданными и хешем
+// FIFO читается как поток с таймаутом fifoReadTimeout
+func readFile(path string) (fileContent, error) {
 	info, err := os.Stat(path)
 	if err != nil {
-		return "", 0, err
+		return fileContent{}, err
+	}
+	var data []byte
+	if isFIFO(info) {
+		data, err = readFIFO(path, fifoReadTimeout)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fileContent{}, err
 	}
-	return string(data), info.Size(), nil
+	return newFileContent(data, info), nil
 }
 
-func main() {
-	var wg sync.WaitGroup
+func newFileContent(data []byte, info fs.FileInfo) fileContent {
+	sum := sha256.Sum256(data)
+	text := StripBOM(string(data))
+	return fileContent{
+		Text: text,
+		Info: info,
+		Hash: hex.EncodeToString(sum[:]),
+		BOM:  len(data) - len(text),
+	}
+}
 
-	globalMap := make(map[string]int)
+// Анализаторы из файлов с build-тегами, регистрируются в init().
+// Фабрика возвращает nil, если анализатор не нужен при данных opts.
+var extraAnalyzers []func(opts Options) Analyzer
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// Набор анализаторов по умолчанию, настроенный по opts
+func defaultAnalyzers(opts Options) []Analyzer {
+	analyzers := []Analyzer{
+		WordCountAnalyzer{MinWordLength: opts.MinWordLength},
+		LineCountAnalyzer{},
+		frequencyAnalyzer(opts),
+		TermExtractorAnalyzer{},
+		QuoteAnalyzer{MinListLength: opts.ListQuotes, Examples: opts.CollectExamples},
+		ShebangAnalyzer{},
+		LineEndingAnalyzer{Examples: opts.CollectExamples},
+		IndentationAnalyzer{Examples: opts.CollectExamples},
+		FinalNewlineAnalyzer{},
+	}
+	if opts.Density {
+		analyzers = append(analyzers, DensityAnalyzer{})
+	}
+	if opts.Language {
+		analyzers = append(analyzers, LanguageDetectorAnalyzer{})
+	}
+	if opts.ContentType {
+		analyzers = append(analyzers, TypeAnalyzer{})
+	}
+	if opts.License {
+		analyzers = append(analyzers, LicenseHeaderAnalyzer{})
+	}
+	if opts.DupSentences {
+		analyzers = append(analyzers, SentenceAnalyzer{})
+	}
+	if opts.TopTags > 0 {
+		analyzers = append(analyzers, MarkupAnalyzer{})
+	}
+	if opts.TokenIndex {
+		analyzers = append(analyzers, TokenIndexAnalyzer{})
+	}
+	if opts.SimilarParagraphs > 0 {
+		analyzers = append(analyzers, ParagraphHashAnalyzer{})
+	}
+	if opts.NearDupes > 0 {
+		analyzers = append(analyzers, MinHashAnalyzer{})
+	}
+	if opts.Summary {
+		analyzers = append(analyzers, SummaryAnalyzer{})
+	}
+	if opts.Geo {
+		analyzers = append(analyzers, GeoMentionAnalyzer{})
+	}
+	if opts.Examples > 0 {
+		analyzers = append(analyzers, ExamplesAnalyzer{N: opts.Examples, Candidates: exampleCandidates * opts.TopWords, Seed: opts.Seed, CaseSensitive: opts.CaseSensitive, Locale: opts.Locale})
+	}
+	for _, extra := range extraAnalyzers {
+		if a := extra(opts); a != nil {
+			analyzers = append(analyzers, a)
+		}
+	}
+	return analyzers
+}
 
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, os.Interrupt)
-	go func() {
-		<-sig
-		fmt.Println(" Оуществлено прерывание программы")
-		cancel()
-	}()
+// Анализатор, сам оценивающий уверенность: нулевая уверенность его результата
+// означает "не уверен" и не заменяется значением по умолчанию
+type ConfidenceAnalyzer interface {
+	Analyzer
+	ReportsConfidence() bool
+}
 
-	filePaths := make(chan string, 100)
-	results := make(chan FileAnalysisResult)
-	filteredResults := make(chan FileAnalysisResult)
+// Запуск анализатора с заполнением уверенности по умолчанию
+func runAnalyzer(a Analyzer, content string) AnalysisResult {
+	return withDefaultConfidence(a, a.Analyze(content))
+}
 
-	path := flag.String("path", "", "путь к директории с текстовыми файлами (.txt) или к одному файлу")
-	ext := flag.String("ext", ".txt", "расширение файлов для анализа")
-	workers := flag.Int("workers", runtime.NumCPU(), "количество рабочих горутин")
-	topWords := flag.Int("top-words", 0, "показать N самых часто встречающихся слов")
-	minSize := flag.Int64("min-size", 0, "минимальный размер файла (байты)")
-	maxSize := flag.Int64("max-size", 0, "максимальный размер файла (байты)")
+// Уверенность 1 для анализаторов, которые её не оценивают
+func withDefaultConfidence(a Analyzer, res AnalysisResult) AnalysisResult {
+	if c, ok := a.(ConfidenceAnalyzer); ok && c.ReportsConfidence() {
+		return res
+	}
+	if res.Confidence == 0 {
+		res.Confidence = 1
+	}
+	return res
+}
 
-	flag.Parse()
+// Результаты с уверенностью не ниже minConfidence
+func filterByConfidence(results []AnalysisResult, minConfidence float64) []AnalysisResult {
+	if minConfidence <= 0 {
+		return results
+	}
+	filtered := make([]AnalysisResult, 0, len(results))
+	for _, r := range results {
+		if r.Confidence >= minConfidence {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
 
-	if *path == "" {
-		fmt.Println("необходимо ввести путь")
-		return
+// Запуск всех анализаторов над content параллельно
+func analyzeContent(content string, analyzers []Analyzer) []AnalysisResult {
+	art := buildArtifacts(content, analyzers)
+	var swg sync.WaitGroup
+	analysisResults := make([]AnalysisResult, len(analyzers))
+	for i, analyzer := range analyzers {
+		swg.Add(1)
+		go func(i int, a Analyzer) {
+			defer swg.Done()
+			analysisResults[i] = runAnalyzerWith(a, content, art)
+		}(i, analyzer)
 	}
+	swg.Wait()
+	return analysisResults
+}
+
+// Чтение файла и запуск всех анализаторов параллельно.
+// Файлы больше chunkSize байт (если он задан) делятся на части по строкам.
+func analyzeFile(path string, analyzers []Analyzer, memo *contentMemo, chunkSize int) (FileAnalysisResult, error) {
+	return analyzeFileWith(path, readSource, analyzers, memo, contentAnalyzer(ParallelBoth, chunkSize))
+}
 
-	files, err := dirTraversal(*path, *ext, *minSize, *maxSize)
+func analyzeFileWith(path string, read func(string) (fileContent, error), analyzers []Analyzer, memo *contentMemo, analyze func(string, []Analyzer) []AnalysisResult) (FileAnalysisResult, error) {
+	fc, err := read(path)
 	if err != nil {
-		fmt.Println("ошибка обхода файловой системы", err)
+		return FileAnalysisResult{}, err
+	}
+
+	analysisResults := memo.get(fc.Hash, func() []AnalysisResult {
+		return withBOMOffset(analyze(fc.Text, analyzers), fc.BOM)
+	})
+
+	return FileAnalysisResult{
+		FileName:    NormalizePath(filepath.Base(path)),
+		FilePath:    NormalizePath(path),
+		Size:        fc.Info.Size(),
+		ModTime:     fc.Info.ModTime(),
+		ContentHash: fc.Hash,
+		Results:     analysisResults,
+	}, nil
+}
+
+func main() {
+	var opts Options
+	flag.StringVar(&opts.Path, "path", "", "путь к директории с текстовыми файлами (.txt) или к одному файлу")
+	flag.StringVar(&opts.Ext, "ext", ".txt", "расширение файлов для анализа")
+	flag.IntVar(&opts.Workers, "workers", runtime.NumCPU(), "количество рабочих горутин")
+	flag.IntVar(&opts.TopWords, "top-words", 0, "показать N самых часто встречающихся слов")
+	flag.IntVar(&opts.TopTerms, "top-terms", 0, "показать N самых частых терминов (аббревиатуры, CamelCase)")
+	flag.IntVar(&opts.TopTags, "top-tags", 0, "показать N самых частых тегов HTML/XML по всем файлам")
+	flag.Int64Var(&opts.MinSize, "min-size", 0, "минимальный размер файла (байты)")
+	flag.Int64Var(&opts.MaxSize, "max-size", 0, "максимальный размер файла (байты)")
+	flag.BoolVar(&opts.Density, "density", false, "считать плотность текста и отмечать файлы, выпадающие из корпуса")
+	flag.Float64Var(&opts.DensitySigma, "density-sigma", 2, "порог отклонения плотности от среднего по корпусу (в стандартных отклонениях)")
+	flag.BoolVar(&opts.QuietErrors, "quiet-errors", false, "не печатать ошибки по ходу работы, а вывести сводку в конце")
+	flag.StringVar(&opts.Format, "format", "text", "формат вывода: "+strings.Join(outputFormats, ", ")+"; свой формат задаёт -template")
+	flag.IntVar(&opts.ListQuotes, "list-quotes", 0, "в JSON выводе перечислить цитаты не короче N символов")
+	flag.BoolVar(&opts.Language, "language", false, "определять язык текста файла")
+	flag.Float64Var(&opts.MinConfidence, "min-confidence", 0, "не выводить результаты анализаторов с уверенностью ниже порога")
+	flag.BoolVar(&opts.TokenIndex, "token-index", false, "строить индекс позиций слов (token_index)")
+	flag.BoolVar(&opts.License, "license", false, "определять лицензию по заголовку файла")
+	flag.StringVar(&opts.FailIf, "fail-if", "", "завершиться с ошибкой при выполнении условия, например license_none>0")
+	flag.IntVar(&opts.ChunkSize, "chunk-size", 0, "делить файлы больше N байт на части и анализировать их параллельно")
+	flag.StringVar(&opts.Types, "type", "", "анализировать только файлы с указанными типами содержимого, например text/plain,text/html")
+	flag.BoolVar(&opts.ContentType, "content-type", false, "определять тип содержимого файла и отмечать расхождения с расширением")
+	flag.StringVar(&opts.Color, "color", "auto", "раскраска вывода: auto, always или never")
+	flag.StringVar(&opts.DiffFrom, "diff-from", "", "сравнить с отчётом предыдущего запуска (JSON)")
+	flag.StringVar(&opts.DiffThreshold, "diff-threshold", "10%", "минимальное изменение числа слов или строк для отчёта о разнице")
+	flag.StringVar(&opts.Lang, "lang", "", "анализировать только скрипты на языке по shebang (bash, python, ...), расширение при этом не учитывается")
+	flag.StringVar(&opts.Template, "template", "", "шаблон text/template для вывода (или @файл)")
+	flag.StringVar(&opts.Trend, "trend", "", "слова через запятую, частота которых показывается по периодам времени изменения файлов")
+	flag.IntVar(&opts.TrendTop, "trend-top", 0, "показывать тренд для N самых частых слов вместо -trend")
+	flag.StringVar(&opts.TrendBucket, "trend-bucket", "month", "период тренда: day, week или month")
+	flag.StringVar(&opts.Autotune, "autotune", "", "подобрать число горутин на выборке файлов: report - только показать, use - использовать лучшее")
+	flag.BoolVar(&opts.Histogram, "histogram", false, "показать распределение размеров файлов и количества слов")
+	flag.BoolVar(&opts.DupSentences, "dup-sentences", false, "найти предложения, повторяющиеся в нескольких файлах")
+	flag.Float64Var(&opts.SimilarParagraphs, "similar-paragraphs", 0, "найти похожие абзацы в разных файлах со схожестью не ниже порога (0..1)")
+	flag.Float64Var(&opts.NearDupes, "near-dupes", 0, "найти почти одинаковые файлы со сходством шинглов не ниже порога (0..1)")
+	flag.IntVar(&opts.Cluster, "cluster", 0, "разбить файлы на K кластеров по схожести словаря")
+	flag.BoolVar(&opts.NormalizeEOL, "normalize-eol", false, "показать, сколько строк изменится при приведении переводов строк к LF")
+	flag.BoolVar(&opts.Fix, "fix", false, "вместе с -normalize-eol перезаписать файлы, сохранив оригиналы в .bak")
+	flag.BoolVar(&opts.Dedup, "dedup", false, "анализировать только один файл из группы с одинаковым содержимым, первый по пути")
+	flag.StringVar(&opts.Index, "index", "", "записать обратный индекс слов в файл; обновить его без полного анализа: index-update")
+	socket := flag.String("socket", "", "путь к Unix-сокету для работы в режиме сервера")
+	flag.BoolVar(&opts.IncludeDirs, "include-dirs", false, "включить в отчёт каталоги как записи нулевого размера")
+	flag.StringVar(&opts.StalePolicy, "stale-policy", "warn", "файлы, исчезнувшие во время анализа: error, warn или reread")
+	flag.StringVar(&opts.Fields, "fields", "", "поля вывода через запятую в нужном порядке, например name,words,lines")
+	flag.BoolVar(&opts.Activity, "activity", false, "показать активность корпуса по датам изменения файлов")
+	flag.StringVar(&opts.Exclude, "exclude", "", "исключить файлы по шаблонам через запятую, например logs/*,*.bak")
+	flag.StringVar(&opts.Analyze, "analyze", "", "запустить только перечисленные анализаторы, неизвестные имена пропускаются")
+	flag.BoolVar(&opts.RawNames, "raw-names", false, "не экранировать управляющие символы в именах файлов текстового вывода")
+	flag.StringVar(&opts.Parallel, "parallel", "both", "где применять параллелизм: files, analyzers, both или none")
+	flag.BoolVar(&opts.FailOnReadError, "fail-on-read-error", false, "прервать анализ с ненулевым кодом при первой ошибке чтения файла")
+	flag.StringVar(&opts.GroupBy, "group-by", "", "сгруппировать файлы с подытогами: dir - по каталогу файла, top - по подкаталогу первого уровня")
+	flag.StringVar(&opts.Locale, "locale", "", "локаль для приведения регистра слов и сортировки -top-words, например tr, de, ru")
+	flag.BoolVar(&opts.Summary, "summary", false, "выбрать для каждого файла самое характерное предложение по частоте слов")
+	flag.BoolVar(&opts.CaseSensitive, "case-sensitive", false, "считать частоты слов с учётом регистра")
+	flag.StringVar(&opts.Normalize, "normalize", "none", "нормализация Unicode перед анализом: nfc, nfkc или none")
+	flag.BoolVar(&opts.Geo, "geo", false, "подсчитать упоминания стран и столиц")
+	flag.IntVar(&opts.Examples, "examples", 0, "показать до N примеров предложений для каждого слова из -top-words")
+	flag.BoolVar(&opts.SparseOutput, "sparse", false, "не выводить нулевые результаты анализаторов (0 и пустые словари)")
+	flag.StringVar(&opts.Require, "require", "", "завершиться с ошибкой, если найдено меньше N файлов расширения, например .txt:10")
+	flag.IntVar(&opts.SplitLargeFiles, "split-large-files", 0, "делить файлы больше N байт на части по строкам и анализировать части всеми воркерами")
+	flag.StringVar(&opts.DiffFromRef, "diff-from-ref", "", "сравнить каждый файл пословно с эталонным файлом")
+	flag.StringVar(&opts.FIFO, "fifo", "skip", "именованные каналы: skip - пропустить, read - читать как поток с таймаутом")
+	flag.StringVar(&opts.Severity, "severity", "", "уровни замечаний анализаторов, например line_endings=error,license=critical; включает замечания")
+	flag.StringVar(&opts.MinSeverity, "min-severity", "", "показать замечания не ниже уровня: info, warning, error или critical")
+	flag.StringVar(&opts.FailOnSeverity, "fail-on-severity", "", "завершиться с ненулевым кодом при замечаниях этого уровня и выше")
+	flag.IntVar(&opts.WordDocFreq, "word-document-frequency", 0, "показать N слов, встречающихся в наибольшем числе файлов")
+	flag.IntVar(&opts.TFIDF, "tfidf", 0, "показать для каждого файла N слов с наибольшей оценкой TF-IDF")
+	flag.IntVar(&opts.MaxTokenLength, "max-token-length", 0, "обрезать слова длиннее N байт, 0 - без ограничения")
+	flag.IntVar(&opts.MaxLineLength, "max-line-length", 0, "обрезать строки длиннее N байт, 0 - без ограничения; обрезанный текст не попадает ни в один анализатор, включая word_count")
+	flag.IntVar(&opts.MinWordLength, "min-word-len", 0, "не считать в word_count слова короче N символов")
+	flag.Int64Var(&opts.PreviewBytes, "preview-bytes", 0, "анализировать только первые N байт каждого файла")
+	flag.IntVar(&opts.CollectExamples, "collect-examples", 0, "добавить к смешанным переводам строк, смешанным отступам и незакрытым цитатам до N примеров строк")
+	flag.BoolVar(&opts.CappedFrequencies, "capped-frequencies", false, "хранить для каждого файла только -frequency-cap самых частых слов")
+	flag.IntVar(&opts.FrequencyCap, "frequency-cap", 10000, "число слов на файл в режиме -capped-frequencies")
+	flag.BoolVar(&opts.Reverse, "reverse", false, "обрабатывать файлы в обратном лексическом порядке")
+	flag.BoolVar(&opts.ReportEmpty, "report-empty", false, "не анализировать файлы нулевого размера, а перечислить их в сводке")
+	flag.IntVar(&opts.GOMAXPROCS, "gomaxprocs", 0, "установить GOMAXPROCS на время анализа, 0 - не менять")
+	flag.Int64Var(&opts.Seed, "seed", 0, "зерно случайных чисел для -examples и -cluster, 0 - по времени (зерно печатается); задаёт порядок файлов по путям")
+	flag.BoolVar(&opts.Deterministic, "deterministic", false, "воспроизводимый отчёт: файлы по порядку путей, без времени изменения")
+	flag.BoolVar(&opts.RedactPaths, "redact-paths", false, "заменить пути файлов в отчёте на идентификаторы по хешу, расширения сохраняются")
+	flag.StringVar(&opts.RedactMap, "redact-map", "", "записать соответствие идентификаторов -redact-paths и путей в локальный файл")
+	flag.BoolVar(&opts.DumpTokens, "dump-tokens", false, "напечатать токены файла из -path так, как их видят анализаторы слов, и выйти")
+	flag.BoolVar(&opts.Zipf, "zipf", false, "проверить закон Ципфа для частот слов: показатель степени и R²")
+	flag.StringVar(&opts.PositionsOut, "positions-out", "", "записать в каталог для каждого файла <путь>.positions.json с токенами и их байтовыми смещениями")
+	flag.IntVar(&opts.PipelineBuffer, "pipeline-buffer", -1, "размер буферов каналов результатов: 0 - без буфера, -1 - 2×workers")
+	flag.BoolVar(&opts.PipelineStats, "pipeline-stats", false, "показать в сводке, сколько воркеры ждали вывода результатов")
+	flag.IntVar(&opts.PositionsMax, "positions-max", 100000, "не больше стольких токенов в одном файле -positions-out")
+	flag.StringVar(&opts.CSVColumns, "csv-columns", "", "то же, что -fields")
+	flag.StringVar(&opts.Analyzers, "analyzers", "", "анализаторы через запятую, например word_count,line_count")
+	repeat := flag.Int("repeat", 1, "запустить анализ N раз и показать min/avg/max времени")
+	grpcAddr := flag.String("grpc-addr", "", "адрес для работы в режиме gRPC-сервера, например :50051")
+
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		if err := runQuery(os.Args[2:], os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "index-update" {
+		if err := runIndexUpdate(os.Args[2:], os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 		return
 	}
-	if len(files) == 0 {
-		fmt.Println("файлы с расширением", *ext, "не найдены")
+	if len(os.Args) > 1 && os.Args[1] == "describe" {
+		if err := runDescribe(os.Args[2:], os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return
 	}
-
-	go func() {
-		defer close(filePaths)
-		for _, file := range files {
-			select {
-			case <-ctx.Done():
-				return
-			case filePaths <- file:
-			}
+	if len(os.Args) > 1 && os.Args[1] == "history" {
+		if err := runHistory(os.Args[2:], os.Stdout); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
 		}
+		return
+	}
 
-	}()
+	flag.Parse()
+	setupConsole()
 
-	analyzers := []Analyzer{
-		WordCountAnalyzer{},
-		LineCountAnalyzer{},
-		MostFrequentWordsAnalyzer{},
-	}
-
-	for i := 0; i < *workers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case path, ok := <-filePaths:
-					if !ok {
-						return
-					}
-
-					content, size, err := readFileContent(path)
-					if err != nil {
-						fmt.Println("ошибка обработки файла", err)
-						continue
-					}
-
-					var swg sync.WaitGroup
-					analysisResults := make([]AnalysisResult, len(analyzers))
-					for i, analyzer := range analyzers {
-						swg.Add(1)
-						go func(i int, a Analyzer) {
-							defer swg.Done()
-							analysisResults[i] = a.Analyze(content)
-						}(i, analyzer)
-					}
-					swg.Wait()
-
-					results <- FileAnalysisResult{
-						FileName: filepath.Base(path),
-						Size:     size,
-						Results:  analysisResults,
-					}
-				}
-			}
-		}()
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
 	go func() {
-		wg.Wait()
-		close(results)
+		<-sig
+		fmt.Println(" Оуществлено прерывание программы")
+		cancel()
 	}()
 
-	//фильтрация на лету
-	go func() {
-		defer close(filteredResults)
-		for res := range results {
-			show := true
-			for _, r := range res.Results {
-				if r.NameAnalyzer == "word_count" {
-					if r.Data.(int) < 2 {
-						show = false
-						break
-					}
-				}
-			}
-			if show {
-				filteredResults <- res
-			}
-
+	if opts.GOMAXPROCS != 0 && (*socket != "" || *grpcAddr != "") {
+		fmt.Println("неверные параметры: -gomaxprocs несовместим с -socket и -grpc-addr")
+		os.Exit(2)
+	}
+	if *socket != "" {
+		if err := serveSocket(ctx, *socket); err != nil {
+			fmt.Println("ошибка сервера", err)
 		}
-	}()
-
-	//Сбор результатов в карту и печать
-	var totalWords, totalLines int
-	for result := range filteredResults {
-		fmt.Printf("Файл: %s, size: %d\n", result.FileName, result.Size)
-		for _, res := range result.Results {
-			switch res.NameAnalyzer {
-			case "word_count":
-				fmt.Println(" words:", res.Data.(int))
-				totalWords += res.Data.(int)
-			case "line_count":
-				fmt.Println(" lines:", res.Data.(int))
-				totalLines += res.Data.(int)
-			case "most_frequent_words":
-				freq := res.Data.(map[string]int)
-				for word, count := range freq {
-					globalMap[word] += count
-				}
-			}
+		return
+	}
+	if *grpcAddr != "" {
+		if err := serveGRPC(ctx, *grpcAddr); err != nil {
+			fmt.Println("ошибка gRPC-сервера", err)
 		}
+		return
 	}
 
-	fmt.Printf("\nTOTAL: lines = %d, words = %d\n\n", totalLines, totalWords)
-
-	//Поиск общих слов
-	type WordCount struct {
-		Word  string
-		Count int
+	if err := opts.Validate(); err != nil {
+		fmt.Println("неверные параметры:", err)
+		os.Exit(2)
 	}
-	if *topWords > 0 {
-		var words []WordCount
-		for w, c := range globalMap {
-			words = append(words, WordCount{w, c})
-		}
-		sort.Slice(words, func(i, j int) bool {
-			return words[i].Count > words[j].Count
-		})
-		n := *topWords
-		if n > len(words) {
-			n = len(words)
-		}
-		for i := 0; i < n; i++ {
-			fmt.Printf("Количество слов \"%s\": %d\n", words[i].Word, words[i].Count)
+
+	var err error
+	if *repeat != 1 {
+		// сводка времени не должна ломать JSON и шаблонный вывод
+		report := io.Writer(os.Stdout)
+		if opts.Format != "text" || opts.Template != "" {
+			report = os.Stderr
 		}
+		_, err = runRepeated(ctx, opts, *repeat, os.Stdout, report)
+	} else {
+		err = run(ctx, opts, os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(exitCode(err))
+	}
+	if opts.Format == "text" && opts.Template == "" {
+		feature.Feature()
+	}
+}
+
+// Код завершения для ошибки run: 2 для неверных параметров, 130 для прерывания
+// (как у оболочки после Ctrl-C), 1 для остальных, включая сработавшие -fail-if,
+// -fail-on-read-error, -require и -fail-on-severity
+func exitCode(err error) int {
+	var validationErr *ValidationError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &validationErr):
+		return 2
+	case errors.Is(err, context.Canceled):
+		return 130
 	}
-	feature.Feature()
+	return 1
 }
@@ -0,0 +1,91 @@
+package main
+
+import "strings"
+
+// Структура markdown документа
+type MarkdownStats struct {
+	CodeBlocks int `json:"code_blocks"`
+	// заголовки по уровням: Headings[0] - число "#", Headings[5] - число "######"
+	Headings  [6]int `json:"headings"`
+	ListItems int    `json:"list_items"`
+	// блок кода не закрыт до конца файла
+	UnclosedFence bool `json:"unclosed_fence,omitempty"`
+}
+
+// Анализатор markdown: блоки кода ``` и ~~~, заголовки # и пункты списков.
+// Строки разбираются по очереди, внутри блока кода заголовки и списки не считаются.
+// Заголовки с подчёркиванием (=== и ---) не распознаются
+type MarkdownBlockAnalyzer struct{}
+
+func (m MarkdownBlockAnalyzer) Name() string {
+	return "markdown"
+}
+func (m MarkdownBlockAnalyzer) Analyze(content string) AnalysisResult {
+	var stats MarkdownStats
+	var fence string // открывающая последовательность текущего блока кода
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		// до трёх пробелов отступа, с четырёх начинается блок кода отступом
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > 3 {
+			continue
+		}
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if f := fenceMarker(trimmed); f != "" {
+			fence = f
+			stats.CodeBlocks++
+			continue
+		}
+		if level := headingLevel(trimmed); level > 0 {
+			stats.Headings[level-1]++
+			continue
+		}
+		if isListItem(trimmed) {
+			stats.ListItems++
+		}
+	}
+	stats.UnclosedFence = fence != ""
+	return AnalysisResult{
+		NameAnalyzer: m.Name(),
+		Data:         stats,
+	}
+}
+
+// Открывающая последовательность ``` или ~~~ (не короче трёх символов), иначе ""
+func fenceMarker(line string) string {
+	for _, c := range []string{"`", "~"} {
+		n := len(line) - len(strings.TrimLeft(line, c))
+		if n >= 3 {
+			// после ``` не может быть обратных кавычек, иначе это код в строке
+			if c == "`" && strings.Contains(line[n:], "`") {
+				return ""
+			}
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// Уровень ATX-заголовка "# ..." от 1 до 6, 0 - не заголовок
+func headingLevel(line string) int {
+	n := len(line) - len(strings.TrimLeft(line, "#"))
+	if n < 1 || n > 6 || (len(line) > n && line[n] != ' ' && line[n] != '\t') {
+		return 0
+	}
+	return n
+}
+
+// Пункт списка: "- ", "* ", "+ " или "1. ", "1) "
+func isListItem(line string) bool {
+	if len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && (line[1] == ' ' || line[1] == '\t') {
+		return true
+	}
+	digits := len(line) - len(strings.TrimLeft(line, "0123456789"))
+	return digits > 0 && digits <= 9 && len(line) > digits+1 &&
+		(line[digits] == '.' || line[digits] == ')') && (line[digits+1] == ' ' || line[digits+1] == '\t')
+}
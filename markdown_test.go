@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+const sampleMarkdown = "# Title\n\nIntro text.\n\n## Install\n\n- step one\n- step two\n  * nested\n1. first\n2) second\n\n```go\n# not a heading\n- not a list item\n```\n\n### Usage\n\n~~~~\ncode\n~~~\nstill code\n~~~~\n\n#hashtag is not a heading\n`inline` code and ```three``` ticks\n    # indented code, not a heading\n## Notes ##\n"
+
+func TestMarkdownBlockAnalyzer(t *testing.T) {
+	got := MarkdownBlockAnalyzer{}.Analyze(sampleMarkdown).Data.(MarkdownStats)
+	want := MarkdownStats{CodeBlocks: 2, Headings: [6]int{1, 2, 1}, ListItems: 5}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	got = MarkdownBlockAnalyzer{}.Analyze("# Doc\n```\nnever closed\n# hidden\n").Data.(MarkdownStats)
+	if !got.UnclosedFence || got.CodeBlocks != 1 || got.Headings[0] != 1 {
+		t.Errorf("expected one unclosed block and one heading, got %+v", got)
+	}
+}
+
+func TestRunMarkdownJSON(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"readme.md": sampleMarkdown})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".md", Workers: 1, Format: "json", Analyzers: "word_count,markdown"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	testutil.AssertAnalyzerResult(t, report.Files[0], "markdown", MarkdownStats{CodeBlocks: 2, Headings: [6]int{1, 2, 1}, ListItems: 5})
+}
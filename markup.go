@@ -0,0 +1,135 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Статистика разметки HTML/XML файла
+type MarkupStats struct {
+	Tags      int            `json:"tags"`
+	TagCounts map[string]int `json:"tag_counts,omitempty"`
+	MaxDepth  int            `json:"max_depth"`
+	// незакрытые и закрывающие теги без пары
+	Unclosed int `json:"unclosed"`
+	// байты текста на байт разметки, пробелы между тегами не учитываются
+	TextRatio float64 `json:"text_ratio"`
+}
+
+// Анализатор тегов HTML/XHTML. Разбор потоковый и нестрогий: закрывающий тег
+// закрывает ближайший открытый с тем же именем, пропущенные по пути теги
+// считаются незакрытыми. Пустые элементы (br, img) и элементы с необязательным
+// закрывающим тегом (p, li, td) незакрытыми не считаются
+type MarkupAnalyzer struct{}
+
+var (
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+		"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+	optionalEndTags = map[string]bool{
+		"html": true, "head": true, "body": true, "p": true, "li": true, "dt": true, "dd": true,
+		"option": true, "thead": true, "tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+	}
+)
+
+func (m MarkupAnalyzer) Name() string {
+	return "markup"
+}
+func (m MarkupAnalyzer) Analyze(content string) AnalysisResult {
+	stats := MarkupStats{TagCounts: make(map[string]int)}
+	var stack []string
+	// стек закрывается до i, пропущенные теги считаются незакрытыми
+	closeTo := func(i int) {
+		for _, name := range stack[i:] {
+			if !optionalEndTags[name] {
+				stats.Unclosed++
+			}
+		}
+		stack = stack[:i]
+	}
+	var text, markup int
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF или ошибка чтения, в обоих случаях открытые теги остаются незакрытыми
+			closeTo(0)
+			break
+		}
+		raw := len(z.Raw())
+		if tt == html.TextToken {
+			inCode := len(stack) > 0 && (stack[len(stack)-1] == "script" || stack[len(stack)-1] == "style")
+			if !inCode {
+				text += len(strings.TrimSpace(string(z.Raw())))
+				continue
+			}
+		}
+		markup += raw
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			stats.Tags++
+			stats.TagCounts[tag]++
+			if tt == html.SelfClosingTagToken || voidElements[tag] {
+				stats.MaxDepth = max(stats.MaxDepth, len(stack)+1)
+				continue
+			}
+			// <li> после незакрытого <li> начинает соседний элемент, а не вложенный
+			if optionalEndTags[tag] && len(stack) > 0 && stack[len(stack)-1] == tag {
+				stack = stack[:len(stack)-1]
+			}
+			stack = append(stack, tag)
+			stats.MaxDepth = max(stats.MaxDepth, len(stack))
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			i := len(stack) - 1
+			for i >= 0 && stack[i] != tag {
+				i--
+			}
+			if i < 0 {
+				if !voidElements[tag] {
+					stats.Unclosed++
+				}
+				continue
+			}
+			closeTo(i + 1)
+			stack = stack[:i]
+		}
+	}
+	if markup > 0 {
+		stats.TextRatio = float64(text) / float64(markup)
+	}
+	if len(stats.TagCounts) == 0 {
+		stats.TagCounts = nil
+	}
+	return AnalysisResult{
+		NameAnalyzer: m.Name(),
+		Data:         stats,
+	}
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// N самых частых тегов по всем файлам, при равной частоте - по алфавиту
+func topTags(counts map[string]int, n int) []TagCount {
+	tags := make([]TagCount, 0, len(counts))
+	for t, c := range counts {
+		tags = append(tags, TagCount{t, c})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags[:min(n, len(tags))]
+}
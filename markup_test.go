@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestMarkupAnalyzerWellFormed(t *testing.T) {
+	content := `<!DOCTYPE html>
+<html>
+<head><title>Export</title><meta charset="utf-8"></head>
+<body>
+<div><p>Hello <b>world</b></p><br><img src="a.png"></div>
+<script>if (a < b) { document.write("<div>") }</script>
+</body>
+</html>`
+	got := MarkupAnalyzer{}.Analyze(content).Data.(MarkupStats)
+	wantCounts := map[string]int{"html": 1, "head": 1, "title": 1, "meta": 1, "body": 1, "div": 1, "p": 1, "b": 1, "br": 1, "img": 1, "script": 1}
+	if got.Tags != 11 || got.MaxDepth != 5 || got.Unclosed != 0 || !reflect.DeepEqual(got.TagCounts, wantCounts) {
+		t.Errorf("unexpected stats %+v", got)
+	}
+	if got.TextRatio <= 0 || got.TextRatio >= 1 {
+		t.Errorf("expected text ratio between 0 and 1, got %v", got.TextRatio)
+	}
+}
+
+func TestMarkupAnalyzerUnclosed(t *testing.T) {
+	content := "<div><div><span>text</div>\n<ul><li>one<li>two</ul></em>"
+	got := MarkupAnalyzer{}.Analyze(content).Data.(MarkupStats)
+	// span закрыт через </div>, внешний div не закрыт до конца, </em> без пары
+	if got.Unclosed != 3 || got.MaxDepth != 3 || got.TagCounts["div"] != 2 || got.TagCounts["li"] != 2 {
+		t.Errorf("expected 3 unclosed tags, got %+v", got)
+	}
+}
+
+func TestMarkupAnalyzerXHTML(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<body>
+<p>Line one<br/>line two</p>
+<hr />
+<p><a href="x">link</a></p>
+</body>
+</html>`
+	got := MarkupAnalyzer{}.Analyze(content).Data.(MarkupStats)
+	if got.Tags != 7 || got.Unclosed != 0 || got.MaxDepth != 4 || got.TagCounts["p"] != 2 {
+		t.Errorf("unexpected XHTML stats %+v", got)
+	}
+	if got := (MarkupAnalyzer{}).Analyze("just plain text").Data.(MarkupStats); got.Tags != 0 || got.TagCounts != nil {
+		t.Errorf("expected no tags in plain text, got %+v", got)
+	}
+}
+
+func TestRunTopTags(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.html": "<div><p>first page</p><p>more text</p></div>",
+		"b.html": "<p>second page <a href='#'>link</a></p>",
+	})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".html", Workers: 2, Format: "json", TopTags: 2}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	want := []TagCount{{"p", 3}, {"a", 1}}
+	if !reflect.DeepEqual(report.Summary.TopTags, want) {
+		t.Errorf("expected %v, got %v", want, report.Summary.TopTags)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Предел числа записей кэша: результаты одного файла могут весить как
+// словарь частот, поэтому при большом корпусе старые записи вытесняются
+const maxMemoEntries = 1024
+
+// Кэш результатов анализаторов в пределах одного запуска по хешу содержимого.
+// Файлы с одинаковым содержимым анализируются один раз, пока запись не вытеснена:
+// хранится не больше limit давно не запрашивавшихся записей (LRU).
+type contentMemo struct {
+	mu      sync.Mutex
+	limit   int
+	entries map[string]*list.Element
+	order   *list.List // от недавно запрошенных к давним
+}
+
+type memoEntry struct {
+	key     string
+	once    sync.Once
+	results []AnalysisResult
+}
+
+func newContentMemo() *contentMemo {
+	return &contentMemo{limit: maxMemoEntries, entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// Возвращает сохранённые результаты для хеша key или вычисляет их через compute.
+// Одновременные запросы одинакового содержимого ждут единственного вычисления.
+func (m *contentMemo) get(key string, compute func() []AnalysisResult) []AnalysisResult {
+	m.mu.Lock()
+	el, ok := m.entries[key]
+	if ok {
+		m.order.MoveToFront(el)
+	} else {
+		el = m.order.PushFront(&memoEntry{key: key})
+		m.entries[key] = el
+		if m.order.Len() > m.limit {
+			// ожидающие вытесненной записи получат результат, новые запросы вычислят заново
+			oldest := m.order.Back()
+			m.order.Remove(oldest)
+			delete(m.entries, oldest.Value.(*memoEntry).key)
+		}
+	}
+	e := el.Value.(*memoEntry)
+	m.mu.Unlock()
+
+	e.once.Do(func() {
+		e.results = compute()
+	})
+	return e.results
+}
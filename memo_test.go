@@ -0,0 +1,84 @@
+package main
+
+import (
+	"stage5/internal/testutil"
+	"sync/atomic"
+	"testing"
+)
+
+// Анализатор, считающий количество своих вызовов
+type countingAnalyzer struct {
+	calls *atomic.Int32
+}
+
+func (c countingAnalyzer) Name() string {
+	return "counting"
+}
+func (c countingAnalyzer) Analyze(content string) AnalysisResult {
+	c.calls.Add(1)
+	return AnalysisResult{NameAnalyzer: c.Name(), Data: len(content)}
+}
+
+func TestAnalyzeFileMemo(t *testing.T) {
+	first := testutil.CreateTempFile(t, "same content")
+	second := testutil.CreateTempFile(t, "same content")
+
+	var calls atomic.Int32
+	analyzers := []Analyzer{countingAnalyzer{&calls}}
+	memo := newContentMemo()
+
+	for _, path := range []string{first, second} {
+		res, err := analyzeFile(path, analyzers, memo, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Results[0].Data.(int) != len("same content") {
+			t.Errorf("unexpected result %v", res.Results[0].Data)
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("expected analyzer to run once, ran %d times", calls.Load())
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	first := testutil.CreateTempFile(t, "identical text")
+	second := testutil.CreateTempFile(t, "identical text")
+	other := testutil.CreateTempFile(t, "different text")
+
+	results, err := AnalyzeSequential([]string{first, second, other}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, c := results[0].ContentHash, results[1].ContentHash, results[2].ContentHash
+	if len(a) != 64 {
+		t.Errorf("expected hex SHA-256, got %q", a)
+	}
+	if a != b {
+		t.Errorf("expected identical hashes for identical content, got %s and %s", a, b)
+	}
+	if a == c {
+		t.Error("expected different hash for different content")
+	}
+}
+
+func TestContentMemoEvictsOldest(t *testing.T) {
+	memo := newContentMemo()
+	memo.limit = 2
+	var calls int
+	compute := func() []AnalysisResult {
+		calls++
+		return nil
+	}
+	for _, key := range []string{"a", "b", "a", "c", "a", "b"} {
+		memo.get(key, compute)
+	}
+	// "b" вытеснен при добавлении "c", "a" оставался недавним
+	if calls != 4 {
+		t.Errorf("expected 4 computations, got %d", calls)
+	}
+	if len(memo.entries) != 2 || memo.order.Len() != 2 {
+		t.Errorf("expected 2 cached entries, got %d", len(memo.entries))
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import "sync"
+
+// Сколько файлов воркер накапливает в локальной карте перед слиянием
+const wordMergeEvery = 16
+
+// Общая частотная карта корпуса, в которую воркеры сливают свои частичные карты
+type wordMerger struct {
+	mu     sync.Mutex
+	global map[string]int
+}
+
+func newWordMerger(global map[string]int) *wordMerger {
+	return &wordMerger{global: global}
+}
+
+func (m *wordMerger) merge(partial map[string]int) {
+	m.mu.Lock()
+	for w, c := range partial {
+		m.global[w] += c
+	}
+	m.mu.Unlock()
+}
+
+// Частичная карта одного воркера, сливается раз в every файлов и при flush
+type partialWords struct {
+	merger *wordMerger
+	every  int
+	files  int
+	words  map[string]int
+}
+
+func (m *wordMerger) partial(every int) *partialWords {
+	return &partialWords{merger: m, every: every, words: make(map[string]int)}
+}
+
+func (p *partialWords) add(freq map[string]int) {
+	for w, c := range freq {
+		p.words[w] += c
+	}
+	p.files++
+	if p.files >= p.every {
+		p.flush()
+	}
+}
+
+func (p *partialWords) flush() {
+	if len(p.words) > 0 {
+		p.merger.merge(p.words)
+		p.words = make(map[string]int, len(p.words))
+	}
+	p.files = 0
+}
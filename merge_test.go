@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func mergeCorpus(t testing.TB, n int) (string, []string) {
+	dir := t.TempDir()
+	var paths []string
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+		for j := 0; j <= i%7; j++ {
+			fmt.Fprintf(&b, "common word%d word%d ", j, i%5)
+		}
+		if i%9 == 0 {
+			b.Reset()
+			b.WriteString("lonely")
+		}
+		path := filepath.Join(dir, fmt.Sprintf("f%02d.txt", i))
+		if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return dir, paths
+}
+
+func TestRunMergedTopWordsMatchSequential(t *testing.T) {
+	dir, paths := mergeCorpus(t, 50)
+
+	results, err := AnalyzeSequential(paths, []Analyzer{WordCountAnalyzer{}, MostFrequentWordsAnalyzer{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make(map[string]int)
+	for _, res := range results {
+		if !enoughWords(res) {
+			continue
+		}
+		for w, c := range res.Results[1].Data.(map[string]int) {
+			want[w] += c
+		}
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 4, Format: "json", TopWords: 20}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if got := report.Summary.TopWords; !reflect.DeepEqual(got, topWords(want, 20)) {
+		t.Errorf("merged top words differ from sequential:\n got %v\nwant %v", got, topWords(want, 20))
+	}
+}
+
+func TestWordMergerConcurrent(t *testing.T) {
+	global := make(map[string]int)
+	m := newWordMerger(global)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := m.partial(3)
+			defer p.flush()
+			for j := 0; j < 100; j++ {
+				p.add(map[string]int{"a": 1, "b": 2})
+			}
+		}()
+	}
+	wg.Wait()
+	if global["a"] != 800 || global["b"] != 1600 {
+		t.Errorf("unexpected merged counts %v", global)
+	}
+}
+
+// Сравнение слияния на каждый файл, пачками и через отдельную горутину
+func BenchmarkWordMerge(b *testing.B) {
+	freqs := make([]map[string]int, 64)
+	for i := range freqs {
+		freqs[i] = make(map[string]int)
+		for j := 0; j < 200; j++ {
+			freqs[i][fmt.Sprintf("w%d", (i*37+j)%1000)] = j
+		}
+	}
+	const workers = 8
+	for _, every := range []int{1, wordMergeEvery, len(freqs)} {
+		b.Run(fmt.Sprintf("mutex-every-%d", every), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				m := newWordMerger(make(map[string]int))
+				var wg sync.WaitGroup
+				for w := 0; w < workers; w++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						p := m.partial(every)
+						for _, f := range freqs {
+							p.add(f)
+						}
+						p.flush()
+					}()
+				}
+				wg.Wait()
+			}
+		})
+	}
+	b.Run("channel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			global := make(map[string]int)
+			ch := make(chan map[string]int, workers)
+			done := make(chan struct{})
+			go func() {
+				for f := range ch {
+					for w, c := range f {
+						global[w] += c
+					}
+				}
+				close(done)
+			}()
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for _, f := range freqs {
+						ch <- f
+					}
+				}()
+			}
+			wg.Wait()
+			close(ch)
+			<-done
+		}
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Имя файла для текстового вывода: управляющие символы и некорректный UTF-8
+// заменяются escape-последовательностями в стиле C, чтобы имя с переводом строки
+// не разрывало отчёт. Обычные имена возвращаются без изменений.
+// В JSON экранирование делает encoding/json, в CSV - кавычки encoding/csv
+func escapeName(s string) string {
+	if utf8.ValidString(s) && strings.IndexFunc(s, unicode.IsControl) < 0 {
+		return s
+	}
+	q := strconv.Quote(s)
+	return q[1 : len(q)-1]
+}
+
+// Имя с учётом -raw-names
+func (c colorizer) name(s string) string {
+	if c.rawNames {
+		return s
+	}
+	return escapeName(s)
+}
+
+// Список имён через запятую
+func (c colorizer) names(list []string) string {
+	escaped := make([]string, len(list))
+	for i, s := range list {
+		escaped[i] = c.name(s)
+	}
+	return strings.Join(escaped, ", ")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEscapeName(t *testing.T) {
+	cases := map[string]string{
+		"plain.txt":        "plain.txt",
+		"кириллица.txt":    "кириллица.txt",
+		"new\nline.txt":    `new\nline.txt`,
+		"tab\there.txt":    `tab\there.txt`,
+		"bad\xffutf8.txt":  `bad\xffutf8.txt`,
+		"bell\a\x1b[0m.go": `bell\a\x1b[0m.go`,
+	}
+	for in, want := range cases {
+		if got := escapeName(in); got != want {
+			t.Errorf("escapeName(%q): expected %s, got %s", in, want, got)
+		}
+	}
+}
+
+func TestSpecialFileNamesOutputs(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"new\nline.txt", "tab\there.txt"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("hello special world"), 0o644); err != nil {
+			t.Skip("filesystem refuses special names:", err)
+		}
+	}
+	runFormat := func(opts Options) string {
+		t.Helper()
+		opts.Path, opts.Ext, opts.Workers = dir, ".txt", 1
+		var out bytes.Buffer
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	text := runFormat(Options{})
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "line.txt") {
+			t.Errorf("file name split the text report:\n%s", text)
+		}
+	}
+	if !strings.Contains(text, `Файл: new\nline.txt`) || !strings.Contains(text, `Файл: tab\there.txt`) {
+		t.Errorf("expected escaped names in text output:\n%s", text)
+	}
+	if raw := runFormat(Options{RawNames: true}); !strings.Contains(raw, "Файл: new\nline.txt") {
+		t.Errorf("-raw-names should keep names as is:\n%s", raw)
+	}
+
+	var report Report
+	if err := json.Unmarshal([]byte(runFormat(Options{Format: "json"})), &report); err != nil {
+		t.Fatal(err)
+	}
+	var jsonNames []string
+	for _, f := range report.Files {
+		jsonNames = append(jsonNames, f.FileName)
+	}
+	sort.Strings(jsonNames)
+	if strings.Join(jsonNames, "|") != strings.Join(names, "|") {
+		t.Errorf("JSON names do not round-trip: %q", jsonNames)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(runFormat(Options{Format: "csv", Fields: "name,words"}))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", rows)
+	}
+	csvNames := []string{rows[1][0], rows[2][0]}
+	sort.Strings(csvNames)
+	if strings.Join(csvNames, "|") != strings.Join(names, "|") {
+		t.Errorf("CSV names do not round-trip: %q", csvNames)
+	}
+}
+
+// Путь длиннее MAX_PATH Windows (260 символов): на Windows пакет os сам переходит
+// на длинные пути, обход должен их находить
+func TestLongPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	deep := dir
+	for len(deep) < 300 {
+		deep = filepath.Join(deep, strings.Repeat("d", 40))
+	}
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Skip("filesystem refuses long paths:", err)
+	}
+	if err := os.WriteFile(filepath.Join(deep, "a.txt"), []byte("deep file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	files, _, err := dirTraversal(dir, ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || len(files[0]) < 300 {
+		t.Errorf("expected the deep file, got %v", files)
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Параметры поиска почти одинаковых файлов
+const (
+	minHashSize     = 64   // число хеш-функций в подписи
+	shingleWords    = 3    // слов в шингле
+	maxNearDupFiles = 5000 // предел числа сравниваемых файлов, сравнение попарное
+)
+
+// Подпись MinHash файла: минимумы хешей шинглов по minHashSize хеш-функциям
+type MinHashSignature []uint32
+
+// Анализатор для -near-dupes: подпись MinHash по шинглам из shingleWords слов.
+// Доля совпадающих минимумов двух подписей оценивает сходство Жаккара множеств шинглов
+type MinHashAnalyzer struct{}
+
+func (m MinHashAnalyzer) Name() string {
+	return "minhash"
+}
+func (m MinHashAnalyzer) Analyze(content string) AnalysisResult {
+	return AnalysisResult{
+		NameAnalyzer: m.Name(),
+		Data:         minHash(paragraphWords(content)),
+	}
+}
+
+func minHash(words []string) MinHashSignature {
+	if len(words) == 0 {
+		return nil
+	}
+	sig := make(MinHashSignature, minHashSize)
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	n := max(len(words)-shingleWords+1, 1)
+	for i := 0; i < n; i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:min(i+shingleWords, len(words))], " ")))
+		sum := h.Sum64()
+		for k := range sig {
+			// k-я хеш-функция - перемешивание splitmix64 со своим сдвигом
+			x := sum + uint64(k+1)*0x9e3779b97f4a7c15
+			x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+			x = (x ^ x>>27) * 0x94d049bb133111eb
+			x ^= x >> 31
+			if v := uint32(x); v < sig[k] {
+				sig[k] = v
+			}
+		}
+	}
+	return sig
+}
+
+// Оценка сходства Жаккара по подписям: доля совпавших минимумов
+func minHashSimilarity(a, b MinHashSignature) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// Группа почти одинаковых файлов
+type NearDupCluster struct {
+	Files []string `json:"files"`
+	// наименьшее сходство среди всех пар группы, из-за транзитивности может быть ниже порога
+	Similarity float64 `json:"similarity"`
+}
+
+// Группы файлов со сходством подписей не ниже threshold. Файлы объединяются
+// транзитивно, сравнивается не больше maxNearDupFiles файлов, skipped - число
+// файлов сверх предела, не попавших в сравнение
+func FindNearDuplicates(results []FileAnalysisResult, threshold float64) (clusters []NearDupCluster, skipped int) {
+	type entry struct {
+		file string
+		sig  MinHashSignature
+	}
+	var entries []entry
+	for _, res := range results {
+		for _, r := range res.Results {
+			if sig, ok := r.Data.(MinHashSignature); ok && r.NameAnalyzer == "minhash" && len(sig) > 0 {
+				entries = append(entries, entry{res.FilePath, sig})
+			}
+		}
+	}
+	// порядок результатов зависит от горутин, для воспроизводимости сортируем
+	sort.Slice(entries, func(i, j int) bool { return entries[i].file < entries[j].file })
+	if len(entries) > maxNearDupFiles {
+		skipped = len(entries) - maxNearDupFiles
+		entries = entries[:maxNearDupFiles]
+	}
+
+	parent := make([]int, len(entries))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range entries {
+		for j := i + 1; j < len(entries); j++ {
+			sim := minHashSimilarity(entries[i].sig, entries[j].sig)
+			if sim < threshold {
+				continue
+			}
+			if a, b := find(i), find(j); a != b {
+				parent[b] = a
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range entries {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+	for _, root := range roots {
+		members := groups[root]
+		if len(members) < 2 {
+			continue
+		}
+		cl := NearDupCluster{Similarity: 1}
+		for k, i := range members {
+			cl.Files = append(cl.Files, entries[i].file)
+			for _, j := range members[k+1:] {
+				cl.Similarity = min(cl.Similarity, minHashSimilarity(entries[i].sig, entries[j].sig))
+			}
+		}
+		clusters = append(clusters, cl)
+	}
+	return clusters, skipped
+}
+
+func writeNearDupesText(out io.Writer, c colorizer, clusters []NearDupCluster, skipped int) {
+	fmt.Fprintln(out, "Почти одинаковые файлы:")
+	for _, cl := range clusters {
+		fmt.Fprintf(out, " %.0f%%: %s\n", cl.Similarity*100, c.names(cl.Files))
+	}
+	if skipped > 0 {
+		fmt.Fprintf(out, " не сравнивалось файлов сверх предела %d: %d\n", maxNearDupFiles, skipped)
+	}
+	fmt.Fprintln(out)
+}
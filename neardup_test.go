@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestNearDuplicates(t *testing.T) {
+	var doc []string
+	for i := 0; i < 40; i++ {
+		doc = append(doc, "line "+strings.Repeat("x", i%7+1)+" of the installation guide explains one more step number "+string(rune('a'+i%26)))
+	}
+	original := strings.Join(doc, "\n")
+	doc[10] = "this line was rewritten completely in the second copy"
+	doc[25] = "and so was this one near the end"
+	edited := strings.Join(doc, "\n")
+
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"guide.txt":      original,
+		"guide-copy.txt": edited,
+		"other.txt":      "Go channels let goroutines communicate and synchronize without explicit locks. " + strings.Repeat("Buffered channels decouple producers from consumers. ", 5),
+	})
+	var out bytes.Buffer
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", NearDupes: 0.7}, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters := report.Summary.NearDuplicates
+	if len(clusters) != 1 {
+		t.Fatalf("expected one cluster, got %+v", clusters)
+	}
+	want := []string{filepath.ToSlash(filepath.Join(dir, "guide-copy.txt")), filepath.ToSlash(filepath.Join(dir, "guide.txt"))}
+	if got := clusters[0].Files; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if s := clusters[0].Similarity; s < 0.7 || s >= 1 {
+		t.Errorf("expected similarity in [0.7, 1), got %v", s)
+	}
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	a := minHash(paragraphWords("the quick brown fox jumps over the lazy dog"))
+	if s := minHashSimilarity(a, a); s != 1 {
+		t.Errorf("identical texts: expected 1, got %v", s)
+	}
+	b := minHash(paragraphWords("completely different words appear in this sentence here"))
+	if s := minHashSimilarity(a, b); s > 0.2 {
+		t.Errorf("unrelated texts: expected low similarity, got %v", s)
+	}
+	if minHash(nil) != nil {
+		t.Error("empty text should have no signature")
+	}
+}
+
+func TestFindNearDuplicatesWeakestPair(t *testing.T) {
+	// a~b и b~c по 80%, a и c совпадают только на 60%
+	sig := func(diff ...int) MinHashSignature {
+		s := make(MinHashSignature, 10)
+		for _, i := range diff {
+			s[i] = 1
+		}
+		return s
+	}
+	results := []FileAnalysisResult{
+		{FilePath: "a", Results: []AnalysisResult{{NameAnalyzer: "minhash", Data: sig()}}},
+		{FilePath: "b", Results: []AnalysisResult{{NameAnalyzer: "minhash", Data: sig(0, 1)}}},
+		{FilePath: "c", Results: []AnalysisResult{{NameAnalyzer: "minhash", Data: sig(0, 1, 2, 3)}}},
+	}
+	clusters, skipped := FindNearDuplicates(results, 0.8)
+	if skipped != 0 {
+		t.Errorf("expected nothing skipped, got %d", skipped)
+	}
+	if len(clusters) != 1 || len(clusters[0].Files) != 3 {
+		t.Fatalf("expected one transitive cluster of three files, got %+v", clusters)
+	}
+	if s := clusters[0].Similarity; s != 0.6 {
+		t.Errorf("expected similarity of the weakest pair 0.6, got %v", s)
+	}
+}
+
+func TestFindNearDuplicatesSkipped(t *testing.T) {
+	results := make([]FileAnalysisResult, maxNearDupFiles+3)
+	for i := range results {
+		results[i] = FileAnalysisResult{
+			FilePath: fmt.Sprintf("f%05d", i),
+			Results:  []AnalysisResult{{NameAnalyzer: "minhash", Data: MinHashSignature{uint32(i)}}},
+		}
+	}
+	if _, skipped := FindNearDuplicates(results, 0.9); skipped != 3 {
+		t.Errorf("expected 3 skipped files, got %d", skipped)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Неверные параметры запуска, main завершает программу с кодом 2
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string { return e.Err.Error() }
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+// Значения -format, свой формат вывода задаёт -template
+var outputFormats = []string{"text", "json", "csv"}
+
+// Проверка параметров до запуска: взаимоисключающие флаги, отрицательные
+// числа и значения, которые не разбираются. Возвращает все найденные ошибки сразу
+// в *ValidationError
+func (opts Options) Validate() error {
+	var errs []error
+	check := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if opts.Path == "" {
+		check(errors.New("необходимо ввести путь"))
+	}
+	if opts.Format != "" && !slices.Contains(outputFormats, opts.Format) {
+		check(fmt.Errorf("неизвестный формат вывода %q, доступны: %s", opts.Format, strings.Join(outputFormats, ", ")))
+	}
+	// без воркеров файлы никто не читает, и запуск зависает
+	if opts.Workers < 1 {
+		check(fmt.Errorf("-workers должен быть не меньше 1, получено %d", opts.Workers))
+	}
+
+	for _, n := range []struct {
+		flag  string
+		value int64
+	}{
+		{"-top-words", int64(opts.TopWords)},
+		{"-top-terms", int64(opts.TopTerms)},
+		{"-top-tags", int64(opts.TopTags)},
+		{"-min-size", opts.MinSize},
+		{"-max-size", opts.MaxSize},
+		{"-list-quotes", int64(opts.ListQuotes)},
+		{"-chunk-size", int64(opts.ChunkSize)},
+		{"-split-large-files", int64(opts.SplitLargeFiles)},
+		{"-trend-top", int64(opts.TrendTop)},
+		{"-cluster", int64(opts.Cluster)},
+		{"-examples", int64(opts.Examples)},
+		{"-word-document-frequency", int64(opts.WordDocFreq)},
+		{"-tfidf", int64(opts.TFIDF)},
+		{"-max-token-length", int64(opts.MaxTokenLength)},
+		{"-max-line-length", int64(opts.MaxLineLength)},
+		{"-min-word-len", int64(opts.MinWordLength)},
+		{"-preview-bytes", opts.PreviewBytes},
+		{"-collect-examples", int64(opts.CollectExamples)},
+		{"-gomaxprocs", int64(opts.GOMAXPROCS)},
+		{"-positions-max", int64(opts.PositionsMax)},
+	} {
+		if n.value < 0 {
+			check(fmt.Errorf("%s не может быть отрицательным, получено %d", n.flag, n.value))
+		}
+	}
+	if opts.MaxSize > 0 && opts.MinSize > opts.MaxSize {
+		check(fmt.Errorf("-min-size %d больше -max-size %d", opts.MinSize, opts.MaxSize))
+	}
+	if opts.SimilarParagraphs < 0 || opts.SimilarParagraphs > 1 {
+		check(fmt.Errorf("порог -similar-paragraphs должен быть от 0 до 1, получено %v", opts.SimilarParagraphs))
+	}
+	if opts.NearDupes < 0 || opts.NearDupes > 1 {
+		check(fmt.Errorf("порог -near-dupes должен быть от 0 до 1, получено %v", opts.NearDupes))
+	}
+
+	if opts.Examples > 0 && opts.TopWords <= 0 {
+		check(errors.New("-examples работает только вместе с -top-words"))
+	}
+	if opts.CappedFrequencies && opts.FrequencyCap <= 0 {
+		check(fmt.Errorf("-frequency-cap должен быть положительным, получено %d", opts.FrequencyCap))
+	}
+	// обрезанные карты файлов занижают документную частоту и веса редких слов
+	for _, f := range []struct {
+		flag string
+		on   bool
+	}{
+		{"-word-document-frequency", opts.WordDocFreq > 0},
+		{"-tfidf", opts.TFIDF > 0},
+		{"-cluster", opts.Cluster > 0},
+	} {
+		if opts.CappedFrequencies && f.on {
+			check(fmt.Errorf("-capped-frequencies несовместим с %s: ему нужны полные частоты файлов", f.flag))
+		}
+	}
+	if opts.CappedFrequencies && opts.CaseSensitive {
+		check(errors.New("-capped-frequencies несовместим с -case-sensitive"))
+	}
+	if opts.CaseSensitive && opts.Locale != "" {
+		check(errors.New("-case-sensitive несовместим с -locale"))
+	}
+	if opts.Trend != "" && opts.TrendTop > 0 {
+		check(errors.New("-trend несовместим с -trend-top"))
+	}
+	if opts.Fix && !opts.NormalizeEOL {
+		check(errors.New("-fix работает только вместе с -normalize-eol"))
+	}
+	if opts.Template != "" && opts.Format != "" && opts.Format != "text" {
+		check(fmt.Errorf("-template несовместим с -format %s", opts.Format))
+	}
+	if opts.Analyze != "" && opts.Analyzers != "" {
+		check(errors.New("-analyze несовместим с -analyzers"))
+	}
+	// -csv-columns - другое имя -fields, два разных списка полей не сводятся в один
+	if opts.CSVColumns != "" && opts.Fields != "" {
+		check(errors.New("-csv-columns несовместим с -fields"))
+	}
+	if opts.RedactPaths && opts.GroupBy != "" {
+		check(errors.New("-redact-paths несовместим с -group-by: имена групп раскрывают каталоги"))
+	}
+	if opts.RedactMap != "" && !opts.RedactPaths {
+		check(errors.New("-redact-map работает только вместе с -redact-paths"))
+	}
+	if opts.PreviewBytes > 0 && opts.Dedup {
+		check(errors.New("-dedup несовместим с -preview-bytes: дубликаты ищутся по всему файлу"))
+	}
+	if opts.PreviewBytes > 0 && opts.NearDupes > 0 {
+		check(errors.New("-near-dupes несовместим с -preview-bytes: сходство считается по всему файлу"))
+	}
+	if opts.PipelineBuffer < -1 {
+		check(fmt.Errorf("-pipeline-buffer должен быть не меньше -1, получено %d", opts.PipelineBuffer))
+	}
+	if opts.Autotune != "" && opts.Autotune != "report" && opts.Autotune != "use" {
+		check(fmt.Errorf("неизвестный режим -autotune %q", opts.Autotune))
+	}
+
+	failConds, err := parseFailIf(opts.FailIf)
+	check(err)
+	for _, c := range failConds {
+		// без своего анализатора метрика всегда 0 и условие молча не срабатывает
+		if flagName, ok := failIfRequirement(c.Metric, opts); !ok {
+			check(fmt.Errorf("-fail-if %s работает только вместе с %s", c.Metric, flagName))
+		}
+	}
+	check(validStalePolicy(opts.StalePolicy))
+	parallel, err := parseParallelMode(opts.Parallel)
+	check(err)
+	// части -chunk-size анализируют горутины анализаторов, части -split-large-files - воркеры
+	if err == nil && opts.ChunkSize > 0 && !parallel.analyzers() {
+		check(fmt.Errorf("-chunk-size несовместим с -parallel %s: части анализируются параллельно только в режимах analyzers и both", parallel))
+	}
+	if err == nil && opts.SplitLargeFiles > 0 && !parallel.files() {
+		check(fmt.Errorf("-split-large-files несовместим с -parallel %s: части анализируют воркеры", parallel))
+	}
+	_, err = unicodeNormalizer(opts.Normalize)
+	check(err)
+	_, err = parseLocale(opts.Locale)
+	check(err)
+	_, err = parseSeverityOverrides(opts.Severity)
+	check(err)
+	if opts.MinSeverity != "" {
+		if _, err := parseSeverity(opts.MinSeverity); err != nil {
+			check(fmt.Errorf("-min-severity: %w", err))
+		}
+	}
+	if opts.FailOnSeverity != "" {
+		if _, err := parseSeverity(opts.FailOnSeverity); err != nil {
+			check(fmt.Errorf("-fail-on-severity: %w", err))
+		}
+	}
+	check(validFIFOPolicy(opts.FIFO))
+	_, err = parseRequire(opts.Require)
+	check(err)
+	check(validGroupBy(opts.GroupBy))
+	if opts.Fields != "" {
+		if _, err := parseFields(opts.Fields); err != nil {
+			check(fmt.Errorf("-fields: %w", err))
+		}
+	}
+	if opts.CSVColumns != "" {
+		if _, err := parseFields(opts.CSVColumns); err != nil {
+			check(fmt.Errorf("-csv-columns: %w", err))
+		}
+	}
+	if opts.TrendBucket != "" {
+		_, err = trendPeriod(time.Time{}, opts.TrendBucket)
+		check(err)
+	}
+	if opts.DiffFrom != "" {
+		_, err = parseDiffThreshold(opts.DiffThreshold)
+		check(err)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{errors.Join(errs...)}
+}
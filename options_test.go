@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{Path: ".", Ext: ".txt", Workers: 2, Format: "json", TrendBucket: "week"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid options, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Options)
+		want   string
+	}{
+		{"no path", func(o *Options) { o.Path = "" }, "путь"},
+		{"unknown format", func(o *Options) { o.Format = "xml" }, "формат"},
+		{"negative workers", func(o *Options) { o.Workers = -1 }, "-workers"},
+		{"zero workers", func(o *Options) { o.Workers = 0 }, "-workers"},
+		{"negative top words", func(o *Options) { o.TopWords = -5 }, "-top-words"},
+		{"min size above max", func(o *Options) { o.MinSize, o.MaxSize = 100, 10 }, "-min-size"},
+		{"examples without top words", func(o *Options) { o.Examples = 3 }, "-examples"},
+		{"case sensitive with locale", func(o *Options) { o.CaseSensitive, o.Locale = true, "tr" }, "-locale"},
+		{"trend with trend top", func(o *Options) { o.Trend, o.TrendTop = "go", 3 }, "-trend-top"},
+		{"fix without normalize", func(o *Options) { o.Fix = true }, "-fix"},
+		{"template with json", func(o *Options) { o.Template = "{{.Summary.Files}}" }, "-template"},
+		{"bad fail-if", func(o *Options) { o.FailIf = "files" }, "-fail-if"},
+		{"dedup with preview", func(o *Options) { o.Dedup, o.PreviewBytes = true, 12 }, "-preview-bytes"},
+		{"capped frequencies with tfidf", func(o *Options) { o.CappedFrequencies, o.FrequencyCap, o.TFIDF = true, 10, 5 }, "-tfidf"},
+		{"chunk size without analyzer parallelism", func(o *Options) { o.ChunkSize, o.Parallel = 4096, "files" }, "-parallel files"},
+		{"split without file parallelism", func(o *Options) { o.SplitLargeFiles, o.Parallel = 4096, "analyzers" }, "-parallel analyzers"},
+		{"negative split size", func(o *Options) { o.SplitLargeFiles = -1 }, "-split-large-files"},
+		{"analyze with analyzers", func(o *Options) { o.Analyze, o.Analyzers = "word_count", "line_count" }, "-analyzers"},
+		{"license fail-if without license", func(o *Options) { o.FailIf = "license_none>0" }, "-license"},
+		{"density fail-if without density", func(o *Options) { o.FailIf = "density_outliers>0" }, "-density"},
+		{"type fail-if without content type", func(o *Options) { o.FailIf = "type_mismatches>0" }, "-content-type"},
+		{"csv columns with fields", func(o *Options) { o.CSVColumns, o.Fields = "name", "words" }, "-fields"},
+		{"unknown fail-if metric", func(o *Options) { o.FailIf = "filez>0" }, "filez"},
+		{"bad trend bucket", func(o *Options) { o.TrendBucket = "year" }, "year"},
+		{"similar paragraphs above 1", func(o *Options) { o.SimilarParagraphs = 1.5 }, "-similar-paragraphs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			err := opts.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestOptionsValidateReportsAll(t *testing.T) {
+	opts := Options{Path: ".", Workers: -1, TFIDF: -2, Parallel: "sometimes"}
+	err := opts.Validate()
+	if err == nil {
+		t.Fatal("expected validation errors")
+	}
+	for _, want := range []string{"-workers", "-tfidf", "sometimes"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Параметры поиска похожих абзацев
+const (
+	minParagraphWords    = 5     // более короткие абзацы не сравниваются
+	paragraphPreviewLen  = 60    // длина превью абзаца в рунах
+	maxTrackedParagraphs = 10000 // предел числа сравниваемых абзацев
+)
+
+// Отпечаток абзаца: SimHash слов и короткое превью, сам текст не хранится
+type ParagraphFingerprint struct {
+	Index   int    `json:"index"`
+	Hash    uint64 `json:"hash"`
+	Preview string `json:"preview"`
+}
+
+// Анализатор абзацев: отпечатки абзацев, разделённых пустыми строками
+type ParagraphHashAnalyzer struct{}
+
+func (p ParagraphHashAnalyzer) Name() string {
+	return "paragraph_hashes"
+}
+func (p ParagraphHashAnalyzer) Analyze(content string) AnalysisResult {
+	var prints []ParagraphFingerprint
+	for i, para := range splitParagraphs(content) {
+		words := paragraphWords(para)
+		if len(words) < minParagraphWords {
+			continue
+		}
+		prints = append(prints, ParagraphFingerprint{
+			Index:   i,
+			Hash:    simHash(words),
+			Preview: paragraphPreview(para),
+		})
+	}
+	return AnalysisResult{
+		NameAnalyzer: p.Name(),
+		Data:         prints,
+	}
+}
+
+// Анализатор длины абзацев: число слов в каждом абзаце по порядку.
+// Среднее, минимум и максимум считаются по результату снаружи
+type ParagraphLengthAnalyzer struct{}
+
+func (p ParagraphLengthAnalyzer) Name() string {
+	return "paragraph_lengths"
+}
+func (p ParagraphLengthAnalyzer) Analyze(content string) AnalysisResult {
+	paras := splitParagraphs(content)
+	lengths := make([]int, len(paras))
+	for i, para := range paras {
+		lengths[i] = len(strings.Fields(para))
+	}
+	return AnalysisResult{
+		NameAnalyzer: p.Name(),
+		Data:         lengths,
+	}
+}
+
+// Абзацы текста: блоки, разделённые строками из одних пробелов
+func splitParagraphs(content string) []string {
+	var paras []string
+	var current []string
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				paras = append(paras, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		paras = append(paras, strings.Join(current, "\n"))
+	}
+	return paras
+}
+
+// Слова абзаца в нижнем регистре без окружающей пунктуации
+func paragraphWords(para string) []string {
+	var words []string
+	for _, f := range strings.Fields(para) {
+		f = strings.TrimFunc(strings.ToLower(f), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if f != "" {
+			words = append(words, f)
+		}
+	}
+	return words
+}
+
+// SimHash по словам и парам соседних слов
+func simHash(words []string) uint64 {
+	var weights [64]int
+	add := func(feature string) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		// перемешивание splitmix64: у FNV близкие строки дают близкие биты
+		sum := h.Sum64()
+		sum = (sum ^ sum>>30) * 0xbf58476d1ce4e5b9
+		sum = (sum ^ sum>>27) * 0x94d049bb133111eb
+		sum ^= sum >> 31
+		for b := 0; b < 64; b++ {
+			if sum&(1<<b) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+	for i, w := range words {
+		add(w)
+		if i > 0 {
+			add(words[i-1] + " " + w)
+		}
+	}
+	var hash uint64
+	for b, w := range weights {
+		if w > 0 {
+			hash |= 1 << b
+		}
+	}
+	return hash
+}
+
+// Схожесть отпечатков: доля совпадающих бит
+func simHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+func paragraphPreview(para string) string {
+	preview := strings.Join(strings.Fields(para), " ")
+	if r := []rune(preview); len(r) > paragraphPreviewLen {
+		preview = string(r[:paragraphPreviewLen]) + "…"
+	}
+	return preview
+}
+
+// Ссылка на абзац: файл и номер абзаца в нём, начиная с 0
+type ParagraphRef struct {
+	File      string `json:"file"`
+	Paragraph int    `json:"paragraph"`
+}
+
+// Группа похожих абзацев из разных файлов
+type ParagraphCluster struct {
+	Preview    string         `json:"preview"`
+	Paragraphs []ParagraphRef `json:"paragraphs"`
+	Omitted    int            `json:"omitted,omitempty"` // абзацы сверх maxListedFiles
+}
+
+// Группы абзацев со схожестью отпечатков не ниже threshold, встречающиеся хотя бы в двух файлах.
+// Абзац входит в первую группу, с каждым абзацем которой схож не ниже порога, поэтому
+// цепочка A~B~C при несхожих A и C не склеивается. Сравнивается не больше maxTrackedParagraphs абзацев.
+func FindSimilarParagraphs(results []FileAnalysisResult, threshold float64) []ParagraphCluster {
+	type entry struct {
+		ref  ParagraphRef
+		hash uint64
+		text string
+	}
+	var entries []entry
+	for _, res := range results {
+		for _, r := range res.Results {
+			prints, ok := r.Data.([]ParagraphFingerprint)
+			if !ok || r.NameAnalyzer != "paragraph_hashes" {
+				continue
+			}
+			for _, p := range prints {
+				entries = append(entries, entry{ParagraphRef{res.FilePath, p.Index}, p.Hash, p.Preview})
+			}
+		}
+	}
+	// порядок результатов зависит от горутин, для воспроизводимости сортируем
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ref.File != entries[j].ref.File {
+			return entries[i].ref.File < entries[j].ref.File
+		}
+		return entries[i].ref.Paragraph < entries[j].ref.Paragraph
+	})
+	if len(entries) > maxTrackedParagraphs {
+		entries = entries[:maxTrackedParagraphs]
+	}
+
+	var groups [][]int
+	for i := range entries {
+		placed := false
+		for g, members := range groups {
+			similar := true
+			for _, m := range members {
+				if simHashSimilarity(entries[i].hash, entries[m].hash) < threshold {
+					similar = false
+					break
+				}
+			}
+			if similar {
+				groups[g] = append(members, i)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []int{i})
+		}
+	}
+
+	var clusters []ParagraphCluster
+	for _, members := range groups {
+		files := make(map[string]bool)
+		for _, m := range members {
+			files[entries[m].ref.File] = true
+		}
+		if len(files) < 2 {
+			continue
+		}
+		cl := ParagraphCluster{Preview: entries[members[0]].text}
+		for _, m := range members[:min(len(members), maxListedFiles)] {
+			cl.Paragraphs = append(cl.Paragraphs, entries[m].ref)
+		}
+		cl.Omitted = len(members) - len(cl.Paragraphs)
+		clusters = append(clusters, cl)
+	}
+	return clusters
+}
+
+func writeParagraphClustersText(out io.Writer, c colorizer, clusters []ParagraphCluster) {
+	fmt.Fprintln(out, "Похожие абзацы:")
+	for _, cl := range clusters {
+		refs := make([]string, len(cl.Paragraphs))
+		for i, p := range cl.Paragraphs {
+			refs[i] = fmt.Sprintf("%s#%d", c.name(p.File), p.Paragraph)
+		}
+		if cl.Omitted > 0 {
+			refs = append(refs, fmt.Sprintf("… ещё %d", cl.Omitted))
+		}
+		fmt.Fprintf(out, " %q: %s\n", cl.Preview, strings.Join(refs, ", "))
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	sharedParagraph = "The quick brown fox jumps over the lazy dog while the farmer watches from the old wooden porch and sips his morning coffee slowly."
+	editedParagraph = "Distributed systems must tolerate partial failures because networks drop packets, clocks drift, and disks fill up at the worst possible moment in production."
+	editedVariant   = "Distributed systems must tolerate partial failures because networks drop packets, clocks drift, and disks fill up at the worst possible time in production."
+)
+
+func paragraphResults(t *testing.T, files map[string]string) []FileAnalysisResult {
+	t.Helper()
+	var results []FileAnalysisResult
+	for name, content := range files {
+		results = append(results, FileAnalysisResult{
+			FileName: name,
+			FilePath: name,
+			Results:  []AnalysisResult{runAnalyzer(ParagraphHashAnalyzer{}, content)},
+		})
+	}
+	return results
+}
+
+func TestSplitParagraphs(t *testing.T) {
+	got := splitParagraphs("one\ntwo\n\n  \nthree\r\n\r\nfour\n")
+	want := []string{"one\ntwo", "three", "four"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFindSimilarParagraphs(t *testing.T) {
+	results := paragraphResults(t, map[string]string{
+		"a.txt": "Short intro.\n\n" + sharedParagraph + "\n\n" + editedParagraph,
+		"b.txt": editedVariant + "\n\nGo channels provide a way for goroutines to communicate and synchronize execution without explicit locks.\n\n" + sharedParagraph,
+		"c.txt": "Bananas are rich in potassium and make a convenient snack for hikers climbing steep mountain trails in summer.",
+	})
+
+	exact := FindSimilarParagraphs(results, 1)
+	if len(exact) != 1 {
+		t.Fatalf("expected only the verbatim paragraph at threshold 1, got %+v", exact)
+	}
+	want := []ParagraphRef{{"a.txt", 1}, {"b.txt", 2}}
+	if !reflect.DeepEqual(exact[0].Paragraphs, want) {
+		t.Errorf("expected %v, got %v", want, exact[0].Paragraphs)
+	}
+	if !strings.HasPrefix(exact[0].Preview, "The quick brown fox") || !strings.HasSuffix(exact[0].Preview, "…") {
+		t.Errorf("unexpected preview %q", exact[0].Preview)
+	}
+
+	near := FindSimilarParagraphs(results, 0.8)
+	if len(near) != 2 {
+		t.Fatalf("expected verbatim and edited paragraphs at threshold 0.8, got %+v", near)
+	}
+	want = []ParagraphRef{{"a.txt", 2}, {"b.txt", 0}}
+	if !reflect.DeepEqual(near[1].Paragraphs, want) {
+		t.Errorf("expected edited pair %v, got %v", want, near[1].Paragraphs)
+	}
+	for _, cl := range near {
+		for _, p := range cl.Paragraphs {
+			if p.File == "c.txt" || p.Paragraph == 1 && p.File == "b.txt" {
+				t.Errorf("unrelated paragraph clustered: %+v", cl)
+			}
+		}
+	}
+}
+
+// Цепочка попарно схожих абзацев не склеивает несхожие концы
+func TestFindSimilarParagraphsNotTransitive(t *testing.T) {
+	at := func(file string, hash uint64) FileAnalysisResult {
+		return FileAnalysisResult{FileName: "same.txt", FilePath: file, Results: []AnalysisResult{{
+			NameAnalyzer: "paragraph_hashes",
+			Data:         []ParagraphFingerprint{{Hash: hash, Preview: file}},
+		}}}
+	}
+	// соседние отпечатки отличаются на 16 бит (схожесть 0.75), крайние - на 32 (0.5)
+	results := []FileAnalysisResult{at("x/a", 0), at("y/b", 0xFFFF), at("z/c", 0xFFFFFFFF)}
+	clusters := FindSimilarParagraphs(results, 0.7)
+	if len(clusters) != 1 || len(clusters[0].Paragraphs) != 2 {
+		t.Fatalf("expected one pair without chaining, got %+v", clusters)
+	}
+	for _, p := range clusters[0].Paragraphs {
+		if p.File == "z/c" {
+			t.Errorf("dissimilar paragraph joined through a chain: %+v", clusters[0])
+		}
+	}
+}
+
+func TestRunSimilarParagraphs(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.txt": sharedParagraph + "\n\nunique words here for the first file only",
+		"b.txt": "another different paragraph with enough words inside\n\n" + sharedParagraph,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, SimilarParagraphs: 0.9}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	pair := filepath.Join(dir, "a.txt") + "#0, " + filepath.Join(dir, "b.txt") + "#1"
+	if !strings.Contains(out.String(), "Похожие абзацы:") || !strings.Contains(out.String(), pair) {
+		t.Errorf("expected similar paragraphs in output, got:\n%s", out.String())
+	}
+
+	opts.SimilarParagraphs = 1.5
+	if err := run(context.Background(), opts, &out); err == nil {
+		t.Error("expected error for threshold above 1")
+	}
+}
+
+func TestParagraphLengthAnalyzer(t *testing.T) {
+	content := "One two three.\nFour five.\n\nSix.\r\n\r\nSeven eight nine ten.\n"
+	got := ParagraphLengthAnalyzer{}.Analyze(content).Data.([]int)
+	want := []int{5, 1, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := (ParagraphLengthAnalyzer{}).Analyze("\n\n").Data.([]int); len(got) != 0 {
+		t.Errorf("expected no paragraphs, got %v", got)
+	}
+}
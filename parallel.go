@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Где применяется параллелизм: между файлами, между анализаторами одного файла,
+// в обоих местах или нигде
+type ParallelMode string
+
+const (
+	ParallelFiles     ParallelMode = "files"
+	ParallelAnalyzers ParallelMode = "analyzers"
+	ParallelBoth      ParallelMode = "both"
+	ParallelNone      ParallelMode = "none"
+)
+
+func parseParallelMode(s string) (ParallelMode, error) {
+	switch m := ParallelMode(s); m {
+	case "":
+		return ParallelBoth, nil
+	case ParallelFiles, ParallelAnalyzers, ParallelBoth, ParallelNone:
+		return m, nil
+	}
+	return "", fmt.Errorf("неизвестный режим -parallel %q, допустимо: files, analyzers, both, none", s)
+}
+
+func (m ParallelMode) files() bool {
+	return m == ParallelFiles || m == ParallelBoth
+}
+
+func (m ParallelMode) analyzers() bool {
+	return m == ParallelAnalyzers || m == ParallelBoth
+}
+
+// Запуск анализаторов над content по очереди в текущей горутине
+func analyzeContentSequential(content string, analyzers []Analyzer) []AnalysisResult {
+	art := buildArtifacts(content, analyzers)
+	results := make([]AnalysisResult, len(analyzers))
+	for i, a := range analyzers {
+		results[i] = runAnalyzerWith(a, content, art)
+	}
+	return results
+}
+
+// Функция анализа содержимого для режима.
+// Деление на части -chunk-size - тоже параллелизм по анализаторам,
+// поэтому без него файл анализируется целиком
+func contentAnalyzer(mode ParallelMode, chunkSize int) func(string, []Analyzer) []AnalysisResult {
+	if !mode.analyzers() {
+		return analyzeContentSequential
+	}
+	return func(content string, analyzers []Analyzer) []AnalysisResult {
+		if chunkSize > 0 && len(content) > chunkSize {
+			return analyzeChunked(content, analyzers, chunkSize)
+		}
+		return analyzeContent(content, analyzers)
+	}
+}
+
+// Анализ списка файлов в заданном режиме; без параллелизма по файлам workers не используется
+func AnalyzeMode(files []string, analyzers []Analyzer, workers int, mode ParallelMode) ([]FileAnalysisResult, error) {
+	if !mode.files() {
+		workers = 1
+	}
+	var out []FileAnalysisResult
+	analyzeFilesFunc(context.Background(), files, analyzers, workers, contentAnalyzer(mode, 0), func(r FileAnalysisResult) {
+		out = append(out, r)
+	})
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func parallelCorpus(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for i := 0; i < 6; i++ {
+		content := strings.Repeat(fmt.Sprintf("line %d with \"some\" words\n", i), i+2)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.txt", i)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParallelModesSameResults(t *testing.T) {
+	dir := parallelCorpus(t)
+	var want []byte
+	for _, mode := range []string{"none", "files", "analyzers", "both"} {
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 3, Format: "json", TopWords: 3, Parallel: mode}
+		// части файлов анализируются параллельно только горутинами анализаторов
+		if mode == "analyzers" || mode == "both" {
+			opts.ChunkSize = 64
+		}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		sort.Slice(report.Files, func(i, j int) bool { return report.Files[i].FileName < report.Files[j].FileName })
+		got, _ := json.Marshal(report)
+		if want == nil {
+			want = got
+		} else if !bytes.Equal(got, want) {
+			t.Errorf("mode %s: results differ from mode none", mode)
+		}
+	}
+}
+
+func TestAnalyzeModeSameResults(t *testing.T) {
+	dir := parallelCorpus(t)
+	files, _, err := dirTraversal(dir, ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzers := defaultAnalyzers(Options{})
+	want, _ := AnalyzeSequential(files, analyzers)
+	for _, mode := range []ParallelMode{ParallelNone, ParallelFiles, ParallelAnalyzers, ParallelBoth} {
+		got, _ := AnalyzeMode(files, analyzers, 3, mode)
+		sort.Slice(got, func(i, j int) bool { return got[i].FilePath < got[j].FilePath })
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("mode %s: results differ from AnalyzeSequential", mode)
+		}
+	}
+}
+
+func TestParseParallelMode(t *testing.T) {
+	if m, err := parseParallelMode(""); err != nil || m != ParallelBoth {
+		t.Errorf("expected both by default, got %v, %v", m, err)
+	}
+	if _, err := parseParallelMode("threads"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+}
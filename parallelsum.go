@@ -0,0 +1,43 @@
+package main
+
+import "sync"
+
+// Параллельная редукция: каждая из workers горутин суммирует свою часть
+// values, затем частичные суммы складываются в вызывающей горутине.
+// Каждая горутина пишет только в свою ячейку partial, поэтому мьютекс не нужен
+func ParallelSum(values []int, workers int) int {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(values) {
+		workers = len(values)
+	}
+	if workers <= 1 {
+		return sequentialSum(values)
+	}
+	chunk := (len(values) + workers - 1) / workers
+	partial := make([]int, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		start := i * chunk
+		end := min(start+chunk, len(values))
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(i int, part []int) {
+			defer wg.Done()
+			partial[i] = sequentialSum(part)
+		}(i, values[start:end])
+	}
+	wg.Wait()
+	return sequentialSum(partial)
+}
+
+func sequentialSum(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestParallelSum(t *testing.T) {
+	values := make([]int, 1001)
+	want := 0
+	for i := range values {
+		values[i] = i
+		want += i
+	}
+	for _, workers := range []int{-1, 0, 1, 2, 3, 7, 64, 2000} {
+		if got := ParallelSum(values, workers); got != want {
+			t.Errorf("workers=%d: expected %d, got %d", workers, want, got)
+		}
+	}
+	if got := ParallelSum(nil, 4); got != 0 {
+		t.Errorf("expected 0 for empty input, got %d", got)
+	}
+}
+
+func sumBenchValues() []int {
+	values := make([]int, 1_000_000)
+	for i := range values {
+		values[i] = i % 97
+	}
+	return values
+}
+
+func BenchmarkSequentialSum(b *testing.B) {
+	values := sumBenchValues()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sequentialSum(values)
+	}
+}
+
+func BenchmarkParallelSum(b *testing.B) {
+	values := sumBenchValues()
+	workers := runtime.NumCPU()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ParallelSum(values, workers)
+	}
+}
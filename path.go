@@ -0,0 +1,8 @@
+package main
+
+import "path/filepath"
+
+// Путь с разделителями "/" на любой ОС, чтобы отчёты совпадали между платформами
+func NormalizePath(p string) string {
+	return filepath.ToSlash(p)
+}
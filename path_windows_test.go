@@ -0,0 +1,45 @@
+//go:build windows
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunNormalizesWindowsPaths(t *testing.T) {
+	if got := NormalizePath(`logs\2024\app.log`); got != "logs/2024/app.log" {
+		t.Errorf("expected forward slashes, got %q", got)
+	}
+
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "nested", "deeper")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "a.txt"), []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", IncludeDirs: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) == 0 {
+		t.Fatal("expected files in report")
+	}
+	for _, res := range report.Files {
+		if strings.Contains(res.FileName, `\`) || strings.Contains(res.FilePath, `\`) {
+			t.Errorf("expected no backslashes, got name %q path %q", res.FileName, res.FilePath)
+		}
+	}
+}
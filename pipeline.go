@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// Размер буферов results и filteredResults: -1 - 2×workers, 0 - без буфера
+func pipelineBuffer(size, workers int) int {
+	if size < 0 {
+		return 2 * workers
+	}
+	return size
+}
+
+// Учёт времени, которое воркеры ждут, пока сборщик примет результат.
+// Большое ожидание значит, что узкое место - вывод, а не анализ
+type stallMeter struct {
+	sends   atomic.Int64
+	blocked atomic.Int64
+	nanos   atomic.Int64
+}
+
+// Отправка v в ch с учётом ожидания. false - ctx отменён до отправки
+func sendMeasured[T any](ctx context.Context, m *stallMeter, ch chan<- T, v T) bool {
+	m.sends.Add(1)
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+	m.blocked.Add(1)
+	start := time.Now()
+	defer func() { m.nanos.Add(int64(time.Since(start))) }()
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Статистика конвейера для -pipeline-stats
+type PipelineStats struct {
+	Buffer       int     `json:"buffer"`
+	Sends        int64   `json:"sends"`
+	BlockedSends int64   `json:"blocked_sends"`
+	StallMs      float64 `json:"stall_ms"` // суммарно по всем воркерам
+}
+
+func (m *stallMeter) stats(buffer int) PipelineStats {
+	return PipelineStats{
+		Buffer:       buffer,
+		Sends:        m.sends.Load(),
+		BlockedSends: m.blocked.Load(),
+		StallMs:      float64(m.nanos.Load()) / float64(time.Millisecond),
+	}
+}
+
+func writePipelineText(out io.Writer, p PipelineStats) {
+	fmt.Fprintf(out, "Конвейер: буфер %d, ожидали сборщика %d из %d отправок, простой воркеров %.1f мс\n\n",
+		p.Buffer, p.BlockedSends, p.Sends, p.StallMs)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"stage5/internal/testutil"
+	"testing"
+	"time"
+)
+
+func TestSendMeasuredStall(t *testing.T) {
+	var m stallMeter
+	ctx := context.Background()
+
+	// в буфере есть место - отправка без ожидания
+	buffered := make(chan int, 1)
+	if !sendMeasured(ctx, &m, buffered, 1) {
+		t.Fatal("send to buffered channel failed")
+	}
+	if s := m.stats(1); s.Sends != 1 || s.BlockedSends != 0 || s.StallMs != 0 {
+		t.Errorf("unexpected stats after buffered send: %+v", s)
+	}
+
+	// получатель появляется через delay - ожидание не меньше delay
+	const delay = 20 * time.Millisecond
+	ch := make(chan int)
+	go func() {
+		time.Sleep(delay)
+		<-ch
+	}()
+	if !sendMeasured(ctx, &m, ch, 2) {
+		t.Fatal("send to unbuffered channel failed")
+	}
+	s := m.stats(0)
+	if s.Sends != 2 || s.BlockedSends != 1 {
+		t.Errorf("expected 2 sends, 1 blocked, got %+v", s)
+	}
+	if s.StallMs < float64(delay/time.Millisecond) {
+		t.Errorf("expected stall >= %v, got %.2fms", delay, s.StallMs)
+	}
+
+	// отмена контекста прерывает ожидание и тоже учитывается
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if sendMeasured(cancelled, &m, ch, 3) {
+		t.Error("send with cancelled context should fail")
+	}
+	if s := m.stats(0); s.BlockedSends != 2 {
+		t.Errorf("expected 2 blocked sends, got %+v", s)
+	}
+}
+
+func TestPipelineBuffer(t *testing.T) {
+	if got := pipelineBuffer(-1, 4); got != 8 {
+		t.Errorf("auto buffer: expected 8, got %d", got)
+	}
+	if got := pipelineBuffer(0, 4); got != 0 {
+		t.Errorf("unbuffered: expected 0, got %d", got)
+	}
+}
+
+// Медленный потребитель вывода, например терминал или конвейер в другую программу
+type slowWriter struct{ delay time.Duration }
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func benchmarkPipeline(b *testing.B, buffer int) {
+	files := make(map[string]string)
+	for i := 0; i < 64; i++ {
+		files[fmt.Sprintf("f%02d.txt", i)] = hugeContent()[:20000]
+	}
+	dir := testutil.CreateTempDir(b, files)
+	opts := Options{Path: dir, Ext: ".txt", Workers: 4, PipelineBuffer: buffer, QuietErrors: true}
+	var out io.Writer = slowWriter{50 * time.Microsecond}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := run(context.Background(), opts, out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPipelineUnbuffered(b *testing.B) { benchmarkPipeline(b, 0) }
+func BenchmarkPipelineBuffered(b *testing.B)   { benchmarkPipeline(b, -1) }
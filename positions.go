@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Токен и его место в исходном файле, до -normalize и обрезки
+type TokenPosition struct {
+	Token  string `json:"token"`
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+}
+
+// Содержимое файла -positions-out
+type PositionsFile struct {
+	File   string          `json:"file"`
+	Tokens []TokenPosition `json:"tokens"`
+	// токенов больше -positions-max, записаны первые
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Кусок обработанного текста [from, from+len) и его источник [orig, orig+origLen).
+// Длины различаются, если -normalize изменил кусок: тогда внутри куска
+// точное смещение неизвестно и берутся его границы
+type offsetPiece struct {
+	from, len     int
+	orig, origLen int
+}
+
+// Соответствие байтов обработанного текста байтам исходного, куски идут по порядку
+type offsetMap []offsetPiece
+
+func (m offsetMap) find(x int) offsetPiece {
+	i := sort.Search(len(m), func(i int) bool { return m[i].from+m[i].len > x })
+	if i == len(m) {
+		i = len(m) - 1
+	}
+	return m[i]
+}
+
+// Исходное смещение начала байта x
+func (m offsetMap) start(x int) int {
+	p := m.find(x)
+	if p.len == p.origLen {
+		return p.orig + x - p.from
+	}
+	return p.orig
+}
+
+// Исходное смещение конца байта x-1 (конец полуинтервала)
+func (m offsetMap) end(x int) int {
+	p := m.find(x - 1)
+	if p.len == p.origLen {
+		return p.orig + x - p.from
+	}
+	return p.orig + p.origLen
+}
+
+// Текст после -normalize и обрезки, как его видят анализаторы, с картой смещений
+// в content. Нормализация идёт по сегментам norm, независимым друг от друга,
+// поэтому каждый сегмент отображается в свой кусок исходного текста
+func preprocessTracked(content, normalize string, maxToken, maxLine int) (string, offsetMap) {
+	text := content
+	m := offsetMap{{0, len(content), 0, len(content)}}
+	var form norm.Form
+	switch normalize {
+	case "nfc":
+		form = norm.NFC
+	case "nfkc":
+		form = norm.NFKC
+	default:
+		normalize = ""
+	}
+	if normalize != "" {
+		var buf []byte
+		m = m[:0]
+		for i := 0; i < len(content); {
+			n := form.NextBoundaryInString(content[i:], true)
+			if n <= 0 {
+				n = len(content) - i
+			}
+			seg := form.String(content[i : i+n])
+			m = append(m, offsetPiece{len(buf), len(seg), i, n})
+			buf = append(buf, seg...)
+			i += n
+		}
+		text = string(buf)
+	}
+
+	var kept offsetMap
+	truncated, _ := truncateLongKeep(text, maxToken, maxLine, func(from, to int) {
+		if to > from {
+			kept = append(kept, offsetPiece{0, to - from, from, to - from})
+		}
+	})
+	if kept == nil {
+		return text, m
+	}
+	// склейка: обрезанный текст -> нормализованный -> исходный
+	var out offsetMap
+	pos := 0
+	for _, k := range kept {
+		for x := k.orig; x < k.orig+k.len; {
+			p := m.find(x)
+			end := min(p.from+p.len, k.orig+k.len)
+			if p.len == p.origLen {
+				out = append(out, offsetPiece{pos, end - x, p.orig + x - p.from, end - x})
+			} else {
+				// кусок нормализации разрезан обрезкой, точные границы внутри него неизвестны
+				out = append(out, offsetPiece{pos, end - x, p.orig, p.origLen})
+			}
+			pos += end - x
+			x = end
+		}
+	}
+	return truncated, out
+}
+
+// Границы слов как у strings.Fields
+func fieldSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				spans = append(spans, [2]int{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i += size
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// Позиции токенов: те же слова и ключи, что у word_count и most_frequent_words,
+// смещения - в исходном content. Не больше limit токенов, truncated - если есть ещё
+func tokenPositions(content string, opts Options, limit int) (positions []TokenPosition, truncated bool) {
+	text, m := preprocessTracked(content, opts.Normalize, opts.MaxTokenLength, opts.MaxLineLength)
+	key := frequencyKey(opts)
+	spans := fieldSpans(text)
+	if limit > 0 && len(spans) > limit {
+		spans, truncated = spans[:limit], true
+	}
+	positions = make([]TokenPosition, len(spans))
+	for i, sp := range spans {
+		from, to := m.start(sp[0]), m.end(sp[1])
+		positions[i] = TokenPosition{Token: key(text[sp[0]:sp[1]]), Offset: from, Length: to - from}
+	}
+	return positions, truncated
+}
+
+// Обёртка конвейера для -positions-out: по исходному тексту, до остальных
+// обёрток, к результатам добавляется "token_positions". Сборщик забирает его
+// из результатов и пишет в отдельный файл
+func positionsAnalyzer(analyze func(string, []Analyzer) []AnalysisResult, opts Options) func(string, []Analyzer) []AnalysisResult {
+	if opts.PositionsOut == "" {
+		return analyze
+	}
+	return func(content string, analyzers []Analyzer) []AnalysisResult {
+		results := analyze(content, analyzers)
+		positions, truncated := tokenPositions(content, opts, opts.PositionsMax)
+		return append(results, AnalysisResult{
+			NameAnalyzer: "token_positions",
+			Data:         PositionsFile{Tokens: positions, Truncated: truncated},
+			Confidence:   1,
+		})
+	}
+}
+
+// Сдвиг позиций на длину метки порядка байтов: анализаторы получают текст
+// без неё, а смещения указывают в исходные байты файла
+func withBOMOffset(results []AnalysisResult, bom int) []AnalysisResult {
+	if bom == 0 {
+		return results
+	}
+	for i, r := range results {
+		p, ok := r.Data.(PositionsFile)
+		if !ok || r.NameAnalyzer != "token_positions" {
+			continue
+		}
+		tokens := make([]TokenPosition, len(p.Tokens))
+		for j, tp := range p.Tokens {
+			tp.Offset += bom
+			tokens[j] = tp
+		}
+		p.Tokens = tokens
+		results[i].Data = p
+	}
+	return results
+}
+
+// Изъятие позиций из результатов файла, как takeExamples
+func takePositions(res *FileAnalysisResult) (PositionsFile, bool) {
+	for i, r := range res.Results {
+		if p, ok := r.Data.(PositionsFile); ok && r.NameAnalyzer == "token_positions" {
+			rest := make([]AnalysisResult, 0, len(res.Results)-1)
+			rest = append(rest, res.Results[:i]...)
+			res.Results = append(rest, res.Results[i+1:]...)
+			return p, true
+		}
+	}
+	return PositionsFile{}, false
+}
+
+// Имя файла для -positions-out: путь относительно корня анализа или идентификатор -redact-paths
+func positionsName(root, path string, redactor *pathRedactor) string {
+	if redactor != nil {
+		return path
+	}
+	rel, err := filepath.Rel(filepath.FromSlash(root), filepath.FromSlash(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Запись dir/name.positions.json, name - путь файла относительно корня анализа
+func writePositions(dir, name string, p PositionsFile) error {
+	path := filepath.Join(dir, filepath.FromSlash(name)+".positions.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	p.File = name
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func readPositions(t *testing.T, dir, name string) PositionsFile {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)+".positions.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p PositionsFile
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// Срезы исходного текста по смещениям из файла позиций
+func originalSlices(content string, p PositionsFile) []string {
+	var out []string
+	for _, tp := range p.Tokens {
+		out = append(out, content[tp.Offset:tp.Offset+tp.Length])
+	}
+	return out
+}
+
+func TestPositionsOut(t *testing.T) {
+	// "ﬁ" - лигатура, после NFKC становится "fi" и слово короче оригинала;
+	// "é" записано разложенным и после NFKC занимает меньше байтов
+	contents := map[string]string{
+		"plain.txt":        "Hello  world\n\tagain Hello",
+		"sub/ligature.txt": "a ﬁne café day",
+		"long.txt":         "short supercalifragilistic end",
+		"bom.txt":          "\xef\xbb\xbfhello world",
+	}
+	root := testutil.CreateTempDir(t, contents)
+
+	tests := []struct {
+		name  string
+		opts  Options
+		file  string
+		want  []string
+		token []string
+	}{
+		{"plain", Options{}, "plain.txt",
+			[]string{"Hello", "world", "again", "Hello"},
+			[]string{"hello", "world", "again", "hello"}},
+		{"nfkc", Options{Normalize: "nfkc"}, "sub/ligature.txt",
+			[]string{"a", "ﬁne", "café", "day"},
+			[]string{"a", "fine", "café", "day"}},
+		{"bom", Options{}, "bom.txt",
+			[]string{"hello", "world"},
+			[]string{"hello", "world"}},
+		{"truncated", Options{MaxTokenLength: 5}, "long.txt",
+			[]string{"short", "super", "end"},
+			[]string{"short", "super", "end"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.opts.Path, tt.opts.Ext, tt.opts.Workers, tt.opts.Format, tt.opts.PositionsOut = root, ".txt", 1, "json", dir
+			var out bytes.Buffer
+			if err := run(context.Background(), tt.opts, &out); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(out.String(), "token_positions") {
+				t.Error("позиции не должны попадать в отчёт")
+			}
+			p := readPositions(t, dir, tt.file)
+			if p.File != tt.file {
+				t.Errorf("file: expected %q, got %q", tt.file, p.File)
+			}
+			if got := originalSlices(contents[tt.file], p); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("slices: expected %q, got %q", tt.want, got)
+			}
+			var tokens []string
+			for _, tp := range p.Tokens {
+				tokens = append(tokens, tp.Token)
+			}
+			if strings.Join(tokens, "|") != strings.Join(tt.token, "|") {
+				t.Errorf("tokens: expected %q, got %q", tt.token, tokens)
+			}
+		})
+	}
+}
+
+func TestPositionsMaxAndRedact(t *testing.T) {
+	content := "one two three four"
+	root := testutil.CreateTempDir(t, map[string]string{"secret/notes.txt": content})
+	dir := t.TempDir()
+	opts := Options{Path: root, Ext: ".txt", Workers: 1, Format: "json", PositionsOut: dir, PositionsMax: 2, RedactPaths: true}
+	var out bytes.Buffer
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	name := newPathRedactor(root).path(filepath.Join(root, "secret", "notes.txt"))
+	p := readPositions(t, dir, name)
+	if p.File != name || !p.Truncated {
+		t.Errorf("expected redacted name %q and truncated, got %q %v", name, p.File, p.Truncated)
+	}
+	if got := originalSlices(content, p); strings.Join(got, " ") != "one two" {
+		t.Errorf("expected first two tokens, got %q", got)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Метка порядка байтов UTF-8
+const utf8BOM = "\xef\xbb\xbf"
+
+// Удаление UTF-8 BOM в начале содержимого, иначе он прилипает к первому слову
+func StripBOM(content string) string {
+	return strings.TrimPrefix(content, utf8BOM)
+}
+
+// Нормализация Unicode для -normalize: none, nfc или nfkc.
+// Возвращает nil для none, чтобы не тратить время на лишний проход
+func unicodeNormalizer(form string) (func(string) string, error) {
+	switch form {
+	case "", "none":
+		return nil, nil
+	case "nfc":
+		return norm.NFC.String, nil
+	case "nfkc":
+		return norm.NFKC.String, nil
+	}
+	return nil, fmt.Errorf("неизвестная нормализация -normalize %q, ожидается nfc, nfkc или none", form)
+}
+
+// Применение normalize к содержимому перед анализаторами
+func normalizingAnalyzer(analyze func(string, []Analyzer) []AnalysisResult, normalize func(string) string) func(string, []Analyzer) []AnalysisResult {
+	if normalize == nil {
+		return analyze
+	}
+	return func(content string, analyzers []Analyzer) []AnalysisResult {
+		return analyze(normalize(content), analyzers)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestStripBOM(t *testing.T) {
+	if got := StripBOM(utf8BOM + "hello"); got != "hello" {
+		t.Errorf("expected BOM stripped, got %q", got)
+	}
+	if got := StripBOM("hello" + utf8BOM); got != "hello"+utf8BOM {
+		t.Errorf("expected only leading BOM stripped, got %q", got)
+	}
+}
+
+func TestBOMFileMatchesPlain(t *testing.T) {
+	withBOM := testutil.CreateTempFile(t, utf8BOM+"Hello world\nhello go")
+	plain := testutil.CreateTempFile(t, "Hello world\nhello go")
+
+	analyzers := []Analyzer{WordCountAnalyzer{}, MostFrequentWordsAnalyzer{}}
+	results, err := AnalyzeSequential([]string{withBOM, plain}, analyzers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(results[0].Results, results[1].Results) {
+		t.Errorf("expected identical results, got %v and %v", results[0].Results, results[1].Results)
+	}
+	if freq := results[0].Results[1].Data.(map[string]int); freq["hello"] != 2 {
+		t.Errorf("expected hello=2 with BOM stripped, got %v", freq)
+	}
+}
+
+func TestRunNormalizeMergesForms(t *testing.T) {
+	const nfc, nfd = "caf\u00e9", "cafe\u0301"
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "mac.txt"), []byte(nfd+" au lait"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "win.txt"), []byte(nfc+" noir"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	summary := func(form string) SummaryReport {
+		t.Helper()
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", TopWords: 10, Normalize: form}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		return report.Summary
+	}
+	count := func(words []WordCount, w string) int {
+		for _, wc := range words {
+			if wc.Word == w {
+				return wc.Count
+			}
+		}
+		return 0
+	}
+
+	plain := summary("none")
+	if count(plain.TopWords, nfc) != 1 || count(plain.TopWords, nfd) != 1 || plain.Normalization != "" {
+		t.Errorf("without normalization forms should stay separate, got %+v", plain)
+	}
+	merged := summary("nfc")
+	if count(merged.TopWords, nfc) != 2 || count(merged.TopWords, nfd) != 0 || merged.Normalization != "nfc" {
+		t.Errorf("with -normalize nfc forms should merge, got %+v", merged)
+	}
+
+	if _, err := unicodeNormalizer("nfd"); err == nil {
+		t.Error("expected error for unsupported form")
+	}
+}
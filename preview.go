@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// Чтение первых n байт файла для -preview-bytes. Незаконченная на границе руна
+// отбрасывается. Остаток файла читается потоком только ради Hash: content_hash
+// в отчёте - хеш всего файла, как без -preview-bytes.
+// FIFO читается целиком через readFile и обрезается
+func readPrefix(path string, n int64) (fileContent, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileContent{}, err
+	}
+	var data []byte
+	if isFIFO(info) {
+		fc, err := readFile(path)
+		if err != nil {
+			return fileContent{}, err
+		}
+		if int64(len(fc.Text)) > n {
+			fc.Text = string(trimPartialRune([]byte(fc.Text[:n])))
+		}
+		return fc, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return fileContent{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if data, err = io.ReadAll(io.LimitReader(io.TeeReader(f, h), n)); err != nil {
+		return fileContent{}, err
+	}
+	if _, err := io.Copy(h, f); err != nil {
+		return fileContent{}, err
+	}
+	if info.Size() > n {
+		data = trimPartialRune(data)
+	}
+	text := StripBOM(string(data))
+	return fileContent{Text: text, Info: info, Hash: hex.EncodeToString(h.Sum(nil)), BOM: len(data) - len(text)}, nil
+}
+
+// Отбрасывание неполной UTF-8 последовательности в конце data
+func trimPartialRune(data []byte) []byte {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i]
+			}
+			break
+		}
+	}
+	return data
+}
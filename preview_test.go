@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTrimPartialRune(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"abc", "abc"},
+		{"ab\xd0", "ab"},     // первый байт "ж"
+		{"ab\xe2\x82", "ab"}, // два байта "€"
+		{"abж", "abж"},
+		{"ab€", "ab€"},
+	}
+	for _, tt := range tests {
+		if got := string(trimPartialRune([]byte(tt.in))); got != tt.want {
+			t.Errorf("trimPartialRune(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadPrefixRuneSafe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ru.txt")
+	if err := os.WriteFile(path, []byte("жжжж"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fc, err := readPrefix(path, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fc.Text != "жж" || fc.Info.Size() != 8 {
+		t.Errorf("expected \"жж\" from an 8 byte file, got %q (size %d)", fc.Text, fc.Info.Size())
+	}
+	whole, err := readFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fc.Hash != whole.Hash {
+		t.Errorf("expected the hash of the whole file %s, got %s", whole.Hash, fc.Hash)
+	}
+}
+
+func TestRunPreviewBytes(t *testing.T) {
+	dir := t.TempDir()
+	prefix := strings.Repeat("alpha ", 100)
+	content := prefix + strings.Repeat("omega ", 200000)
+	if err := os.WriteFile(filepath.Join(dir, "big.txt"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", PreviewBytes: int64(len(prefix))}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(report.Files))
+	}
+	f := report.Files[0]
+	if f.Size != int64(len(content)) {
+		t.Errorf("expected full file size %d, got %d", len(content), f.Size)
+	}
+	for _, r := range f.Results {
+		switch r.NameAnalyzer {
+		case "word_count":
+			if r.Data.(int) != 100 {
+				t.Errorf("expected 100 words from the prefix, got %v", r.Data)
+			}
+		case "most_frequent_words":
+			if freq := r.Data.(map[string]int); freq["omega"] != 0 || freq["alpha"] != 100 {
+				t.Errorf("expected only prefix words, got %v", freq)
+			}
+		}
+	}
+}
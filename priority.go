@@ -0,0 +1,94 @@
+package main
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// Файл с приоритетом: файлы с большим Priority обрабатываются раньше
+type PrioritizedFile struct {
+	Path     string
+	Priority int
+}
+
+type queuedFile struct {
+	PrioritizedFile
+	seq int // порядок в исходном списке, при равном приоритете раньше идёт меньший
+}
+
+// Очередь файлов для воркеров: куча по убыванию приоритета под мьютексом
+type priorityQueue struct {
+	mu    sync.Mutex
+	items []queuedFile
+}
+
+func (q *priorityQueue) Len() int { return len(q.items) }
+func (q *priorityQueue) Less(i, j int) bool {
+	if q.items[i].Priority != q.items[j].Priority {
+		return q.items[i].Priority > q.items[j].Priority
+	}
+	return q.items[i].seq < q.items[j].seq
+}
+func (q *priorityQueue) Swap(i, j int) { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *priorityQueue) Push(x any)    { q.items = append(q.items, x.(queuedFile)) }
+func (q *priorityQueue) Pop() any {
+	it := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return it
+}
+
+func newPriorityQueue(files []PrioritizedFile) *priorityQueue {
+	q := &priorityQueue{items: make([]queuedFile, len(files))}
+	for i, f := range files {
+		q.items[i] = queuedFile{f, i}
+	}
+	heap.Init(q)
+	return q
+}
+
+// Файл с наибольшим приоритетом, ok == false когда очередь пуста
+func (q *priorityQueue) next() (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		return "", false
+	}
+	return heap.Pop(q).(queuedFile).Path, true
+}
+
+// То же, что AnalyzeParallel, но свободный воркер всегда берёт файл с наибольшим
+// приоритетом. Результаты идут в порядке завершения обработки
+func AnalyzeParallelPriority(files []PrioritizedFile, analyzers []Analyzer, workers int) ([]FileAnalysisResult, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	queue := newPriorityQueue(files)
+	results := make(chan FileAnalysisResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			analyzers := cloneAnalyzers(analyzers)
+			for {
+				path, ok := queue.next()
+				if !ok {
+					return
+				}
+				if r, ok := analyzePath(path, analyzers, analyzeContent); ok {
+					results <- r
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []FileAnalysisResult
+	for r := range results {
+		out = append(out, r)
+	}
+	return out, nil
+}
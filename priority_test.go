@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"stage5/internal/testutil"
+	"testing"
+)
+
+func TestPriorityQueueOrder(t *testing.T) {
+	q := newPriorityQueue([]PrioritizedFile{{"low", 1}, {"high", 9}, {"mid-a", 5}, {"mid-b", 5}})
+	var got []string
+	for {
+		path, ok := q.next()
+		if !ok {
+			break
+		}
+		got = append(got, path)
+	}
+	want := []string{"high", "mid-a", "mid-b", "low"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestAnalyzeParallelPriorityLowLast(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt": "one two", "b.txt": "three four", "c.txt": "five six", "d.txt": "seven eight",
+	})
+	files := []PrioritizedFile{
+		{filepath.Join(dir, "a.txt"), 0},
+		{filepath.Join(dir, "b.txt"), 10},
+		{filepath.Join(dir, "c.txt"), 5},
+		{filepath.Join(dir, "d.txt"), 7},
+	}
+	results, err := AnalyzeParallelPriority(files, []Analyzer{WordCountAnalyzer{}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	for _, r := range results {
+		order = append(order, r.FileName)
+	}
+	if len(order) != 4 || order[0] != "b.txt" || order[3] != "a.txt" {
+		t.Errorf("expected b.txt first and low-priority a.txt last, got %v", order)
+	}
+
+	results, _ = AnalyzeParallelPriority(files, []Analyzer{WordCountAnalyzer{}}, 3)
+	order = order[:0]
+	for _, r := range results {
+		order = append(order, r.FileName)
+	}
+	sort.Strings(order)
+	if len(order) != 4 || order[0] != "a.txt" || order[3] != "d.txt" {
+		t.Errorf("expected all 4 files with 3 workers, got %v", order)
+	}
+}
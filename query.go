@@ -0,0 +1,174 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Файл, подходящий под запрос, и его оценка - сумма вхождений искомых слов
+type QueryMatch struct {
+	File  string
+	Score int
+}
+
+// Разбор и выполнение запроса вида "x AND y OR NOT z".
+// Приоритет: NOT, затем AND (в том числе неявный между словами), затем OR.
+func (idx *InvertedIndex) Query(expr string) ([]QueryMatch, error) {
+	p := &queryParser{tokens: tokenizeQuery(expr), idx: idx}
+	if len(p.tokens) == 0 {
+		return nil, errors.New("пустой запрос")
+	}
+	scores, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("неожиданный токен %q", p.tokens[p.pos])
+	}
+
+	matches := make([]QueryMatch, 0, len(scores))
+	for id, score := range scores {
+		matches = append(matches, QueryMatch{idx.Files[id], score})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].File < matches[j].File
+	})
+	return matches, nil
+}
+
+func tokenizeQuery(expr string) []string {
+	expr = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)
+	return strings.Fields(expr)
+}
+
+// Множество файлов с оценками: номер файла -> оценка
+type fileScores map[int]int
+
+type queryParser struct {
+	tokens []string
+	pos    int
+	idx    *InvertedIndex
+}
+
+func (p *queryParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *queryParser) parseOr() (fileScores, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "OR" {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		for id, s := range right {
+			left[id] += s
+		}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (fileScores, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok == "AND" {
+			p.pos++
+		} else if tok == "" || tok == "OR" || tok == ")" {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		for id := range left {
+			if s, ok := right[id]; ok {
+				left[id] += s
+			} else {
+				delete(left, id)
+			}
+		}
+	}
+}
+
+func (p *queryParser) parseNot() (fileScores, error) {
+	if p.peek() != "NOT" {
+		return p.parseTerm()
+	}
+	p.pos++
+	inner, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	out := make(fileScores)
+	for id, f := range p.idx.Files {
+		if _, ok := inner[id]; !ok && f != "" {
+			out[id] = 0
+		}
+	}
+	return out, nil
+}
+
+func (p *queryParser) parseTerm() (fileScores, error) {
+	tok := p.peek()
+	switch tok {
+	case "":
+		return nil, errors.New("неожиданный конец запроса")
+	case "AND", "OR", ")":
+		return nil, fmt.Errorf("неожиданный токен %q", tok)
+	case "(":
+		p.pos++
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, errors.New("не закрыта скобка")
+		}
+		p.pos++
+		return inner, nil
+	}
+	p.pos++
+	out := make(fileScores)
+	for _, posting := range p.idx.Postings[strings.ToLower(tok)] {
+		if p.idx.Files[posting.FileID] != "" {
+			out[posting.FileID] = posting.Count
+		}
+	}
+	return out, nil
+}
+
+// Подкоманда query <индекс> "запрос"
+func runQuery(args []string, out io.Writer) error {
+	if len(args) != 2 {
+		return errors.New(`использование: query <индекс> "x AND y OR z"`)
+	}
+	idx, err := readIndexFile(args[0])
+	if err != nil {
+		return fmt.Errorf("ошибка чтения индекса %w", err)
+	}
+	matches, err := idx.Query(args[1])
+	if err != nil {
+		return fmt.Errorf("ошибка запроса %w", err)
+	}
+	for _, m := range matches {
+		fmt.Fprintf(out, "%s: %d\n", m.File, m.Score)
+	}
+	return nil
+}
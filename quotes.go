@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Статистика цитат в файле
+type QuoteStats struct {
+	Count       int      `json:"count"`
+	TotalLength int      `json:"total_length"`
+	Longest     string   `json:"longest"`
+	Unbalanced  int      `json:"unbalanced"`
+	Quotes      []string `json:"quotes,omitempty"`
+	// строки, где начинаются незакрытые цитаты, заполняется при Examples > 0
+	Examples []LineExample `json:"examples,omitempty"`
+}
+
+// Открывающие кавычки и соответствующие им закрывающие.
+// Одинарные кавычки не учитываются: ’ слишком часто используется как апостроф.
+var quotePairs = map[rune]rune{
+	'"': '"',
+	'“': '”',
+	'«': '»',
+	'„': '“',
+}
+
+// Анализатор цитат в прямых, типографских кавычках и «ёлочках».
+// Цитаты не короче MinListLength символов перечисляются в результате (0 - не перечислять),
+// для первых Examples незакрытых цитат указывается строка, где они начинаются.
+type QuoteAnalyzer struct {
+	MinListLength int
+	Examples      int
+}
+
+func (q QuoteAnalyzer) Name() string {
+	return "quotes"
+}
+func (q QuoteAnalyzer) Analyze(content string) AnalysisResult {
+	var stats QuoteStats
+	longest := 0
+
+	emit := func(quote string) {
+		n := utf8.RuneCountInString(quote)
+		stats.Count++
+		stats.TotalLength += n
+		if n > longest {
+			longest = n
+			stats.Longest = quote
+		}
+		if q.MinListLength > 0 && n >= q.MinListLength {
+			stats.Quotes = append(stats.Quotes, quote)
+		}
+	}
+
+	unbalanced := func(start int) {
+		stats.Unbalanced++
+		if len(stats.Examples) < q.Examples {
+			open := strings.LastIndexByte(content[:start], '\n') + 1
+			text, _, _ := strings.Cut(content[open:], "\n")
+			stats.Examples = appendExample(stats.Examples, q.Examples, strings.Count(content[:start], "\n")+1, text)
+		}
+	}
+
+	// Автомат: стек ожидаемых закрывающих кавычек, учитывается только внешняя цитата
+	var closers []rune
+	start := 0
+	for i, r := range content {
+		if len(closers) > 0 && r == '\n' && paragraphEnds(content[i+1:]) {
+			// незакрытая цитата обрывается в конце абзаца
+			emit(content[start:i])
+			unbalanced(start)
+			closers = closers[:0]
+			continue
+		}
+
+		if depth := closerDepth(closers, r); depth >= 0 {
+			if depth < len(closers)-1 {
+				// закрылась внешняя цитата, вложенные остались незакрытыми
+				unbalanced(start)
+			}
+			closers = closers[:depth]
+			if len(closers) == 0 {
+				emit(content[start:i])
+			}
+			continue
+		}
+
+		if closer, ok := quotePairs[r]; ok {
+			if len(closers) == 0 {
+				start = i + utf8.RuneLen(r)
+			}
+			closers = append(closers, closer)
+		}
+	}
+	if len(closers) > 0 {
+		emit(content[start:])
+		unbalanced(start)
+	}
+
+	return AnalysisResult{
+		NameAnalyzer: q.Name(),
+		Data:         stats,
+	}
+}
+
+// Индекс самой вложенной открытой цитаты, которую закрывает r, или -1
+func closerDepth(closers []rune, r rune) int {
+	for i := len(closers) - 1; i >= 0; i-- {
+		if closers[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Начинается ли rest (текст после перевода строки) с пустой строки
+func paragraphEnds(rest string) bool {
+	rest = strings.TrimLeft(rest, " \t\r")
+	return rest == "" || rest[0] == '\n'
+}
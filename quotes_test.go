@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuoteAnalyzer(t *testing.T) {
+	content := `He said "hello there" and “goodbye”.`
+	q := QuoteAnalyzer{}.Analyze(content).Data.(QuoteStats)
+
+	if q.Count != 2 {
+		t.Errorf("expected 2 quotes, got %d", q.Count)
+	}
+	if q.TotalLength != len("hello there")+len("goodbye") {
+		t.Errorf("unexpected total length %d", q.TotalLength)
+	}
+	if q.Longest != "hello there" {
+		t.Errorf("expected longest %q, got %q", "hello there", q.Longest)
+	}
+	if q.Quotes != nil {
+		t.Errorf("expected no quote list without MinListLength")
+	}
+}
+
+func TestQuoteAnalyzerNested(t *testing.T) {
+	content := `Он ответил: «Мне сказали „всё готово“ утром».`
+	q := QuoteAnalyzer{MinListLength: 1}.Analyze(content).Data.(QuoteStats)
+
+	if q.Count != 1 || q.Unbalanced != 0 {
+		t.Fatalf("expected 1 balanced quote, got %+v", q)
+	}
+	if q.Longest != "Мне сказали „всё готово“ утром" {
+		t.Errorf("unexpected quote %q", q.Longest)
+	}
+	if q.TotalLength != 30 {
+		t.Errorf("expected length 30 in runes, got %d", q.TotalLength)
+	}
+}
+
+func TestQuoteAnalyzerUnbalanced(t *testing.T) {
+	content := "She said \"this never ends\nstill the same paragraph\n\n" +
+		strings.Repeat("next paragraph text ", 50) + "\n\nand «a proper one»."
+	q := QuoteAnalyzer{}.Analyze(content).Data.(QuoteStats)
+
+	if q.Count != 2 || q.Unbalanced != 1 {
+		t.Fatalf("expected 2 quotes with 1 unbalanced, got %+v", q)
+	}
+	if q.Longest != "this never ends\nstill the same paragraph" {
+		t.Errorf("expected unbalanced quote capped at paragraph end, got %q", q.Longest)
+	}
+}
+
+func TestQuoteAnalyzerListMinLength(t *testing.T) {
+	content := `"a" and "longer quote" and «ok»`
+	q := QuoteAnalyzer{MinListLength: 5}.Analyze(content).Data.(QuoteStats)
+
+	if len(q.Quotes) != 1 || q.Quotes[0] != "longer quote" {
+		t.Errorf("expected only the long quote listed, got %v", q.Quotes)
+	}
+}
+
+func TestQuoteAnalyzerExamplesCapped(t *testing.T) {
+	content := "ok \"closed\"\nfirst \"open\n\nsecond «open\n\nthird \"open"
+	q := QuoteAnalyzer{Examples: 2}.Analyze(content).Data.(QuoteStats)
+	if q.Unbalanced != 3 || len(q.Examples) != 2 {
+		t.Fatalf("expected 3 unbalanced with 2 examples, got %+v", q)
+	}
+	if q.Examples[0] != (LineExample{2, `first "open`}) || q.Examples[1] != (LineExample{4, "second «open"}) {
+		t.Errorf("unexpected examples %v", q.Examples)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Гласные для подсчёта слогов: латиница с y и кириллица
+const syllableVowels = "aeiouyаеёиоуыэюя"
+
+// Количество слогов в слове: группы подряд идущих гласных.
+// Для английского немая e в конце не считается (make, но не table)
+func countSyllables(word string) int {
+	runes := []rune(strings.ToLower(word))
+	count := 0
+	prevVowel := false
+	for _, r := range runes {
+		vowel := strings.ContainsRune(syllableVowels, r)
+		if vowel && !prevVowel {
+			count++
+		}
+		prevVowel = vowel
+	}
+	n := len(runes)
+	if count > 1 && runes[n-1] == 'e' && runes[n-2] != 'l' && !strings.ContainsRune(syllableVowels, runes[n-2]) {
+		count--
+	}
+	return max(count, 1)
+}
+
+// Счётчики, общие для формул читаемости
+type readabilityCounts struct {
+	Words, Sentences, Syllables int
+}
+
+func countReadability(content string) readabilityCounts {
+	var c readabilityCounts
+	for _, sentence := range splitSentences(content) {
+		words := 0
+		for _, token := range strings.Fields(sentence) {
+			word := strings.TrimFunc(token, func(r rune) bool { return !unicode.IsLetter(r) })
+			if word == "" {
+				continue
+			}
+			words++
+			c.Syllables += countSyllables(word)
+		}
+		if words > 0 {
+			c.Words += words
+			c.Sentences++
+		}
+	}
+	return c
+}
+
+// Анализатор индекса удобочитаемости Флеша (0-100, чем выше, тем проще текст).
+// Формула рассчитана на английский текст
+type ReadabilityAnalyzer struct{}
+
+func (r ReadabilityAnalyzer) Name() string {
+	return "readability"
+}
+func (r ReadabilityAnalyzer) Analyze(content string) AnalysisResult {
+	c := countReadability(content)
+	score := 0.0
+	if c.Words > 0 {
+		score = 206.835 - 1.015*float64(c.Words)/float64(c.Sentences) - 84.6*float64(c.Syllables)/float64(c.Words)
+	}
+	return AnalysisResult{
+		NameAnalyzer: r.Name(),
+		Data:         score,
+	}
+}
+
+// Анализатор уровня Флеша-Кинкейда: школьный класс США,
+// 0.39*(слова/предложения) + 11.8*(слоги/слова) - 15.59
+type FKGradeAnalyzer struct{}
+
+func (f FKGradeAnalyzer) Name() string {
+	return "fk_grade"
+}
+func (f FKGradeAnalyzer) Analyze(content string) AnalysisResult {
+	c := countReadability(content)
+	grade := 0.0
+	if c.Words > 0 {
+		grade = 0.39*float64(c.Words)/float64(c.Sentences) + 11.8*float64(c.Syllables)/float64(c.Words) - 15.59
+	}
+	return AnalysisResult{
+		NameAnalyzer: f.Name(),
+		Data:         grade,
+	}
+}
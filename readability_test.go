@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountSyllables(t *testing.T) {
+	cases := map[string]int{
+		"cat":         1,
+		"make":        1,
+		"table":       2,
+		"water":       2,
+		"agreement":   3,
+		"be":          1,
+		"the":         1,
+		"readability": 5,
+		"молоко":      3,
+	}
+	for word, want := range cases {
+		if got := countSyllables(word); got != want {
+			t.Errorf("%s: expected %d syllables, got %d", word, want, got)
+		}
+	}
+}
+
+func TestFKGradeAnalyzer(t *testing.T) {
+	// 6 слов, 1 предложение, 6 слогов: 0.39*6 + 11.8*1 - 15.59
+	got := FKGradeAnalyzer{}.Analyze("The cat sat on the mat.").Data.(float64)
+	if math.Abs(got-(-1.45)) > 1e-9 {
+		t.Errorf("expected -1.45, got %v", got)
+	}
+
+	// начало "Старика и моря" Хемингуэя, уровень около 4 класса
+	hemingway := "He was an old man who fished alone in a skiff in the Gulf Stream and he had gone " +
+		"eighty-four days now without taking a fish. In the first forty days a boy had been with him. " +
+		"But after forty days without a fish the boy's parents had told him that the old man was now " +
+		"definitely and finally salao, which is the worst form of unlucky."
+	// юридический текст, уровень выпускника вуза
+	legal := "Notwithstanding any provision of this Agreement to the contrary, the indemnifying party " +
+		"shall, to the maximum extent permissible under applicable legislation, indemnify, defend and " +
+		"hold harmless the indemnified party from and against any and all liabilities, obligations, " +
+		"deficiencies, judgments, settlements and expenditures arising out of or relating to any " +
+		"misrepresentation or nonfulfillment of any covenant hereunder."
+
+	simple := FKGradeAnalyzer{}.Analyze(hemingway).Data.(float64)
+	hard := FKGradeAnalyzer{}.Analyze(legal).Data.(float64)
+	if simple < 2 || simple > 9 {
+		t.Errorf("expected Hemingway prose around grade 4-8, got %.2f", simple)
+	}
+	if hard < 16 {
+		t.Errorf("expected legal text above grade 16, got %.2f", hard)
+	}
+
+	if g := (FKGradeAnalyzer{}).Analyze("").Data.(float64); g != 0 {
+		t.Errorf("expected 0 for empty text, got %v", g)
+	}
+}
+
+func TestReadabilityAnalyzer(t *testing.T) {
+	easy := ReadabilityAnalyzer{}.Analyze("The cat sat on the mat.").Data.(float64)
+	// 206.835 - 1.015*6 - 84.6*1
+	if math.Abs(easy-116.145) > 1e-9 {
+		t.Errorf("expected 116.145, got %v", easy)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Префикс скрытых имён -redact-paths
+const redactedPrefix = "file-"
+
+// Замена путей в отчёте на непрозрачные идентификаторы для -redact-paths.
+// Идентификатор - хеш пути относительно корня анализа с сохранённым расширением,
+// поэтому один файл получает одно имя во всём отчёте и в повторных запусках.
+// Хеш не секрет: короткий предсказуемый путь можно подобрать перебором.
+// Методы nil-редактора возвращают пути без изменений
+type pathRedactor struct {
+	root string
+	mu   sync.Mutex
+	ids  map[string]string // идентификатор -> исходный путь
+}
+
+func newPathRedactor(root string) *pathRedactor {
+	return &pathRedactor{root: filepath.FromSlash(root), ids: make(map[string]string)}
+}
+
+func (r *pathRedactor) path(p string) string {
+	if r == nil || p == "" {
+		return p
+	}
+	rel, err := filepath.Rel(r.root, filepath.FromSlash(p))
+	if err != nil {
+		rel = p
+	}
+	sum := sha256.Sum256([]byte(filepath.ToSlash(rel)))
+	id := redactedPrefix + hex.EncodeToString(sum[:6]) + filepath.Ext(p)
+	r.mu.Lock()
+	r.ids[id] = NormalizePath(p)
+	r.mu.Unlock()
+	return id
+}
+
+func (r *pathRedactor) paths(ps []string) []string {
+	if r == nil {
+		return ps
+	}
+	out := make([]string, len(ps))
+	for i, p := range ps {
+		out[i] = r.path(p)
+	}
+	return out
+}
+
+// Ошибка с путём, заменённым на идентификатор, для вывода и FileError
+func (r *pathRedactor) error(p string, err error) error {
+	if r == nil {
+		return err
+	}
+	msg := strings.ReplaceAll(err.Error(), p, r.path(p))
+	return errors.New(strings.ReplaceAll(msg, filepath.FromSlash(p), r.path(p)))
+}
+
+// Результат файла со скрытыми FileName и FilePath
+func (r *pathRedactor) result(res FileAnalysisResult) FileAnalysisResult {
+	if r == nil {
+		return res
+	}
+	id := r.path(res.FilePath)
+	res.FileName, res.FilePath = id, id
+	return res
+}
+
+// Запись соответствия идентификатор -> путь. Файл только для владельца:
+// он раскрывает структуру каталогов и не должен уходить вместе с отчётом
+func (r *pathRedactor) writeMap(path string) error {
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.ids, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Чтение файла соответствий -redact-map
+func loadRedactionMap(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ids map[string]string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestRunRedactPaths(t *testing.T) {
+	shared := "The quick brown fox jumps over the lazy dog while the farmer watches from the porch."
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"alice/secretalpha.txt": "SPDX-License-Identifier: MIT\n" + shared + " Alpha words here.",
+		"alice/secretbeta.txt":  shared + "\n\nBeta text with more words.",
+		"bob/secretcopy.txt":    shared + "\n\nBeta text with more words.",
+		"bob/secretempty.txt":   "",
+	})
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "secretgone.txt")); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	mapFile := filepath.Join(t.TempDir(), "map.json")
+
+	var out bytes.Buffer
+	opts := Options{
+		Path: dir, Ext: ".txt", Workers: 2, Format: "json", RedactPaths: true, RedactMap: mapFile,
+		License: true, DupSentences: true, SimilarParagraphs: 0.9, TopWords: 2, Examples: 1,
+		Activity: true, ReportEmpty: true, Dedup: true, MinSeverity: "info", Seed: 1,
+	}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{dir, "alice", "bob", "secret"} {
+		if strings.Contains(out.String(), leak) {
+			t.Errorf("redacted output contains %q:\n%s", leak, out.String())
+		}
+	}
+
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := loadRedactionMap(mapFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := append(report.Summary.EmptyFiles, report.Summary.VanishedFiles...)
+	names = append(names, report.Summary.DuplicateFiles...)
+	names = append(names, report.Summary.FailedFiles...)
+	for _, f := range report.Files {
+		names = append(names, f.FileName)
+	}
+	if len(names) != 5 {
+		t.Fatalf("expected 5 files across the report, got %v", names)
+	}
+	for _, id := range names {
+		orig, ok := ids[id]
+		if !strings.HasPrefix(id, redactedPrefix) || !strings.HasSuffix(id, ".txt") || !ok {
+			t.Errorf("id %q is not redacted or missing from the map", id)
+			continue
+		}
+		if !strings.HasPrefix(orig, NormalizePath(dir)) || newPathRedactor(dir).path(orig) != id {
+			t.Errorf("map entry %q -> %q does not round-trip", id, orig)
+		}
+	}
+	if info, err := os.Stat(mapFile); err == nil && info.Mode().Perm() != 0o600 {
+		t.Errorf("expected map file mode 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestPathRedactorStable(t *testing.T) {
+	r := newPathRedactor("/data")
+	a, b := r.path("/data/x/report.md"), r.path("/data/y/report.md")
+	if a == b || a != newPathRedactor("/data").path("/data/x/report.md") || filepath.Ext(a) != ".md" {
+		t.Errorf("expected stable distinct ids with extension, got %q and %q", a, b)
+	}
+	var nilRedactor *pathRedactor
+	if got := nilRedactor.path("/data/x"); got != "/data/x" {
+		t.Errorf("expected nil redactor to keep the path, got %q", got)
+	}
+}
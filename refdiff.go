@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Пословное отличие файла от эталона: добавлено, удалено и общих слов
+type DiffStats struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Common  int `json:"common"`
+	// общие слова посчитаны без учёта порядка: файл и эталон слишком велики для LCS
+	Approximate bool `json:"approximate,omitempty"`
+}
+
+// Предел размера таблицы LCS (произведение длин без общих начала и конца).
+// Сверх него общие слова считаются без учёта порядка, за линейное время
+const maxDiffCells = 1 << 26
+
+// Анализатор отличий от эталонного текста для -diff-from-ref.
+// Эталон читается один раз в NewDiffFromReferenceAnalyzer
+type DiffFromReferenceAnalyzer struct {
+	ReferencePath string
+	reference     []string
+}
+
+func NewDiffFromReferenceAnalyzer(path string) (DiffFromReferenceAnalyzer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return DiffFromReferenceAnalyzer{}, fmt.Errorf("ошибка чтения эталона %w", err)
+	}
+	return DiffFromReferenceAnalyzer{ReferencePath: path, reference: strings.Fields(StripBOM(string(data)))}, nil
+}
+
+// Анализатор diff_from_ref с эталоном из -diff-from-ref: заменяет выбранный
+// через -analyzers или добавляется. Без эталона выбирать его нельзя
+func withReference(analyzers []Analyzer, path string) ([]Analyzer, error) {
+	i := slices.IndexFunc(analyzers, func(a Analyzer) bool { return a.Name() == "diff_from_ref" })
+	if path == "" {
+		if i >= 0 {
+			return nil, errors.New("анализатор diff_from_ref требует -diff-from-ref")
+		}
+		return analyzers, nil
+	}
+	ref, err := NewDiffFromReferenceAnalyzer(path)
+	if err != nil {
+		return nil, err
+	}
+	if i >= 0 {
+		analyzers[i] = ref
+		return analyzers, nil
+	}
+	return append(analyzers, ref), nil
+}
+
+func (d DiffFromReferenceAnalyzer) Name() string {
+	return "diff_from_ref"
+}
+func (d DiffFromReferenceAnalyzer) Analyze(content string) AnalysisResult {
+	words := strings.Fields(content)
+	common, exact := commonWords(d.reference, words)
+	return AnalysisResult{
+		NameAnalyzer: d.Name(),
+		Data: DiffStats{
+			Added:       len(words) - common,
+			Removed:     len(d.reference) - common,
+			Common:      common,
+			Approximate: !exact,
+		},
+	}
+}
+
+// Число общих слов a и b: длина LCS, если таблица после отбрасывания общих
+// начала и конца не больше maxDiffCells, иначе пересечение мультимножеств слов
+// (верхняя оценка LCS), exact == false
+func commonWords(a, b []string) (common int, exact bool) {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+		common++
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+		common++
+	}
+	if len(a) > 0 && len(b) > maxDiffCells/len(a) {
+		return common + bagIntersection(a, b), false
+	}
+	return common + lcsLength(a, b), true
+}
+
+// Размер пересечения a и b как мультимножеств
+func bagIntersection(a, b []string) int {
+	counts := make(map[string]int, len(a))
+	for _, w := range a {
+		counts[w]++
+	}
+	n := 0
+	for _, w := range b {
+		if counts[w] > 0 {
+			counts[w]--
+			n++
+		}
+	}
+	return n
+}
+
+// Длина наибольшей общей подпоследовательности слов.
+// Время O(len(a)*len(b)), память - две строки по меньшей из длин
+func lcsLength(a, b []string) int {
+	if len(b) > len(a) {
+		a, b = b, a
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestLCSLength(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"a b c d", "a b c d", 4},
+		{"a b c d", "b d", 2},
+		{"the cat sat on the mat", "the dog sat on a mat", 4},
+		{"", "x y", 0},
+	}
+	for _, tt := range tests {
+		if got := lcsLength(strings.Fields(tt.a), strings.Fields(tt.b)); got != tt.want {
+			t.Errorf("lcsLength(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCommonWordsCapped(t *testing.T) {
+	if common, exact := commonWords(strings.Fields("x a b c y"), strings.Fields("x c b a y")); common != 3 || !exact {
+		t.Errorf("expected exact LCS 3, got %d (exact %v)", common, exact)
+	}
+
+	// таблица сверх maxDiffCells не строится, общие слова считаются без порядка
+	n := 1 << 14
+	a, b := make([]string, n), make([]string, n)
+	for i := range a {
+		a[i], b[i] = fmt.Sprint(i), fmt.Sprint(n-1-i)
+	}
+	common, exact := commonWords(a, b)
+	if exact || common != n {
+		t.Errorf("expected approximate count %d, got %d (exact %v)", n, common, exact)
+	}
+}
+
+func TestDiffFromReferenceAnalyzer(t *testing.T) {
+	ref := testutil.CreateTempFile(t, "the cat sat on the mat")
+	a, err := NewDiffFromReferenceAnalyzer(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// общие: the sat on mat; добавлены dog a big; удалены cat the
+	got := a.Analyze("the dog sat on a big mat").Data.(DiffStats)
+	if want := (DiffStats{Added: 3, Removed: 2, Common: 4}); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := NewDiffFromReferenceAnalyzer(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected error for missing reference")
+	}
+}
+
+func TestRunDiffFromRef(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one two three four"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ref := filepath.Join(t.TempDir(), "ref.txt")
+	if err := os.WriteFile(ref, []byte("one three five"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, DiffFromRef: ref}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), " diff from reference: +2 -1 (common 2)") {
+		t.Errorf("expected diff line, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Реестр анализаторов по имени для сборки конвейера во время выполнения
+type AnalyzerRegistry struct {
+	mu        sync.RWMutex
+	analyzers map[string]Analyzer
+}
+
+func NewAnalyzerRegistry() *AnalyzerRegistry {
+	return &AnalyzerRegistry{analyzers: make(map[string]Analyzer)}
+}
+
+// Регистрация анализатора под его Name(), повторная регистрация заменяет прежний
+func (r *AnalyzerRegistry) Register(a Analyzer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.analyzers[a.Name()] = a
+}
+
+func (r *AnalyzerRegistry) Get(name string) (Analyzer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	a, ok := r.analyzers[name]
+	return a, ok
+}
+
+// Отсортированный список зарегистрированных имён
+func (r *AnalyzerRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.analyzers))
+	for name := range r.analyzers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Анализаторы по списку имён в заданном порядке
+func (r *AnalyzerRegistry) Build(names []string) ([]Analyzer, error) {
+	analyzers := make([]Analyzer, 0, len(names))
+	for _, name := range names {
+		a, ok := r.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("неизвестный анализатор %q, доступны: %s", name, strings.Join(r.Names(), ", "))
+		}
+		analyzers = append(analyzers, a)
+	}
+	return analyzers, nil
+}
+
+// Реестр встроенных анализаторов, анализаторы с build-тегами добавляются в своих init()
+var registry = NewAnalyzerRegistry()
+
+func init() {
+	for _, a := range []Analyzer{
+		WordCountAnalyzer{},
+		LineCountAnalyzer{},
+		MostFrequentWordsAnalyzer{},
+		DensityAnalyzer{},
+		TermExtractorAnalyzer{},
+		QuoteAnalyzer{},
+		LanguageDetectorAnalyzer{},
+		TypeAnalyzer{},
+		ShebangAnalyzer{},
+		LineEndingAnalyzer{},
+		IndentationAnalyzer{},
+		FinalNewlineAnalyzer{},
+		CharCountAnalyzer{},
+		MarkupAnalyzer{},
+		MarkdownBlockAnalyzer{},
+		LicenseHeaderAnalyzer{},
+		SentenceAnalyzer{},
+		SentenceDiversityAnalyzer{},
+		TokenIndexAnalyzer{},
+		ParagraphHashAnalyzer{},
+		MinHashAnalyzer{},
+		ParagraphLengthAnalyzer{},
+		SummaryAnalyzer{},
+		GeoMentionAnalyzer{},
+		FilteredFreqAnalyzer{},
+		ReadabilityAnalyzer{},
+		FKGradeAnalyzer{},
+		ExamplesAnalyzer{},
+		DiffFromReferenceAnalyzer{},
+	} {
+		registry.Register(a)
+	}
+}
+
+// Набор анализаторов для запуска: по умолчанию или из -analyzers.
+// Анализаторы из набора по умолчанию берутся с настройками из opts
+func selectAnalyzers(opts Options) ([]Analyzer, error) {
+	defaults := defaultAnalyzers(opts)
+	if opts.Analyzers == "" {
+		return defaults, nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(opts.Analyzers, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	analyzers, err := registry.Build(names)
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range analyzers {
+		for _, d := range defaults {
+			if d.Name() == a.Name() {
+				analyzers[i] = d
+			}
+		}
+	}
+	return analyzers, nil
+}
+
+// Фильтр -analyze: только анализаторы с перечисленными именами, порядок прежний.
+// Неизвестные имена дают предупреждение, а не ошибку, чтобы старые скрипты
+// не ломались при переименовании или удалении анализатора
+func filterAnalyzers(analyzers []Analyzer, list string) []Analyzer {
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+	var kept []Analyzer
+	found := make(map[string]bool)
+	for _, a := range analyzers {
+		if wanted[a.Name()] {
+			kept = append(kept, a)
+			found[a.Name()] = true
+		}
+	}
+	for _, name := range sortedKeys(wanted) {
+		if !found[name] {
+			slog.Warn("анализатор из -analyze не найден", "name", name)
+		}
+	}
+	return kept
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAnalyzerRegistry(t *testing.T) {
+	r := NewAnalyzerRegistry()
+	r.Register(WordCountAnalyzer{})
+	r.Register(mockAnalyzer{name: "mock", data: 1})
+
+	a, ok := r.Get("mock")
+	if !ok || a.Name() != "mock" {
+		t.Fatalf("expected mock analyzer, got %v, %v", a, ok)
+	}
+	if _, ok := r.Get("missing"); ok {
+		t.Error("expected missing analyzer not to be found")
+	}
+	if names := r.Names(); strings.Join(names, ",") != "mock,word_count" {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+func TestAnalyzerRegistryBuildUnknown(t *testing.T) {
+	_, err := registry.Build([]string{"word_count", "no_such"})
+	if err == nil {
+		t.Fatal("expected error for unknown analyzer")
+	}
+	if !strings.Contains(err.Error(), "no_such") || !strings.Contains(err.Error(), "line_count") {
+		t.Errorf("error should name the unknown analyzer and list known ones: %v", err)
+	}
+}
+
+func TestSelectAnalyzers(t *testing.T) {
+	analyzers, err := selectAnalyzers(Options{Analyzers: "line_count, quotes", ListQuotes: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(analyzers) != 2 || analyzers[0].Name() != "line_count" {
+		t.Fatalf("unexpected analyzers %v", analyzers)
+	}
+	if q, ok := analyzers[1].(QuoteAnalyzer); !ok || q.MinListLength != 3 {
+		t.Errorf("expected quotes analyzer configured from options, got %#v", analyzers[1])
+	}
+
+	analyzers, err = selectAnalyzers(Options{Analyzers: "license"})
+	if err != nil || len(analyzers) != 1 || analyzers[0].Name() != "license" {
+		t.Errorf("expected registered license analyzer, got %v, %v", analyzers, err)
+	}
+}
+
+// Анализатор, запоминающий, сколько раз его вызвали
+type RecordingAnalyzer struct {
+	name  string
+	calls *atomic.Int32
+}
+
+func (r RecordingAnalyzer) Name() string {
+	return r.name
+}
+func (r RecordingAnalyzer) Analyze(content string) AnalysisResult {
+	r.calls.Add(1)
+	return AnalysisResult{NameAnalyzer: r.name, Data: len(content)}
+}
+
+func TestFilterAnalyzers(t *testing.T) {
+	var a, b atomic.Int32
+	analyzers := []Analyzer{RecordingAnalyzer{"rec_a", &a}, WordCountAnalyzer{}, RecordingAnalyzer{"rec_b", &b}}
+	got := filterAnalyzers(analyzers, "word_count, rec_b, not_there")
+	if len(got) != 2 || got[0].Name() != "word_count" || got[1].Name() != "rec_b" {
+		t.Errorf("unexpected filtered analyzers %v", got)
+	}
+}
+
+func TestRunAnalyzeSubset(t *testing.T) {
+	var selected, skipped atomic.Int32
+	saved := extraAnalyzers
+	extraAnalyzers = append(extraAnalyzers,
+		func(Options) Analyzer { return RecordingAnalyzer{"rec_selected", &selected} },
+		func(Options) Analyzer { return RecordingAnalyzer{"rec_skipped", &skipped} },
+	)
+	defer func() { extraAnalyzers = saved }()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("hello from "+name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var logs bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Analyze: "word_count,rec_selected,unknown"}
+	if err := run(context.Background(), opts, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logs.String(), "name=unknown") {
+		t.Errorf("expected warning about unknown analyzer, got %q", logs.String())
+	}
+	if selected.Load() != 2 {
+		t.Errorf("expected selected analyzer to run on 2 files, got %d", selected.Load())
+	}
+	if skipped.Load() != 0 {
+		t.Errorf("unspecified analyzer should not be called, got %d calls", skipped.Load())
+	}
+}
+
+// Всё, что может попасть в конвейер, должно находиться и через -analyzers
+func TestRegistryCoversAllAnalyzers(t *testing.T) {
+	opts := Options{
+		License: true, DupSentences: true, TopTags: 1, TokenIndex: true, SimilarParagraphs: 0.9,
+		NearDupes: 0.9, Summary: true, Geo: true, Examples: 1, Density: true, Language: true, ContentType: true,
+	}
+	analyzers := withFindingAnalyzers(defaultAnalyzers(opts), opts)
+	analyzers = append(analyzers, DiffFromReferenceAnalyzer{})
+	for _, a := range analyzers {
+		if _, ok := registry.Get(a.Name()); !ok {
+			t.Errorf("analyzer %q is not registered", a.Name())
+		}
+	}
+	for _, f := range outputFields {
+		if _, ok := registry.Get(f.Analyzer); f.Analyzer != "" && !ok {
+			t.Errorf("field %q uses unregistered analyzer %q", f.Name, f.Analyzer)
+		}
+	}
+}
+
+func TestSelectDiffFromRefNeedsReference(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": "one two three", "ref.txt": "one three"})
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Analyzers: "diff_from_ref"}
+	if err := run(context.Background(), opts, io.Discard); err == nil {
+		t.Error("expected error for diff_from_ref without -diff-from-ref")
+	}
+	opts.DiffFromRef = filepath.Join(dir, "ref.txt")
+	var out bytes.Buffer
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range report.Files {
+		if len(f.Results) != 1 || f.Results[0].NameAnalyzer != "diff_from_ref" {
+			t.Errorf("expected a single diff_from_ref result for %s, got %+v", f.FileName, f.Results)
+		}
+	}
+}
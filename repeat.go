@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Время каждого из повторных запусков
+type RepeatTiming struct {
+	Samples []time.Duration
+}
+
+func (r RepeatTiming) Min() time.Duration {
+	m := r.Samples[0]
+	for _, d := range r.Samples[1:] {
+		m = min(m, d)
+	}
+	return m
+}
+
+func (r RepeatTiming) Max() time.Duration {
+	m := r.Samples[0]
+	for _, d := range r.Samples[1:] {
+		m = max(m, d)
+	}
+	return m
+}
+
+func (r RepeatTiming) Avg() time.Duration {
+	var sum time.Duration
+	for _, d := range r.Samples {
+		sum += d
+	}
+	return sum / time.Duration(len(r.Samples))
+}
+
+// Запуск анализа n раз подряд для замера времени.
+// Результаты печатаются только для последнего запуска, сводка времени - в report
+func runRepeated(ctx context.Context, opts Options, n int, out, report io.Writer) (RepeatTiming, error) {
+	var timing RepeatTiming
+	if n < 1 {
+		return timing, fmt.Errorf("-repeat должен быть не меньше 1, получено %d", n)
+	}
+
+	var err error
+	for i := 0; i < n; i++ {
+		w := io.Discard
+		if i == n-1 {
+			w = out
+		}
+		start := time.Now()
+		err = run(ctx, opts, w)
+		timing.Samples = append(timing.Samples, time.Since(start))
+
+		// сработавшее условие -fail-if не мешает замерам, ошибка вернётся после последнего запуска
+		var failErr *FailConditionError
+		if err != nil && !errors.As(err, &failErr) {
+			return timing, err
+		}
+		if ctx.Err() != nil {
+			return timing, ctx.Err()
+		}
+	}
+
+	fmt.Fprintf(report, "Запусков: %d, время min=%v avg=%v max=%v\n", len(timing.Samples), timing.Min(), timing.Avg(), timing.Max())
+	for i, d := range timing.Samples {
+		fmt.Fprintf(report, " #%d: %v\n", i+1, d)
+	}
+	return timing, err
+}
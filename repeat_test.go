@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunRepeated(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world\nhello go"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, report bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1}
+	timing, err := runRepeated(context.Background(), opts, 3, &out, &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timing.Samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d", len(timing.Samples))
+	}
+	if !strings.Contains(report.String(), "Запусков: 3") || strings.Count(report.String(), " #") != 3 {
+		t.Errorf("unexpected timing summary:\n%s", report.String())
+	}
+	if strings.Count(out.String(), "Файл: a.txt") != 1 {
+		t.Errorf("expected results of the final run only, got:\n%s", out.String())
+	}
+}
+
+func TestRepeatTimingStats(t *testing.T) {
+	r := RepeatTiming{Samples: []time.Duration{3, 1, 2}}
+	if r.Min() != 1 || r.Max() != 3 || r.Avg() != 2 {
+		t.Errorf("unexpected stats min=%v avg=%v max=%v", r.Min(), r.Avg(), r.Max())
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// Итоговая сводка по всем файлам
+type SummaryReport struct {
+	Files int `json:"files"`
+	// итоги line_count и word_count из Totals, поля оставлены для совместимости отчётов
+	TotalLines         int                      `json:"total_lines"`
+	TotalWords         int                      `json:"total_words"`
+	Totals             []Total                  `json:"totals,omitempty"`
+	DensityOutliers    []string                 `json:"density_outliers,omitempty"`
+	FailedFiles        []string                 `json:"failed_files,omitempty"`
+	SkippedPermission  int                      `json:"skipped_permission,omitempty"`
+	TruncatedFiles     int                      `json:"truncated_files,omitempty"` // итоги по ним неполные
+	Licenses           map[string][]string      `json:"licenses,omitempty"`
+	IndentationStyles  map[string][]string      `json:"indentation_styles,omitempty"`
+	TypeMismatches     []string                 `json:"type_mismatches,omitempty"`
+	ScriptLanguages    map[string]int           `json:"script_languages,omitempty"`
+	TopWords           []WordCount              `json:"top_words,omitempty"`
+	TopTerms           []TermCount              `json:"top_terms,omitempty"`
+	TopTags            []TagCount               `json:"top_tags,omitempty"`
+	Trend              *TrendReport             `json:"trend,omitempty"`
+	Zipf               *ZipfReport              `json:"zipf,omitempty"`
+	Pipeline           *PipelineStats           `json:"pipeline,omitempty"`
+	SizeDistribution   *Distribution            `json:"size_distribution,omitempty"`
+	WordDistribution   *Distribution            `json:"word_distribution,omitempty"`
+	DuplicateSentences map[string][]string      `json:"duplicate_sentences,omitempty"`
+	Clusters           []Cluster                `json:"clusters,omitempty"`
+	DuplicateFiles     []string                 `json:"duplicate_files,omitempty"`
+	EmptyFiles         []string                 `json:"empty_files,omitempty"`
+	VanishedFiles      []string                 `json:"vanished_files,omitempty"`
+	Activity           *CorpusActivity          `json:"activity,omitempty"`
+	SimilarParagraphs  []ParagraphCluster       `json:"similar_paragraphs,omitempty"`
+	NearDuplicates     []NearDupCluster         `json:"near_duplicates,omitempty"`
+	NearDupSkipped     int                      `json:"near_dup_skipped,omitempty"` // файлы сверх maxNearDupFiles
+	Groups             []DirectoryGroup         `json:"groups,omitempty"`
+	Normalization      string                   `json:"normalization,omitempty"`
+	Examples           map[string][]WordExample `json:"examples,omitempty"`
+	Findings           []Finding                `json:"findings,omitempty"`
+	FindingCounts      map[string]int           `json:"finding_counts,omitempty"`
+	DocumentFrequency  *DocFreqReport           `json:"document_frequency,omitempty"`
+	TFIDF              []FileTFIDF              `json:"tfidf,omitempty"`
+	FrequencyCap       int                      `json:"frequency_cap,omitempty"` // хвост TopWords приблизителен
+}
+
+// Предел числа файлов в одном списке сводки, иначе на большом корпусе
+// списки лицензий и повторов перечисляют его целиком
+const maxListedFiles = 100
+
+// Список не длиннее maxListedFiles, остаток заменяется строкой "… ещё N"
+func limitFiles(names []string) []string {
+	if len(names) <= maxListedFiles {
+		return names
+	}
+	return append(names[:maxListedFiles:maxListedFiles], fmt.Sprintf("… ещё %d", len(names)-maxListedFiles))
+}
+
+// Ограничение списков файлов сводки. Вызывается после подсчёта метрик -fail-if:
+// они берут длины полных списков
+func limitSummaryLists(s *SummaryReport) {
+	s.DensityOutliers = limitFiles(s.DensityOutliers)
+	s.TypeMismatches = limitFiles(s.TypeMismatches)
+	for l, files := range s.Licenses {
+		s.Licenses[l] = limitFiles(files)
+	}
+}
+
+// Полный отчёт для JSON вывода
+type Report struct {
+	// зерно случайных чисел запуска: выборка примеров и k-means
+	Seed      int64                `json:"seed,omitempty"`
+	Analyzers []AnalyzerInfo       `json:"analyzers,omitempty"`
+	Files     []FileAnalysisResult `json:"files"`
+	Summary   SummaryReport        `json:"summary"`
+	Diff      *RunDiff             `json:"diff,omitempty"`
+}
+
+// N самых частых слов, при равной частоте - по алфавиту
+func topWords(globalMap map[string]int, n int) []WordCount {
+	return topWordsLocale(globalMap, n, "")
+}
+
+// Как topWords, но с locale слова равной частоты упорядочиваются по правилам локали
+func topWordsLocale(globalMap map[string]int, n int, locale string) []WordCount {
+	var words []WordCount
+	for w, c := range globalMap {
+		words = append(words, WordCount{w, c})
+	}
+	if locale != "" {
+		sortWordsCollated(words, locale)
+	} else {
+		sort.Slice(words, func(i, j int) bool {
+			if words[i].Count != words[j].Count {
+				return words[i].Count > words[j].Count
+			}
+			return words[i].Word < words[j].Word
+		})
+	}
+	if n > len(words) {
+		n = len(words)
+	}
+	return words[:n]
+}
+
+// Печать результатов одного файла
+func writeFileText(out io.Writer, c colorizer, result FileAnalysisResult) {
+	if result.IsDir {
+		fmt.Fprintf(out, "Каталог: %s\n", c.bold(c.name(result.FileName)))
+		return
+	}
+	fmt.Fprintf(out, "Файл: %s, size: %d\n", c.bold(c.name(result.FileName)), result.Size)
+	for _, res := range result.Results {
+		switch res.NameAnalyzer {
+		case "word_count":
+			fmt.Fprintln(out, " words:", res.Data.(int))
+		case "line_count":
+			fmt.Fprintln(out, " lines:", res.Data.(int))
+		case "density":
+			d := res.Data.(DensityStats)
+			fmt.Fprintf(out, " density: %.2f words/line (max %d), %.2f chars/word\n", d.MeanWordsPerLine, d.MaxWordsPerLine, d.CharsPerWord)
+		case "language":
+			fmt.Fprintf(out, " language: %s (%.2f)\n", res.Data.(string), res.Confidence)
+		case "cyclomatic_complexity":
+			fmt.Fprintln(out, " complexity:", res.Data.(int))
+		case "license":
+			l := res.Data.(LicenseInfo)
+			if l.YearsFrom > 0 {
+				fmt.Fprintf(out, " license: %s (%d-%d)\n", l.License, l.YearsFrom, l.YearsTo)
+			} else {
+				fmt.Fprintf(out, " license: %s\n", l.License)
+			}
+		case "type":
+			fmt.Fprintln(out, " type:", res.Data.(string))
+		case "script_language":
+			if lang := res.Data.(string); lang != "" {
+				fmt.Fprintln(out, " script:", lang)
+			}
+		case "line_endings":
+			if e := res.Data.(LineEndings); e.Mixed {
+				fmt.Fprintf(out, " line endings: lf=%d crlf=%d cr=%d (mixed)\n", e.LF, e.CRLF, e.CR)
+				writeLineExamples(out, e.Examples)
+			}
+		case "has_final_newline":
+			if !res.Data.(bool) {
+				fmt.Fprintln(out, c.highlight(" no final newline"))
+			}
+		case "indentation":
+			if ind := res.Data.(Indentation); ind.Mixed {
+				fmt.Fprintf(out, " indentation: tabs=%d spaces=%d (mixed, %d violations)\n", ind.TabLines, ind.SpaceLines, ind.Violations)
+				writeLineExamples(out, ind.Examples)
+			}
+		case "geo_mentions":
+			if geo := res.Data.(map[string]int); len(geo) > 0 {
+				fmt.Fprintln(out, " geo:", formatCounts(geo))
+			}
+		case "diff_from_ref":
+			d := res.Data.(DiffStats)
+			approx := ""
+			if d.Approximate {
+				approx = ", approximate"
+			}
+			fmt.Fprintf(out, " diff from reference: +%d -%d (common %d%s)\n", d.Added, d.Removed, d.Common, approx)
+		case "truncated":
+			t := res.Data.(Truncations)
+			fmt.Fprintln(out, c.highlight(fmt.Sprintf(" truncated: %d tokens, %d lines (results are incomplete)", t.Tokens, t.Lines)))
+		case "paragraph_lengths":
+			if lengths := res.Data.([]int); len(lengths) > 0 {
+				lo, hi, sum := lengths[0], lengths[0], 0
+				for _, n := range lengths {
+					lo, hi, sum = min(lo, n), max(hi, n), sum+n
+				}
+				fmt.Fprintf(out, " paragraphs: %d (words min %d, mean %.1f, max %d)\n", len(lengths), lo, float64(sum)/float64(len(lengths)), hi)
+			}
+		case "markup":
+			if m := res.Data.(MarkupStats); m.Tags > 0 {
+				fmt.Fprintf(out, " markup: %d tags, depth %d, unclosed %d, text ratio %.2f\n", m.Tags, m.MaxDepth, m.Unclosed, m.TextRatio)
+			}
+		case "markdown":
+			md := res.Data.(MarkdownStats)
+			fmt.Fprintf(out, " markdown: %d code blocks, headings h1-h6 %v, %d list items\n", md.CodeBlocks, md.Headings, md.ListItems)
+			if md.UnclosedFence {
+				fmt.Fprintln(out, c.highlight(" markdown: code block is not closed"))
+			}
+		case "summary":
+			if summary := res.Data.(string); summary != "" {
+				fmt.Fprintf(out, " summary: %q\n", summary)
+			}
+		case "quotes":
+			q := res.Data.(QuoteStats)
+			if q.Count > 0 {
+				fmt.Fprintf(out, " quotes: %d (total length %d, longest: %q)\n", q.Count, q.TotalLength, q.Longest)
+				writeLineExamples(out, q.Examples)
+			}
+		}
+	}
+}
+
+func writeLineExamples(out io.Writer, examples []LineExample) {
+	for _, e := range examples {
+		fmt.Fprintf(out, "  line %d: %q\n", e.Line, e.Text)
+	}
+}
+
+// Печать итоговой сводки
+func writeSummaryText(out io.Writer, c colorizer, summary SummaryReport, opts Options) {
+	fmt.Fprintf(out, "\n%s\n", c.highlight(formatTotals(summary.Totals)))
+	if summary.TruncatedFiles > 0 {
+		fmt.Fprintf(out, "Итоги неполные: в %d файлах обрезаны длинные слова или строки\n", summary.TruncatedFiles)
+	}
+	fmt.Fprintln(out)
+
+	if len(summary.FindingCounts) > 0 {
+		writeFindingsText(out, c, summary.Findings, summary.FindingCounts)
+	}
+
+	if len(summary.DensityOutliers) > 0 {
+		fmt.Fprintln(out, "Файлы с аномальной плотностью:", c.names(summary.DensityOutliers))
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.EmptyFiles) > 0 {
+		fmt.Fprintln(out, "Пустые файлы:", c.names(summary.EmptyFiles))
+		fmt.Fprintln(out)
+	}
+
+	if summary.SkippedPermission > 0 {
+		fmt.Fprintln(out, "Пропущено файлов без прав на чтение:", summary.SkippedPermission)
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.DuplicateFiles) > 0 {
+		fmt.Fprintln(out, "Пропущены дубликаты:", c.names(summary.DuplicateFiles))
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.TypeMismatches) > 0 {
+		fmt.Fprintln(out, "Тип содержимого не совпадает с расширением:", c.names(summary.TypeMismatches))
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.ScriptLanguages) > 0 {
+		fmt.Fprintln(out, "Языки скриптов:")
+		var langs []string
+		for l := range summary.ScriptLanguages {
+			langs = append(langs, l)
+		}
+		sort.Strings(langs)
+		for _, l := range langs {
+			fmt.Fprintf(out, " %s: %d\n", l, summary.ScriptLanguages[l])
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.Licenses) > 0 {
+		fmt.Fprintln(out, "Лицензии:")
+		var licenses []string
+		for l := range summary.Licenses {
+			licenses = append(licenses, l)
+		}
+		sort.Strings(licenses)
+		for _, l := range licenses {
+			fmt.Fprintf(out, " %s: %s\n", l, c.names(summary.Licenses[l]))
+		}
+		fmt.Fprintln(out)
+	}
+
+	// единый стиль по всему дереву не интересен, печатаются только расхождения
+	if len(summary.IndentationStyles) > 1 {
+		fmt.Fprintln(out, "Стили отступов:")
+		var styles []string
+		for s := range summary.IndentationStyles {
+			styles = append(styles, s)
+		}
+		sort.Strings(styles)
+		for _, s := range styles {
+			fmt.Fprintf(out, " %s: %s\n", s, c.names(summary.IndentationStyles[s]))
+		}
+		fmt.Fprintln(out)
+	}
+
+	//Сводка ошибок
+	if opts.QuietErrors && len(summary.FailedFiles) > 0 {
+		fmt.Fprintf(out, "%d files failed: %s\n\n", len(summary.FailedFiles), c.names(summary.FailedFiles))
+	}
+
+	if summary.Activity != nil {
+		writeActivityText(out, c, *summary.Activity)
+	}
+
+	if len(summary.VanishedFiles) > 0 {
+		fmt.Fprintln(out, "Файлы исчезли во время анализа:", c.names(summary.VanishedFiles))
+		fmt.Fprintln(out)
+	}
+
+	if summary.SizeDistribution != nil {
+		writeDistributionText(out, "Размеры файлов (байты)", *summary.SizeDistribution)
+	}
+	if summary.WordDistribution != nil {
+		writeDistributionText(out, "Слов в файле", *summary.WordDistribution)
+	}
+
+	if len(summary.Clusters) > 0 {
+		writeClustersText(out, c, summary.Clusters)
+	}
+
+	if len(summary.DuplicateSentences) > 0 {
+		fmt.Fprintln(out, "Повторяющиеся предложения:")
+		var sentences []string
+		for s := range summary.DuplicateSentences {
+			sentences = append(sentences, s)
+		}
+		sort.Strings(sentences)
+		for _, s := range sentences {
+			fmt.Fprintf(out, " %q: %s\n", s, c.names(summary.DuplicateSentences[s]))
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(summary.SimilarParagraphs) > 0 {
+		writeParagraphClustersText(out, c, summary.SimilarParagraphs)
+	}
+	if len(summary.NearDuplicates) > 0 || summary.NearDupSkipped > 0 {
+		writeNearDupesText(out, c, summary.NearDuplicates, summary.NearDupSkipped)
+	}
+
+	if summary.Trend != nil {
+		writeTrendText(out, *summary.Trend)
+	}
+	if summary.Zipf != nil {
+		writeZipfText(out, *summary.Zipf)
+	}
+	if summary.Pipeline != nil {
+		writePipelineText(out, *summary.Pipeline)
+	}
+
+	if summary.FrequencyCap > 0 && len(summary.TopWords) > 0 {
+		fmt.Fprintf(out, "Частоты слов ограничены %d словами на файл, редкие слова учтены приблизительно\n", summary.FrequencyCap)
+	}
+	for i, w := range summary.TopWords {
+		fmt.Fprintf(out, "Количество слов \"%s\": %d\n", c.rank(i, w.Word), w.Count)
+		writeExamplesText(out, c, summary.Examples[w.Word])
+	}
+	for _, t := range summary.TopTerms {
+		fmt.Fprintf(out, "Количество терминов \"%s\": %d\n", t.Term, t.Count)
+	}
+	for _, t := range summary.TopTags {
+		fmt.Fprintf(out, "Количество тегов <%s>: %d\n", t.Tag, t.Count)
+	}
+	if len(summary.TFIDF) > 0 {
+		writeTFIDFText(out, c, summary.TFIDF)
+	}
+	if summary.DocumentFrequency != nil {
+		writeDocFreqText(out, c, *summary.DocumentFrequency)
+	}
+}
+
+func writeJSON(out io.Writer, report Report) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Требование -require: не меньше Min файлов с расширением Ext
+type FileRequirement struct {
+	Ext string
+	Min int
+}
+
+// Невыполненное требование -require, main завершает программу с ненулевым кодом
+type RequireError struct {
+	Requirement FileRequirement
+	Found       int
+}
+
+func (e *RequireError) Error() string {
+	return fmt.Sprintf("требование -require не выполнено: файлов %s найдено %d, нужно не меньше %d", e.Requirement.Ext, e.Found, e.Requirement.Min)
+}
+
+// Разбор списка требований через запятую, например ".txt:10,.md:2"
+func parseRequire(s string) ([]FileRequirement, error) {
+	var reqs []FileRequirement
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.LastIndex(part, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("неверное требование -require %q, ожидается .ext:N", part)
+		}
+		n, err := strconv.Atoi(part[i+1:])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("неверное число файлов в -require %q", part)
+		}
+		reqs = append(reqs, FileRequirement{Ext: part[:i], Min: n})
+	}
+	return reqs, nil
+}
+
+// Проверка требований по всем файлам обхода, без -ext, размеров, -lang и -types.
+// Расширение сравнивается как в обходе - по окончанию пути, поэтому подходит и имя вроде LICENSE
+func checkRequire(reqs []FileRequirement, files []string) error {
+	for _, r := range reqs {
+		found := 0
+		for _, f := range files {
+			if strings.HasSuffix(f, r.Ext) {
+				found++
+			}
+		}
+		if found < r.Min {
+			return &RequireError{r, found}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseRequire(t *testing.T) {
+	got, err := parseRequire(".txt:10, .md:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []FileRequirement{{".txt", 10}, {".md", 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	for _, bad := range []string{".txt", ":5", ".txt:x", ".txt:-1"} {
+		if _, err := parseRequire(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRunRequire(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("hello world"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, Require: ".txt:5"}, &out)
+	var reqErr *RequireError
+	if !errors.As(err, &reqErr) || reqErr.Found != 2 || reqErr.Requirement.Min != 5 {
+		t.Fatalf("expected require error with 2 of 5 files, got %v", err)
+	}
+	if code := exitCode(err); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if out.Len() != 0 {
+		t.Errorf("failed requirement should stop before analysis, got:\n%s", out.String())
+	}
+
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, Require: ".txt:2"}, &out); err != nil {
+		t.Errorf("requirement met, unexpected error %v", err)
+	}
+
+	// файлы вне -ext тоже учитываются
+	if err := os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("MIT"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, Require: "LICENSE:1,.md:0"}, &out); err != nil {
+		t.Errorf("LICENSE outside -ext should satisfy the requirement, got %v", err)
+	}
+}
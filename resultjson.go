@@ -0,0 +1,24 @@
+package main
+
+import "stage5/analysis"
+
+// Структуры данных встроенных анализаторов, восстанавливаемые при чтении JSON отчёта.
+// Метки пишутся в отчёт, переименование типа их не меняет.
+// Простые типы регистрирует сам пакет analysis
+func init() {
+	for tag, sample := range map[string]any{
+		"density":          DensityStats{},
+		"quotes":           QuoteStats{},
+		"license":          LicenseInfo{},
+		"line_endings":     LineEndings{},
+		"indentation":      Indentation{},
+		"paragraph_hashes": []ParagraphFingerprint(nil),
+		"minhash":          MinHashSignature(nil),
+		"diff":             DiffStats{},
+		"truncations":      Truncations{},
+		"markup":           MarkupStats{},
+		"markdown":         MarkdownStats{},
+	} {
+		analysis.RegisterResultType(tag, sample)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnalysisResultJSONPreservesTypes(t *testing.T) {
+	in := []AnalysisResult{
+		{NameAnalyzer: "word_count", Data: 42, Confidence: 1},
+		{NameAnalyzer: "most_frequent_words", Data: map[string]int{"go": 3, "rust": 1}, Confidence: 1},
+		{NameAnalyzer: "density", Data: DensityStats{MeanWordsPerLine: 2.5, MaxWordsPerLine: 4, CharsPerWord: 3}, Confidence: 1},
+		{NameAnalyzer: "has_final_newline", Data: true, Confidence: 1},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"type":"int"`) || !strings.Contains(string(data), `"type":"map[string]int"`) {
+		t.Errorf("expected type tags in %s", data)
+	}
+	var out []AnalysisResult
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip changed results:\n got %#v\nwant %#v", out, in)
+	}
+}
+
+func TestAnalysisResultJSONUntyped(t *testing.T) {
+	var r AnalysisResult
+	if err := json.Unmarshal([]byte(`{"name":"word_count","data":7,"confidence":1}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := r.Data.(float64); !ok || v != 7 {
+		t.Errorf("report without type should decode as plain JSON, got %#v", r.Data)
+	}
+
+	type custom struct{ X int }
+	data, err := json.Marshal(AnalysisResult{NameAnalyzer: "custom", Data: custom{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"type"`) {
+		t.Errorf("unregistered type should not be tagged, got %s", data)
+	}
+
+	// неизвестная метка - данные из другой программы, читаются как обычный JSON
+	if err := json.Unmarshal([]byte(`{"name":"x","data":{"a":1},"type":"other.Stats"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := r.Data.(map[string]any); !ok || m["a"] != 1.0 {
+		t.Errorf("unknown tag should decode as plain JSON, got %#v", r.Data)
+	}
+}
+
+func TestAnalysisResultJSONStableTags(t *testing.T) {
+	data, err := json.Marshal(AnalysisResult{NameAnalyzer: "density", Data: DensityStats{MaxWordsPerLine: 4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"type":"density"`) {
+		t.Errorf("expected stable tag, got %s", data)
+	}
+	// отчёты прежних версий с именем типа из reflect
+	var r AnalysisResult
+	if err := json.Unmarshal([]byte(`{"name":"density","data":{"max_words_per_line":4},"type":"main.DensityStats"}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := r.Data.(DensityStats); !ok || d.MaxWordsPerLine != 4 {
+		t.Errorf("legacy tag should decode to DensityStats, got %#v", r.Data)
+	}
+}
+
+func TestAnalysisResultJSONLazy(t *testing.T) {
+	lazy := runAnalyzer(LazyAnalyzer{WordCountAnalyzer{}}, "one two three")
+	data, err := json.Marshal(lazy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r AnalysisResult
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := r.Data.(int); !ok || n != 3 {
+		t.Errorf("expected lazy word_count to round trip as int 3, got %#v from %s", r.Data, data)
+	}
+}
@@ -0,0 +1,835 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Параметры запуска, заполняются из флагов командной строки
+type Options struct {
+	Path              string
+	Ext               string
+	Workers           int
+	TopWords          int
+	TopTerms          int
+	TopTags           int
+	MinSize           int64
+	MaxSize           int64
+	Density           bool
+	DensitySigma      float64
+	QuietErrors       bool
+	Format            string
+	ListQuotes        int
+	Language          bool
+	MinConfidence     float64
+	TokenIndex        bool
+	License           bool
+	FailIf            string
+	ChunkSize         int
+	Types             string
+	ContentType       bool
+	Color             string
+	DiffFrom          string
+	DiffThreshold     string
+	Lang              string
+	Template          string
+	Trend             string
+	TrendTop          int
+	TrendBucket       string
+	Autotune          string
+	Histogram         bool
+	DupSentences      bool
+	Cluster           int
+	NormalizeEOL      bool
+	Fix               bool
+	Dedup             bool
+	Index             string
+	Analyzers         string
+	IncludeDirs       bool
+	StalePolicy       string
+	Fields            string
+	CSVColumns        string
+	Activity          bool
+	Exclude           string
+	Analyze           string
+	RawNames          bool
+	Parallel          string
+	SimilarParagraphs float64
+	NearDupes         float64
+	FailOnReadError   bool
+	GroupBy           string
+	Locale            string
+	Summary           bool
+	CaseSensitive     bool
+	Normalize         string
+	Geo               bool
+	Examples          int
+	SparseOutput      bool
+	Require           string
+	SplitLargeFiles   int
+	DiffFromRef       string
+	FIFO              string
+	Severity          string
+	MinSeverity       string
+	FailOnSeverity    string
+	WordDocFreq       int
+	TFIDF             int
+	MaxTokenLength    int
+	MaxLineLength     int
+	MinWordLength     int
+	PreviewBytes      int64
+	CollectExamples   int
+	CappedFrequencies bool
+	FrequencyCap      int
+	Reverse           bool
+	ReportEmpty       bool
+	GOMAXPROCS        int
+	Seed              int64
+	Deterministic     bool
+	RedactPaths       bool
+	RedactMap         string
+	DumpTokens        bool
+	Zipf              bool
+	PipelineBuffer    int
+	PipelineStats     bool
+	PositionsOut      string
+	PositionsMax      int
+}
+
+// Ошибка обработки отдельного файла
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// При -fail-on-read-error первая ошибка чтения возвращается из run
+func (e *FileError) Error() string {
+	return fmt.Sprintf("ошибка чтения файла %s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Запись из нескольких горутин в один io.Writer
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Анализ файлов по opts с печатью отчёта в out
+func run(ctx context.Context, opts Options, out io.Writer) error {
+	var wg sync.WaitGroup
+	// отмена останавливает обход и воркеров при -fail-on-read-error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	colored, err := colorEnabled(opts.Color, out)
+	if err != nil {
+		return err
+	}
+	color := colorizer{enabled: colored, rawNames: opts.RawNames}
+	out = &syncWriter{w: out}
+
+	globalMap := make(map[string]int)
+	globalTerms := make(TermAggregator)
+	globalTags := make(map[string]int)
+	docFreq := make(DocumentFrequency)
+	// Частоты слов сливаются воркерами, при -dedup дубликаты отсеивает сборщик
+	var merger *wordMerger
+	if !opts.Dedup {
+		merger = newWordMerger(globalMap)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	defer setGOMAXPROCS(opts.GOMAXPROCS)()
+	if opts.DumpTokens {
+		return dumpTokens(out, opts)
+	}
+	var redactor *pathRedactor
+	if opts.RedactPaths {
+		redactor = newPathRedactor(opts.Path)
+	}
+	// зерно нужно только выборке примеров и k-means, без них в отчёт не попадает
+	randomized := opts.Cluster > 0 || opts.Examples > 0
+	// k-means зависит от порядка файлов, поэтому явное зерно упорядочивает их по путям
+	sortByPath := opts.Deterministic || opts.Seed != 0
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+		if randomized {
+			slog.Info("случайное зерно запуска, для повтора укажите -seed", "seed", opts.Seed)
+		}
+	}
+	if opts.Format == "" {
+		opts.Format = "text"
+	}
+	var outputTemplate *template.Template
+	if opts.Template != "" {
+		if outputTemplate, err = parseOutputTemplate(opts.Template, globalMap); err != nil {
+			return err
+		}
+		opts.Format = "template"
+	}
+
+	failConds, err := parseFailIf(opts.FailIf)
+	if err != nil {
+		return err
+	}
+	analyzers, err := selectAnalyzers(opts)
+	if err != nil {
+		return err
+	}
+	// эталон читается здесь, а не в defaultAnalyzers, чтобы вернуть ошибку чтения
+	if analyzers, err = withReference(analyzers, opts.DiffFromRef); err != nil {
+		return err
+	}
+	if opts.Analyze != "" {
+		analyzers = filterAnalyzers(analyzers, opts.Analyze)
+	}
+	findings := opts.MinSeverity != "" || opts.FailOnSeverity != "" || opts.Severity != ""
+	if findings {
+		analyzers = withFindingAnalyzers(analyzers, opts)
+	}
+	var fields []outputField
+	if opts.Fields != "" || opts.CSVColumns != "" || opts.Format == "csv" {
+		names := opts.Fields
+		if opts.CSVColumns != "" {
+			names = opts.CSVColumns
+		}
+		if names == "" {
+			names = defaultCSVFields
+		}
+		if fields, err = parseFields(names); err != nil {
+			return err
+		}
+		analyzers = withFieldAnalyzers(analyzers, fields, opts)
+	}
+	// CSV выводит только колонки, поэтому остальные анализаторы выполняются лишь по запросу.
+	// word_count нужен фильтру коротких файлов, -fail-if, -index, находки и -diff-from
+	// читают все результаты. Сборщик отбрасывает отложенные результаты вместе с текстом файлов
+	lazy := opts.Format == "csv" && opts.FailIf == "" && opts.Index == "" && !findings && opts.DiffFrom == ""
+	if lazy {
+		needed := map[string]bool{"word_count": true}
+		for _, f := range fields {
+			needed[f.Analyzer] = true
+		}
+		analyzers = lazyAnalyzers(analyzers, needed)
+	}
+
+	parallel, err := parseParallelMode(opts.Parallel)
+	if err != nil {
+		return err
+	}
+	normalize, err := unicodeNormalizer(opts.Normalize)
+	if err != nil {
+		return err
+	}
+	severities, err := parseSeverityOverrides(opts.Severity)
+	if err != nil {
+		return err
+	}
+	var minSeverity, failSeverity Severity
+	if opts.MinSeverity != "" {
+		if minSeverity, err = parseSeverity(opts.MinSeverity); err != nil {
+			return fmt.Errorf("-min-severity: %w", err)
+		}
+	}
+	if opts.FailOnSeverity != "" {
+		if failSeverity, err = parseSeverity(opts.FailOnSeverity); err != nil {
+			return fmt.Errorf("-fail-on-severity: %w", err)
+		}
+	}
+	requirements, err := parseRequire(opts.Require)
+	if err != nil {
+		return err
+	}
+	if opts.TrendBucket == "" {
+		opts.TrendBucket = "month"
+	}
+
+	var previous *Report
+	var diffThreshold float64
+	if opts.DiffFrom != "" {
+		if diffThreshold, err = parseDiffThreshold(opts.DiffThreshold); err != nil {
+			return err
+		}
+		report, err := loadReport(opts.DiffFrom)
+		if err != nil {
+			return fmt.Errorf("ошибка чтения предыдущего отчёта %w", err)
+		}
+		// имена прошлого отчёта скрываются так же, иначе удалённые файлы раскроют пути
+		if redactor != nil {
+			for i, f := range report.Files {
+				if !strings.HasPrefix(f.FilePath, redactedPrefix) {
+					report.Files[i].FileName = redactor.path(f.FilePath)
+				}
+			}
+		}
+		previous = &report
+	}
+
+	ext := opts.Ext
+	if opts.Lang != "" {
+		ext = ""
+	}
+	exclude := parseExclude(opts.Exclude)
+	files, unreadable, err := walkFiles(ctx, opts.Path, ext, opts.MinSize, opts.MaxSize, exclude)
+	if err != nil {
+		return fmt.Errorf("ошибка обхода файловой системы %w", err)
+	}
+	// пропуск нечитаемых файлов - не повод молча завершиться успешно при -fail-on-read-error
+	if opts.FailOnReadError && len(unreadable) > 0 {
+		u := unreadable[0]
+		return &FileError{redactor.path(u.Path), redactor.error(u.Path, u.Err)}
+	}
+	if opts.FIFO != "read" {
+		files = skipFIFOs(files)
+	}
+	// ошибки чтения заголовков для -lang и -type попадают в отчёт как ошибки файлов,
+	// а не прерывают запуск
+	var fileErrors []FileError
+	var vanishedFiles []string
+	var failed []FileError
+	if opts.Lang != "" {
+		var langFailed []FileError
+		files, langFailed = filterByLang(files, opts.Lang)
+		failed = append(failed, langFailed...)
+	}
+	if opts.Types != "" {
+		var gone []string
+		var typeFailed []FileError
+		files, gone, typeFailed = filterByType(files, opts.Types, opts.StalePolicy)
+		failed = append(failed, typeFailed...)
+		for _, path := range gone {
+			slog.Warn("файл исчез во время анализа", "path", redactor.path(path))
+			vanishedFiles = append(vanishedFiles, redactor.path(path))
+		}
+	}
+	for _, fe := range failed {
+		if opts.FailOnReadError {
+			return &FileError{redactor.path(fe.Path), redactor.error(fe.Path, fe.Err)}
+		}
+		fileErrors = append(fileErrors, FileError{redactor.path(fe.Path), redactor.error(fe.Path, fe.Err)})
+	}
+	if len(requirements) > 0 {
+		// требования проверяются по всем файлам обхода: LICENSE и go.mod не проходят -ext
+		all, unreadable, err := walkFiles(ctx, opts.Path, "", 0, 0, exclude)
+		if err != nil {
+			return fmt.Errorf("ошибка обхода файловой системы %w", err)
+		}
+		for _, u := range unreadable {
+			all = append(all, u.Path)
+		}
+		if err := checkRequire(requirements, all); err != nil {
+			return err
+		}
+	}
+	var emptyFiles []string
+	if opts.ReportEmpty {
+		files, emptyFiles = splitEmpty(files)
+	}
+	// обратный порядок отправки воркерам, с одним воркером - и вывода
+	if opts.Reverse {
+		slices.Reverse(files)
+	}
+	if len(files) == 0 && len(emptyFiles) == 0 && opts.Format == "text" {
+		fmt.Fprintln(out, "файлы с расширением", opts.Ext, "не найдены")
+	}
+
+	var dirs []FileAnalysisResult
+	if opts.IncludeDirs {
+		paths, err := listDirs(opts.Path, exclude)
+		if err != nil {
+			return fmt.Errorf("ошибка обхода файловой системы %w", err)
+		}
+		for _, p := range paths {
+			result, err := dirResult(p)
+			if err != nil {
+				return fmt.Errorf("ошибка чтения каталога %w", err)
+			}
+			dirs = append(dirs, result)
+		}
+	}
+
+	if opts.NormalizeEOL {
+		return normalizeEOLReport(out, color, files, opts.Fix)
+	}
+
+	if opts.Autotune != "" && len(files) > 0 {
+		// таблица замеров не должна ломать JSON, CSV и шаблонный вывод
+		tuneOut := out
+		if opts.Format != "text" || opts.Template != "" {
+			tuneOut = os.Stderr
+		}
+		best := autotune(tuneOut, files, analyzers)
+		if opts.Autotune == "report" {
+			return nil
+		}
+		opts.Workers = best
+	}
+
+	filePaths := feedPaths(ctx, files)
+
+	var errMu sync.Mutex
+	var readErr *FileError
+
+	memo := newContentMemo()
+	read := readSource
+	if opts.PreviewBytes > 0 {
+		read = func(path string) (fileContent, error) {
+			return readPrefix(path, opts.PreviewBytes)
+		}
+	}
+	analyze := contentAnalyzer(parallel, opts.ChunkSize)
+	if !parallel.files() {
+		opts.Workers = 1
+	}
+	if opts.SplitLargeFiles > 0 {
+		pool := newChunkPool(opts.Workers)
+		defer pool.close()
+		analyze = splittingAnalyzer(analyze, opts.SplitLargeFiles, pool)
+	}
+	analyze = truncatingAnalyzer(analyze, opts.MaxTokenLength, opts.MaxLineLength)
+	analyze = normalizingAnalyzer(analyze, normalize)
+	analyze = positionsAnalyzer(analyze, opts)
+	buffer := pipelineBuffer(opts.PipelineBuffer, opts.Workers)
+	results := make(chan FileAnalysisResult, buffer)
+	filteredResults := make(chan FileAnalysisResult, buffer)
+	var stalls stallMeter
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			analyzers := cloneAnalyzers(analyzers)
+			var words *partialWords
+			if merger != nil {
+				words = merger.partial(wordMergeEvery)
+				defer words.flush()
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case path, ok := <-filePaths:
+					if !ok {
+						return
+					}
+
+					result, gone, err := analyzeFileStale(path, read, analyzers, memo, analyze, opts.StalePolicy)
+					if gone {
+						slog.Warn("файл исчез во время анализа", "path", redactor.path(path))
+						errMu.Lock()
+						vanishedFiles = append(vanishedFiles, redactor.path(path))
+						errMu.Unlock()
+						continue
+					}
+					if err != nil {
+						err, path := redactor.error(path, err), redactor.path(path)
+						errMu.Lock()
+						if opts.FailOnReadError {
+							if readErr == nil {
+								readErr = &FileError{path, err}
+								cancel()
+							}
+							errMu.Unlock()
+							return
+						}
+						fileErrors = append(fileErrors, FileError{path, err})
+						if !opts.QuietErrors && opts.Format == "text" {
+							fmt.Fprintln(out, "ошибка обработки файла", color.name(err.Error()))
+						}
+						errMu.Unlock()
+						continue
+					}
+					for _, verr := range ValidateResult(result) {
+						slog.Warn("некорректный результат анализатора", "error", verr)
+					}
+					result.Results = filterByConfidence(result.Results, opts.MinConfidence)
+					if words != nil && enoughWords(result) {
+						for _, r := range result.Results {
+							if freq, ok := r.Data.(map[string]int); ok && r.NameAnalyzer == "most_frequent_words" {
+								words.add(freq)
+							}
+						}
+					}
+					if !sendMeasured(ctx, &stalls, results, result) {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	//фильтрация на лету
+	go func() {
+		defer close(filteredResults)
+		for res := range results {
+			if enoughWords(res) {
+				filteredResults <- res
+			}
+		}
+	}()
+
+	//Сбор результатов в карту и печать
+	var summary SummaryReport
+	summary.EmptyFiles = redactor.paths(emptyFiles)
+	summary.SkippedPermission = len(unreadable)
+	if normalize != nil {
+		summary.Normalization = opts.Normalize
+	}
+	var collected []FileAnalysisResult
+	printFile := func(result FileAnalysisResult) {
+		if opts.SparseOutput {
+			result = sparseFile(result)
+		}
+		if fields != nil && !result.IsDir {
+			writeFieldsText(out, color, fields, result)
+		} else {
+			writeFileText(out, color, result)
+		}
+	}
+	// при группировке файлы печатаются после сбора всех результатов
+	streaming := opts.Format == "text" && opts.GroupBy == ""
+	for _, result := range dirs {
+		result = redactor.result(result)
+		collected = append(collected, result)
+		if streaming {
+			printFile(result)
+		}
+	}
+	totals := newTotalsAggregator(analyzers)
+	var sampler *exampleSampler
+	if opts.Examples > 0 {
+		sampler = newExampleSampler(opts.Examples)
+	}
+	// ошибка записи -positions-out, результаты дочитываются, чтобы не блокировать воркеры
+	var positionsErr error
+	collect := func(result FileAnalysisResult) {
+		result = redactor.result(result)
+		if positions, ok := takePositions(&result); ok && positionsErr == nil {
+			positionsErr = writePositions(opts.PositionsOut, positionsName(opts.Path, result.FilePath, redactor), positions)
+		}
+		if lazy {
+			result.Results = withoutLazy(result.Results)
+		}
+		if words, ok := takeExamples(&result); ok && sampler != nil {
+			sampler.add(result.FilePath, words)
+		}
+		collected = append(collected, result)
+		if streaming {
+			printFile(result)
+		}
+		for _, res := range result.Results {
+			if isLazy(res) {
+				continue
+			}
+			totals.add(res)
+			switch res.NameAnalyzer {
+			case "most_frequent_words":
+				freq := res.Data.(map[string]int)
+				if opts.WordDocFreq > 0 {
+					docFreq.Add(freq)
+				}
+				if merger != nil {
+					continue
+				}
+				for word, count := range freq {
+					globalMap[word] += count
+				}
+			case "terms":
+				globalTerms.Add(res.Data.(map[string]int))
+			case "markup":
+				for tag, count := range res.Data.(MarkupStats).TagCounts {
+					globalTags[tag] += count
+				}
+			case "truncated":
+				summary.TruncatedFiles++
+			}
+		}
+	}
+	// при -dedup из копий остаётся первая по пути, а не первая пришедшая от воркеров,
+	// поэтому копии собираются до конца анализа
+	kept := make(map[string]FileAnalysisResult)
+	var duplicates []FileAnalysisResult
+	for result := range filteredResults {
+		if !opts.Dedup {
+			collect(result)
+			continue
+		}
+		if prev, ok := kept[result.ContentHash]; ok {
+			if result.FilePath < prev.FilePath {
+				kept[result.ContentHash], result = result, prev
+			}
+			duplicates = append(duplicates, result)
+			continue
+		}
+		kept[result.ContentHash] = result
+	}
+	if opts.Dedup {
+		var unique []FileAnalysisResult
+		for _, result := range kept {
+			unique = append(unique, result)
+		}
+		for _, files := range [][]FileAnalysisResult{unique, duplicates} {
+			sort.Slice(files, func(i, j int) bool { return files[i].FilePath < files[j].FilePath })
+		}
+		for _, result := range unique {
+			collect(result)
+		}
+		for _, dup := range duplicates {
+			summary.DuplicateFiles = append(summary.DuplicateFiles, redactor.result(dup).FileName)
+		}
+	}
+
+	if readErr != nil {
+		return readErr
+	}
+	// отмена извне: часть файлов не проанализирована, неполный отчёт не выводится
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if positionsErr != nil {
+		return fmt.Errorf("запись -positions-out: %w", positionsErr)
+	}
+	if sortByPath {
+		sort.SliceStable(collected, func(i, j int) bool {
+			return collected[i].FilePath < collected[j].FilePath
+		})
+	}
+
+	summary.Files = len(collected) - len(dirs)
+	summary.Totals = totals.totals()
+	summary.TotalLines = int(totalValue(summary.Totals, "line_count"))
+	summary.TotalWords = int(totalValue(summary.Totals, "word_count"))
+	if opts.GroupBy != "" {
+		groups := groupResults(collected, opts.GroupBy, opts.Path)
+		summary.Groups = directoryGroups(groups)
+		if opts.Format == "text" {
+			writeGroupsText(out, color, groups, printFile)
+		}
+	}
+	if opts.License {
+		summary.Licenses = groupByLicense(collected)
+	}
+
+	if opts.Histogram {
+		sizes, words := newSizeHistogram(), newWordHistogram()
+		for _, res := range collected {
+			if res.IsDir {
+				continue
+			}
+			sizes.Add(float64(res.Size))
+			if n, ok := numericResult(res, "word_count"); ok {
+				words.Add(float64(n))
+			}
+		}
+		sizeDist, wordDist := sizes.Distribution(), words.Distribution()
+		summary.SizeDistribution, summary.WordDistribution = &sizeDist, &wordDist
+	}
+
+	if opts.Activity {
+		summary.Activity = computeActivity(collected)
+	}
+
+	if opts.Cluster > 0 {
+		summary.Clusters = ClusterFiles(collected, opts.Cluster, opts.Seed)
+	}
+	if opts.DupSentences {
+		summary.DuplicateSentences = FindDuplicateSentences(collected, 2)
+	}
+	if opts.SimilarParagraphs > 0 {
+		summary.SimilarParagraphs = FindSimilarParagraphs(collected, opts.SimilarParagraphs)
+	}
+	if opts.NearDupes > 0 {
+		summary.NearDuplicates, summary.NearDupSkipped = FindNearDuplicates(collected, opts.NearDupes)
+		if summary.NearDupSkipped > 0 {
+			slog.Warn("часть файлов не сравнивалась на почти-дубликаты", "limit", maxNearDupFiles, "skipped", summary.NearDupSkipped)
+		}
+	}
+
+	summary.TypeMismatches = findTypeMismatches(collected)
+	if findings {
+		summary.Findings, summary.FindingCounts = collectFindings(collected, severities, minSeverity)
+	}
+	summary.ScriptLanguages = countScriptLanguages(collected)
+	summary.IndentationStyles = groupByIndentation(collected)
+
+	//Поиск файлов с аномальной плотностью
+	summary.DensityOutliers = FindDensityOutliers(collected, opts.DensitySigma)
+
+	sort.Slice(fileErrors, func(i, j int) bool {
+		return fileErrors[i].Path < fileErrors[j].Path
+	})
+	for _, fe := range fileErrors {
+		summary.FailedFiles = append(summary.FailedFiles, fe.Path)
+	}
+	sort.Strings(vanishedFiles)
+	summary.VanishedFiles = vanishedFiles
+
+	//Поиск общих слов
+	if opts.TopWords > 0 {
+		if opts.CappedFrequencies {
+			summary.FrequencyCap = opts.FrequencyCap
+		}
+		summary.TopWords = topWordsLocale(globalMap, opts.TopWords, opts.Locale)
+		if sampler != nil {
+			summary.Examples = sampler.examples(summary.TopWords)
+		}
+	}
+	if opts.PipelineStats {
+		pipeline := stalls.stats(buffer)
+		summary.Pipeline = &pipeline
+	}
+	if opts.Zipf {
+		zipf := ZipfAnalysis(globalMap)
+		summary.Zipf = &zipf
+	}
+	if opts.TopTerms > 0 {
+		summary.TopTerms = globalTerms.Top(opts.TopTerms)
+	}
+	if opts.TopTags > 0 {
+		summary.TopTags = topTags(globalTags, opts.TopTags)
+	}
+	if opts.TFIDF > 0 {
+		summary.TFIDF = topTFIDF(collected, opts.TFIDF)
+	}
+	if opts.WordDocFreq > 0 {
+		summary.DocumentFrequency = &DocFreqReport{
+			SingleFileWords: docFreq.SingleFile(),
+			TotalWords:      len(docFreq),
+			Words:           docFreq.Top(opts.WordDocFreq),
+		}
+	}
+
+	var trendWords []string
+	if opts.TrendTop > 0 {
+		for _, w := range topWords(globalMap, opts.TrendTop) {
+			trendWords = append(trendWords, w.Word)
+		}
+	} else if opts.Trend != "" {
+		trendWords = parseTrendWords(opts.Trend)
+	}
+	if len(trendWords) > 0 {
+		trend, err := computeTrend(collected, trendWords, opts.TrendBucket)
+		if err != nil {
+			return err
+		}
+		summary.Trend = &trend
+	}
+
+	if opts.Index != "" {
+		if err := writeIndexFile(opts.Index, BuildIndex(collected)); err != nil {
+			return fmt.Errorf("ошибка записи индекса %w", err)
+		}
+	}
+
+	metrics := summaryMetrics(summary)
+	limitSummaryLists(&summary)
+
+	report := Report{Analyzers: analyzerManifest(analyzers), Files: collected, Summary: summary}
+	if randomized {
+		report.Seed = opts.Seed
+	}
+	if opts.SparseOutput {
+		report.Files = sparseFiles(collected)
+	}
+	if opts.Deterministic {
+		report.Files = withoutModTimes(report.Files)
+	}
+	if previous != nil {
+		diff := computeRunDiff(previous.Files, collected, opts.Path, diffThreshold)
+		report.Diff = &diff
+	}
+
+	switch opts.Format {
+	case "json":
+		if err := writeJSON(out, report); err != nil {
+			return err
+		}
+	case "csv":
+		if err := writeCSV(out, fields, collected); err != nil {
+			return err
+		}
+	case "template":
+		if err := writeTemplate(out, outputTemplate, TemplateData{collected, summary}); err != nil {
+			return err
+		}
+	default:
+		writeSummaryText(out, color, summary, opts)
+		if report.Diff != nil {
+			if err := writeRunDiffText(out, color, redactor.path(opts.DiffFrom), *report.Diff); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.RedactMap != "" {
+		if err := redactor.writeMap(opts.RedactMap); err != nil {
+			return fmt.Errorf("ошибка записи -redact-map %w", err)
+		}
+	}
+	if err := checkFailIf(failConds, metrics); err != nil {
+		return err
+	}
+	if opts.FailOnSeverity != "" {
+		return checkFailOnSeverity(summary.FindingCounts, failSeverity)
+	}
+	return nil
+}
+
+// Файлы меньше чем из двух слов не попадают в отчёт
+func enoughWords(res FileAnalysisResult) bool {
+	for _, r := range res.Results {
+		if n, ok := r.Data.(int); ok && r.NameAnalyzer == "word_count" && n < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// Копия результатов без времени изменения файлов, для -deterministic
+func withoutModTimes(results []FileAnalysisResult) []FileAnalysisResult {
+	out := make([]FileAnalysisResult, len(results))
+	for i, res := range results {
+		res.ModTime = time.Time{}
+		out[i] = res
+	}
+	return out
+}
+
+// Канал путей для воркеров. Закрывает его только эта горутина: после отмены ctx
+// оставшиеся пути не отправляются, а канал закрывается один раз
+func feedPaths(ctx context.Context, files []string) <-chan string {
+	paths := make(chan string, 100)
+	go func() {
+		defer close(paths)
+		for _, file := range files {
+			select {
+			case <-ctx.Done():
+				return
+			case paths <- file:
+			}
+		}
+	}()
+	return paths
+}
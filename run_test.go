@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunQuietErrors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "good.txt"), []byte("hello world"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bad := filepath.Join(dir, "bad.txt")
+	if err := os.Symlink(filepath.Join(dir, "missing"), bad); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, QuietErrors: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	output := out.String()
+	if strings.Contains(output, "ошибка обработки файла") {
+		t.Errorf("expected no inline errors, got:\n%s", output)
+	}
+	if !strings.Contains(output, "1 files failed: "+bad) {
+		t.Errorf("expected error summary listing %s, got:\n%s", bad, output)
+	}
+}
+
+func TestRunFailOnReadError(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 20; i++ {
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("good%02d.txt", i)), []byte("hello world"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bad := filepath.Join(dir, "a_bad.txt")
+	if err := os.Symlink(filepath.Join(dir, "missing"), bad); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	reads := 0
+	readSource = func(path string) (fileContent, error) {
+		reads++
+		return readFile(path)
+	}
+	t.Cleanup(func() { readSource = readFile })
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, FailOnReadError: true}
+	err := run(context.Background(), opts, &out)
+	var readErr *FileError
+	if !errors.As(err, &readErr) || readErr.Path != bad || !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected read error for %s, got %v", bad, err)
+	}
+	if code := exitCode(err); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if reads != 1 {
+		t.Errorf("expected the run to stop after the failed read, %d files read", reads)
+	}
+	if strings.Contains(out.String(), "files failed") {
+		t.Errorf("aborted run should not print a summary, got:\n%s", out.String())
+	}
+
+	if code := exitCode(errors.New("другая ошибка")); code != 1 {
+		t.Errorf("expected exit code 1 for other errors, got %d", code)
+	}
+	if code := exitCode(Options{}.Validate()); code != 2 {
+		t.Errorf("expected exit code 2 for invalid options, got %d", code)
+	}
+	if code := exitCode(nil); code != 0 {
+		t.Errorf("expected exit code 0 without error, got %d", code)
+	}
+}
+
+// Полный текстовый вывод на известном наборе файлов сравнивается с testdata/golden_text.txt.
+// После намеренного изменения формата: go test -run TestGoldenOutput -update-golden
+func TestGoldenOutput(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"alpha.txt": "Первая строка текста.\nВторая строка текста!\n",
+		"beta.txt":  "go is fun\ngo is fast\n\n    indented \"quoted words\" here\n",
+		"gamma.txt": "one two three\r\nfour five six\r\nseven\n",
+	})
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, TopWords: 5, TopTerms: 3, DensitySigma: 2}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "golden_text.txt")
+	if *updateGolden {
+		if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != string(want) {
+		t.Errorf("text output differs from golden:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestRunReverse(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt":     "one two",
+		"b.txt":     "three four",
+		"sub/c.txt": "five six",
+	})
+	order := func(reverse bool) []string {
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Reverse: reverse}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, f := range report.Files {
+			names = append(names, f.FileName)
+		}
+		return names
+	}
+	if got := strings.Join(order(false), ","); got != "a.txt,b.txt,c.txt" {
+		t.Errorf("expected lexical order, got %s", got)
+	}
+	if got := strings.Join(order(true), ","); got != "c.txt,b.txt,a.txt" {
+		t.Errorf("expected reversed order, got %s", got)
+	}
+}
+
+// Одинаковое зерно даёт одинаковые кластеры и примеры при нескольких воркерах
+// и без -deterministic, а с ним - побайтно одинаковый JSON
+func TestRunSeedReproducible(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i < 8; i++ {
+		var b strings.Builder
+		for j := 0; j < 6; j++ {
+			fmt.Fprintf(&b, "Go channels move data %d. Rust borrows memory %d. Cats chase mice %d. ", (i+j)%3, j, i%2)
+		}
+		files[fmt.Sprintf("doc%d.txt", i)] = b.String()
+	}
+	dir := testutil.CreateTempDir(t, files)
+
+	runJSON := func(seed int64, deterministic bool) []byte {
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 4, Format: "json", TopWords: 3, Examples: 2, Cluster: 3, Seed: seed, Deterministic: deterministic}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.Bytes()
+	}
+	first := runJSON(42, true)
+	if second := runJSON(42, true); !bytes.Equal(first, second) {
+		t.Errorf("expected identical output for the same seed:\n%s\n---\n%s", first, second)
+	}
+
+	var report Report
+	if err := json.Unmarshal(first, &report); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		var seeded Report
+		if err := json.Unmarshal(runJSON(42, false), &seeded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(seeded.Summary.Clusters, report.Summary.Clusters) || !reflect.DeepEqual(seeded.Summary.Examples, report.Summary.Examples) {
+			t.Fatalf("-seed without -deterministic changed clusters or examples:\n%+v\n---\n%+v", seeded.Summary, report.Summary)
+		}
+	}
+	if report.Seed != 42 || len(report.Summary.Clusters) == 0 || len(report.Summary.Examples) == 0 {
+		t.Errorf("expected seed 42 with clusters and examples, got seed %d, %d clusters, %d examples",
+			report.Seed, len(report.Summary.Clusters), len(report.Summary.Examples))
+	}
+	if !report.Files[0].ModTime.IsZero() || report.Files[0].FileName != "doc0.txt" {
+		t.Errorf("expected files sorted by path without mod times, got %s at %v", report.Files[0].FileName, report.Files[0].ModTime)
+	}
+}
+
+func TestRunSkipsUnreadable(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"ok.txt":     "hello world",
+		"secret.txt": "hidden words here",
+	})
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.Chmod(secret, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(secret, 0o644)
+	if readable(secret) == nil {
+		t.Skip("file mode does not restrict reading (root or no Unix permissions)")
+	}
+
+	files, unreadable, err := dirTraversal(dir, ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || filepath.Base(files[0]) != "ok.txt" || len(unreadable) != 1 {
+		t.Errorf("expected only ok.txt and 1 unreadable, got %v and %v", files, unreadable)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Summary.SkippedPermission != 1 || len(report.Summary.FailedFiles) != 0 || len(report.Files) != 1 {
+		t.Errorf("expected 1 skipped file and no read failures, got %+v", report.Summary)
+	}
+
+	opts.FailOnReadError = true
+	err = run(context.Background(), opts, &out)
+	var readErr *FileError
+	if !errors.As(err, &readErr) || readErr.Path != secret || !errors.Is(err, fs.ErrPermission) || exitCode(err) != 1 {
+		t.Errorf("expected permission error for %s with -fail-on-read-error, got %v", secret, err)
+	}
+}
+
+func TestDirTraversalSkipsUnreadableDir(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"ok.txt":            "hello world",
+		"private/inner.txt": "hidden words here",
+	})
+	private := filepath.Join(dir, "private")
+	if err := os.Chmod(private, 0o000); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(private, 0o755)
+	if _, err := os.ReadDir(private); err == nil {
+		t.Skip("directory mode does not restrict reading (root or no Unix permissions)")
+	}
+
+	files, unreadable, err := dirTraversal(dir, ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || len(unreadable) != 1 || unreadable[0].Path != private {
+		t.Errorf("expected ok.txt and skipped %s, got %v and %v", private, files, unreadable)
+	}
+}
+
+// Вывод, отменяющий контекст после первого напечатанного файла
+type cancelAfterFirstFile struct {
+	bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancelAfterFirstFile) Write(p []byte) (int, error) {
+	if bytes.HasPrefix(p, []byte("Файл:")) {
+		w.cancel()
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestRunCancelledAfterFirstFile(t *testing.T) {
+	files := make(map[string]string)
+	for i := 0; i < 200; i++ {
+		files[fmt.Sprintf("f%03d.txt", i)] = "hello world again"
+	}
+	dir := testutil.CreateTempDir(t, files)
+
+	for _, buffer := range []int{0, -1} {
+		t.Run(fmt.Sprint("buffer ", buffer), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			out := &cancelAfterFirstFile{cancel: cancel}
+			done := make(chan error, 1)
+			go func() {
+				done <- run(ctx, Options{Path: dir, Ext: ".txt", Workers: 4, PipelineBuffer: buffer}, out)
+			}()
+			select {
+			case err := <-done:
+				if !errors.Is(err, context.Canceled) || exitCode(err) != 130 {
+					t.Errorf("expected context.Canceled with exit code 130, got %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("run did not return after cancellation")
+			}
+			if strings.Contains(out.String(), "word_count = ") {
+				t.Error("summary of an incomplete run should not be printed")
+			}
+		})
+	}
+}
+
+func TestFeedPathsCancelled(t *testing.T) {
+	files := make([]string, 1000)
+	for i := range files {
+		files[i] = fmt.Sprintf("f%d.txt", i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	paths := feedPaths(ctx, files)
+	<-paths
+	cancel()
+
+	// канал закрывается ровно один раз и не больше чем после всех путей
+	n := 1
+	for range paths {
+		n++
+	}
+	if n > len(files) {
+		t.Errorf("received %d paths from %d files", n, len(files))
+	}
+	if _, ok := <-paths; ok {
+		t.Error("channel should stay closed")
+	}
+}
+
+// Из копий остаётся первая по пути независимо от порядка обработки
+func TestRunDedupKeepsFirstPath(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt":     "same words here",
+		"b.txt":     "same words here",
+		"sub/c.txt": "same words here",
+		"other.txt": "different words",
+	})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", Dedup: true, Reverse: true}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range report.Files {
+		names = append(names, f.FileName)
+	}
+	if got := strings.Join(names, ","); got != "a.txt,other.txt" {
+		t.Errorf("expected a.txt to be kept, got %s", got)
+	}
+	if got := strings.Join(report.Summary.DuplicateFiles, ","); got != "b.txt,c.txt" {
+		t.Errorf("expected b.txt and c.txt as duplicates, got %s", got)
+	}
+}
+
+// Плотность, язык и тип содержимого не меняют обычный отчёт и включаются своими флагами
+func TestRunOptionalAnalyzers(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": "the quick brown fox jumps over the lazy dog"})
+	names := func(opts Options) map[string]bool {
+		opts.Path, opts.Ext, opts.Workers, opts.Format = dir, ".txt", 1, "json"
+		var out bytes.Buffer
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]bool)
+		for _, r := range report.Files[0].Results {
+			got[r.NameAnalyzer] = true
+		}
+		return got
+	}
+	optional := []string{"density", "language", "type"}
+	got := names(Options{})
+	for _, name := range optional {
+		if got[name] {
+			t.Errorf("%s should be off by default", name)
+		}
+	}
+	got = names(Options{Density: true, Language: true, ContentType: true})
+	for _, name := range optional {
+		if !got[name] {
+			t.Errorf("%s should be enabled by its flag, got %v", name, got)
+		}
+	}
+}
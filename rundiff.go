@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"stage5/analysis"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Изменение метрики файла между запусками
+type MetricChange struct {
+	File    string  `json:"file"`
+	Metric  string  `json:"metric"`
+	Old     int     `json:"old"`
+	New     int     `json:"new"`
+	Percent float64 `json:"percent"`
+}
+
+// Разница между предыдущим и текущим запуском
+type RunDiff struct {
+	Added   []string       `json:"added,omitempty"`
+	Removed []string       `json:"removed,omitempty"`
+	Changed []MetricChange `json:"changed,omitempty"`
+}
+
+// Метрики, изменения которых попадают в отчёт
+var diffMetrics = []struct {
+	analyzer string
+	label    string
+}{
+	{"word_count", "words"},
+	{"line_count", "lines"},
+}
+
+var runDiffTemplate = template.Must(template.New("diff").Parse(`--- {{.From}}
++++ current
+{{range .Diff.Added}}+ {{.}}
+{{end}}{{range .Diff.Removed}}- {{.}}
+{{end}}{{range .Diff.Changed}}~ {{.File}}: {{.Metric}} {{.Old}} -> {{.New}} ({{printf "%+.1f" .Percent}}%)
+{{end}}`))
+
+// Загрузка отчёта, сохранённого с -format json
+func loadReport(path string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	err = json.Unmarshal(data, &report)
+	return report, err
+}
+
+// Разбор порога вида "10%" или "10" в процентах
+func parseDiffThreshold(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(s), "%"), 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("неверный порог -diff-threshold %q", s)
+	}
+	return v, nil
+}
+
+// Числовой результат анализатора; в отчётах без поля type числа читаются как float64
+func numericResult(res FileAnalysisResult, analyzer string) (int, bool) {
+	for _, r := range res.Results {
+		if r.NameAnalyzer != analyzer {
+			continue
+		}
+		switch v := r.Data.(type) {
+		case int:
+			return v, true
+		case float64:
+			return int(v), true
+		}
+	}
+	return 0, false
+}
+
+// Сравнение запусков через analysis.Diff: добавленные и удалённые файлы и изменения
+// метрик diffMetrics больше чем на thresholdPercent процентов. Файлы сопоставляются
+// по пути относительно root: одноимённые файлы из разных каталогов не смешиваются
+func computeRunDiff(before, after []FileAnalysisResult, root string, thresholdPercent float64) RunDiff {
+	labels := make(map[string]string, len(diffMetrics))
+	opts := analysis.DiffOptions{
+		ThresholdPercent: thresholdPercent,
+		Key:              func(r FileAnalysisResult) string { return relativePath(root, r.FilePath) },
+	}
+	for _, m := range diffMetrics {
+		labels[m.analyzer] = m.label
+		opts.Analyzers = append(opts.Analyzers, m.analyzer)
+	}
+	report := analysis.Diff(before, after, opts)
+
+	diff := RunDiff{Added: report.Added, Removed: report.Removed}
+	for _, fd := range report.Changed {
+		for _, m := range fd.Metrics {
+			diff.Changed = append(diff.Changed, MetricChange{fd.File, labels[m.Analyzer], int(m.Old), int(m.New), m.Percent})
+		}
+	}
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		if diff.Changed[i].File != diff.Changed[j].File {
+			return diff.Changed[i].File < diff.Changed[j].File
+		}
+		return diff.Changed[i].Metric < diff.Changed[j].Metric
+	})
+	return diff
+}
+
+// Путь относительно root в виде со слешами, вне root - путь как есть
+func relativePath(root, path string) string {
+	rel, err := filepath.Rel(root, filepath.FromSlash(path))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func writeRunDiffText(out io.Writer, c colorizer, from string, diff RunDiff) error {
+	escaped := RunDiff{Changed: make([]MetricChange, len(diff.Changed))}
+	for _, f := range diff.Added {
+		escaped.Added = append(escaped.Added, c.name(f))
+	}
+	for _, f := range diff.Removed {
+		escaped.Removed = append(escaped.Removed, c.name(f))
+	}
+	for i, ch := range diff.Changed {
+		ch.File = c.name(ch.File)
+		escaped.Changed[i] = ch
+	}
+	diff = escaped
+	return runDiffTemplate.Execute(out, struct {
+		From string
+		Diff RunDiff
+	}{from, diff})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func fileWithCounts(name string, words, lines int) FileAnalysisResult {
+	return FileAnalysisResult{
+		FileName: name,
+		FilePath: name,
+		Results: []AnalysisResult{
+			{NameAnalyzer: "word_count", Data: words},
+			{NameAnalyzer: "line_count", Data: lines},
+		},
+	}
+}
+
+func TestComputeRunDiff(t *testing.T) {
+	before := []FileAnalysisResult{
+		fileWithCounts("same.txt", 100, 10),
+		fileWithCounts("grown.txt", 100, 10),
+		fileWithCounts("small_change.txt", 100, 10),
+		fileWithCounts("removed.txt", 5, 1),
+	}
+	after := []FileAnalysisResult{
+		fileWithCounts("same.txt", 100, 10),
+		fileWithCounts("grown.txt", 150, 10),
+		fileWithCounts("small_change.txt", 105, 10),
+		fileWithCounts("added.txt", 5, 1),
+	}
+
+	diff := computeRunDiff(before, after, "", 10)
+
+	if len(diff.Added) != 1 || diff.Added[0] != "added.txt" {
+		t.Errorf("expected added.txt added, got %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0] != "removed.txt" {
+		t.Errorf("expected removed.txt removed, got %v", diff.Removed)
+	}
+	want := MetricChange{"grown.txt", "words", 100, 150, 50}
+	if len(diff.Changed) != 1 || diff.Changed[0] != want {
+		t.Errorf("expected %+v changed, got %+v", want, diff.Changed)
+	}
+
+	var out bytes.Buffer
+	if err := writeRunDiffText(&out, colorizer{}, "old.json", diff); err != nil {
+		t.Fatal(err)
+	}
+	expected := "--- old.json\n+++ current\n+ added.txt\n- removed.txt\n~ grown.txt: words 100 -> 150 (+50.0%)\n"
+	if out.String() != expected {
+		t.Errorf("unexpected diff output:\n%s", out.String())
+	}
+}
+
+func TestRunDiffFromJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one two three four"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	previous, err := json.Marshal(Report{Files: []FileAnalysisResult{fileWithCounts("a.txt", 2, 1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevPath := filepath.Join(t.TempDir(), "prev.json")
+	if err := os.WriteFile(prevPath, previous, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, DiffFrom: prevPath, DiffThreshold: "10%"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "~ a.txt: words 2 -> 4 (+100.0%)") {
+		t.Errorf("expected words change in output:\n%s", out.String())
+	}
+}
+
+// Одноимённые файлы из разных каталогов сравниваются каждый со своей копией
+func TestRunDiffSameBaseNames(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a/x.txt": "one two three four",
+		"b/x.txt": "one two",
+	})
+	var previous bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json"}
+	if err := run(context.Background(), opts, &previous); err != nil {
+		t.Fatal(err)
+	}
+	prevPath := filepath.Join(t.TempDir(), "prev.json")
+	if err := os.WriteFile(prevPath, previous.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	opts.DiffFrom, opts.DiffThreshold = prevPath, "10%"
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if d := report.Diff; d == nil || len(d.Added)+len(d.Removed)+len(d.Changed) != 0 {
+		t.Errorf("expected no changes for an unchanged tree, got %+v", d)
+	}
+}
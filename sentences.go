@@ -0,0 +1,107 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Предел числа отслеживаемых предложений при поиске повторов
+const maxTrackedSentences = 10000
+
+// Разбиение текста на предложения по . ! ? и пустым строкам.
+// Пробельные символы внутри предложения схлопываются в один пробел.
+func splitSentences(content string) []string {
+	var sentences []string
+	var current []string
+	flush := func() {
+		if len(current) > 0 {
+			sentences = append(sentences, strings.Join(current, " "))
+			current = current[:0]
+		}
+	}
+	for _, para := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		for _, word := range strings.Fields(para) {
+			current = append(current, word)
+			if strings.ContainsAny(word[len(word)-1:], ".!?") || strings.HasSuffix(word, "…") {
+				flush()
+			}
+		}
+		flush()
+	}
+	return sentences
+}
+
+// Анализатор предложений: уникальные предложения файла в порядке появления
+type SentenceAnalyzer struct{}
+
+func (s SentenceAnalyzer) Name() string {
+	return "sentences"
+}
+func (s SentenceAnalyzer) Analyze(content string) AnalysisResult {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, sentence := range splitSentences(content) {
+		if !seen[sentence] && strings.IndexFunc(sentence, unicode.IsLetter) >= 0 {
+			seen[sentence] = true
+			unique = append(unique, sentence)
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: s.Name(),
+		Data:         unique,
+	}
+}
+
+// Разнообразие предложений: доля уникальных среди всех предложений файла.
+// Повторяющийся шаблонный текст даёт значение около 0, обычная проза - около 1.
+// Для текста без предложений - 0
+type SentenceDiversityAnalyzer struct{}
+
+func (s SentenceDiversityAnalyzer) Name() string {
+	return "sentence_diversity"
+}
+func (s SentenceDiversityAnalyzer) Analyze(content string) AnalysisResult {
+	sentences := splitSentences(content)
+	unique := make(map[string]bool)
+	for _, sentence := range sentences {
+		unique[strings.TrimSpace(sentence)] = true
+	}
+	diversity := 0.0
+	if len(sentences) > 0 {
+		diversity = float64(len(unique)) / float64(len(sentences))
+	}
+	return AnalysisResult{
+		NameAnalyzer: s.Name(),
+		Data:         diversity,
+	}
+}
+
+// Предложения, встречающиеся дословно не менее чем в minFiles файлах: предложение -> файлы,
+// не больше maxListedFiles на предложение. Отслеживается не больше maxTrackedSentences разных предложений.
+func FindDuplicateSentences(results []FileAnalysisResult, minFiles int) map[string][]string {
+	files := make(map[string][]string)
+	for _, res := range results {
+		for _, r := range res.Results {
+			sentences, ok := r.Data.([]string)
+			if !ok || r.NameAnalyzer != "sentences" {
+				continue
+			}
+			for _, sentence := range sentences {
+				if _, tracked := files[sentence]; !tracked && len(files) >= maxTrackedSentences {
+					continue
+				}
+				files[sentence] = append(files[sentence], res.FilePath)
+			}
+		}
+	}
+
+	dups := make(map[string][]string)
+	for sentence, names := range files {
+		if len(names) >= minFiles {
+			sort.Strings(names)
+			dups[sentence] = limitFiles(names)
+		}
+	}
+	return dups
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitSentences(t *testing.T) {
+	content := "First sentence.  Second\none! Third?\n\nHeading without dot\nNext para."
+	want := []string{"First sentence.", "Second one!", "Third?", "Heading without dot Next para."}
+	if got := splitSentences(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestFindDuplicateSentences(t *testing.T) {
+	results := []FileAnalysisResult{
+		{FileName: "a.txt", FilePath: "a.txt", Results: []AnalysisResult{SentenceAnalyzer{}.Analyze("This is confidential. Report for March.")}},
+		{FileName: "b.txt", FilePath: "b.txt", Results: []AnalysisResult{SentenceAnalyzer{}.Analyze("Report for April. This   is confidential.")}},
+		{FileName: "c.txt", FilePath: "c.txt", Results: []AnalysisResult{SentenceAnalyzer{}.Analyze("Unrelated text.")}},
+	}
+
+	dups := FindDuplicateSentences(results, 2)
+	want := map[string][]string{"This is confidential.": {"a.txt", "b.txt"}}
+	if !reflect.DeepEqual(dups, want) {
+		t.Errorf("expected %v, got %v", want, dups)
+	}
+}
+
+// Файлы с одинаковым именем в разных каталогах различаются, список файлов ограничен
+func TestFindDuplicateSentencesPathsLimited(t *testing.T) {
+	var results []FileAnalysisResult
+	for i := 0; i < maxListedFiles+5; i++ {
+		results = append(results, FileAnalysisResult{
+			FileName: "notes.txt",
+			FilePath: fmt.Sprintf("dir%03d/notes.txt", i),
+			Results:  []AnalysisResult{SentenceAnalyzer{}.Analyze("Shared boilerplate line.")},
+		})
+	}
+	files := FindDuplicateSentences(results, 2)["Shared boilerplate line."]
+	if len(files) != maxListedFiles+1 || files[0] != "dir000/notes.txt" || files[maxListedFiles] != "… ещё 5" {
+		t.Errorf("expected %d paths and a remainder marker, got %d: %v", maxListedFiles, len(files), files[maxListedFiles:])
+	}
+}
+
+func TestSentenceDiversityAnalyzer(t *testing.T) {
+	tests := []struct {
+		content string
+		want    float64
+	}{
+		{"Cats sleep. Dogs bark. Birds sing.", 1},
+		{"Call us now. Call us now.  Call   us now.\nCall us now.", 0.25},
+		{"One. Two. One. Two.", 0.5},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := (SentenceDiversityAnalyzer{}).Analyze(tt.content).Data.(float64); got != tt.want {
+			t.Errorf("diversity of %q: expected %v, got %v", tt.content, tt.want, got)
+		}
+	}
+}
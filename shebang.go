@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+)
+
+// Язык скрипта по строке shebang: "#!/usr/bin/env python3" -> "python".
+// Для строки без "#!" возвращает пустую строку.
+func detectShebang(firstLine string) string {
+	line, ok := strings.CutPrefix(strings.TrimRight(firstLine, "\r\n"), "#!")
+	if !ok {
+		return ""
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return ""
+	}
+	interp := path.Base(fields[0])
+	if interp == "env" {
+		// env -S python3 -u: пропускаем флаги env
+		interp = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interp = path.Base(f)
+				break
+			}
+		}
+	}
+	// python3.11 -> python, версии интерпретатора не различаем
+	return strings.TrimRight(interp, "0123456789.")
+}
+
+// Предел длины читаемой первой строки: shebang короче, а бинарный файл
+// без переводов строк иначе прочитался бы целиком
+const maxShebangLine = 512
+
+// Чтение первой строки файла, не длиннее maxShebangLine байт
+func readFirstLine(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReaderSize(f, maxShebangLine).ReadSlice('\n')
+	if err != nil && err != bufio.ErrBufferFull && err != io.EOF {
+		return "", err
+	}
+	return string(line), nil
+}
+
+// Отбор файлов, shebang которых указывает на язык lang.
+// Непрочитанные файлы не отбираются и возвращаются в failed. Именованные каналы
+// не отбираются: открытие без писателя зависает, а чтение заголовка съело бы поток
+func filterByLang(files []string, lang string) (selected []string, failed []FileError) {
+	for _, f := range files {
+		if isFIFOPath(f) {
+			slog.Warn("именованный канал не проверяется по -lang и пропущен", "path", f)
+			continue
+		}
+		line, err := readFirstLine(f)
+		if err != nil {
+			failed = append(failed, FileError{f, err})
+			continue
+		}
+		if detectShebang(line) == lang {
+			selected = append(selected, f)
+		}
+	}
+	return selected, failed
+}
+
+// Анализатор языка скрипта по shebang
+type ShebangAnalyzer struct{}
+
+func (s ShebangAnalyzer) Name() string {
+	return "script_language"
+}
+func (s ShebangAnalyzer) Analyze(content string) AnalysisResult {
+	line, _, _ := strings.Cut(content, "\n")
+	return AnalysisResult{
+		NameAnalyzer: s.Name(),
+		Data:         detectShebang(line),
+	}
+}
+
+// Количество файлов по языкам скриптов
+func countScriptLanguages(results []FileAnalysisResult) map[string]int {
+	counts := make(map[string]int)
+	for _, res := range results {
+		for _, r := range res.Results {
+			if lang, ok := r.Data.(string); ok && r.NameAnalyzer == "script_language" && lang != "" {
+				counts[lang]++
+			}
+		}
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	return counts
+}
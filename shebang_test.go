@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestDetectShebang(t *testing.T) {
+	tests := map[string]string{
+		"#!/usr/bin/env python3\n":       "python",
+		"#!/usr/bin/env -S python3 -u":   "python",
+		"#!/bin/bash":                    "bash",
+		"#!/usr/bin/perl -w":             "perl",
+		"#! /bin/sh\r\n":                 "sh",
+		"# just a comment":               "",
+		"echo hello":                     "",
+		"#!":                             "",
+		"#!/usr/local/bin/python3.11 -O": "python",
+	}
+	for line, want := range tests {
+		if got := detectShebang(line); got != want {
+			t.Errorf("detectShebang(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestFilterByLang(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"deploy":     "#!/usr/bin/env python3\nprint('deploy')\n",
+		"build.sh":   "#!/bin/bash\nmake\n",
+		"notes":      "# not a shebang\npython is mentioned\n",
+		"script.txt": "#!/usr/bin/python\nprint(1)\n",
+	})
+
+	all, _, err := dirTraversal(dir, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// исчезнувший файл не прерывает отбор, а возвращается как ошибка файла
+	gone := filepath.Join(dir, "gone")
+	selected, failed := filterByLang(append(all, gone), "python")
+	if len(selected) != 2 || filepath.Base(selected[0]) != "deploy" || filepath.Base(selected[1]) != "script.txt" {
+		t.Errorf("expected deploy and script.txt, got %v", selected)
+	}
+	if len(failed) != 1 || failed[0].Path != gone || !errors.Is(failed[0].Err, fs.ErrNotExist) {
+		t.Errorf("expected a read error for %s, got %v", gone, failed)
+	}
+}
+
+func TestReadFirstLineBounded(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"blob": "#!/bin/sh " + strings.Repeat("x", 10*maxShebangLine),
+	})
+	line, err := readFirstLine(filepath.Join(dir, "blob"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(line) != maxShebangLine || detectShebang(line) != "sh" {
+		t.Errorf("expected %d bytes detected as sh, got %d bytes", maxShebangLine, len(line))
+	}
+}
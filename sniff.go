@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Количество байт, по которым определяется тип содержимого
+const sniffLen = 512
+
+// Определение MIME типа по первым байтам содержимого
+func sniffType(header []byte) string {
+	if len(header) > sniffLen {
+		header = header[:sniffLen]
+	}
+	switch {
+	case bytes.HasPrefix(header, []byte("%PDF-")):
+		return "application/pdf"
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")):
+		return "application/zip"
+	case bytes.HasPrefix(header, []byte{0xFF, 0xFE}):
+		return "text/plain; charset=utf-16le"
+	case bytes.HasPrefix(header, []byte{0xFE, 0xFF}):
+		return "text/plain; charset=utf-16be"
+	}
+	return http.DetectContentType(header)
+}
+
+// MIME тип без параметров: "text/plain; charset=utf-8" -> "text/plain"
+func mediaType(t string) string {
+	if i := strings.IndexByte(t, ';'); i >= 0 {
+		t = t[:i]
+	}
+	return strings.TrimSpace(strings.ToLower(t))
+}
+
+// Анализатор типа содержимого
+type TypeAnalyzer struct{}
+
+func (t TypeAnalyzer) Name() string {
+	return "type"
+}
+func (t TypeAnalyzer) Analyze(content string) AnalysisResult {
+	n := min(len(content), sniffLen)
+	return AnalysisResult{
+		NameAnalyzer: t.Name(),
+		Data:         sniffType([]byte(content[:n])),
+	}
+}
+
+// Чтение первых байт файла для определения типа
+func sniffFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return sniffType(buf[:n]), nil
+}
+
+// Отбор файлов, тип содержимого которых входит в types (через запятую).
+// Читается только заголовок файла, до полного анализа. Исчезнувшие файлы
+// обрабатываются по -stale-policy и возвращаются в gone, непрочитанные - в failed.
+// Именованные каналы пропускаются, как в filterByLang
+func filterByType(files []string, types, policy string) (selected, gone []string, failed []FileError) {
+	allowed := make(map[string]bool)
+	for _, t := range strings.Split(types, ",") {
+		if t = mediaType(t); t != "" {
+			allowed[t] = true
+		}
+	}
+
+	for _, f := range files {
+		if isFIFOPath(f) {
+			slog.Warn("именованный канал не проверяется по -type и пропущен", "path", f)
+			continue
+		}
+		t, err := sniffFile(f)
+		if err != nil && policy == "reread" && errors.Is(err, fs.ErrNotExist) {
+			if _, serr := os.Stat(f); serr == nil {
+				t, err = sniffFile(f)
+			}
+		}
+		if err != nil && policy != "error" && vanished(f, err) {
+			gone = append(gone, f)
+			continue
+		}
+		if err != nil {
+			failed = append(failed, FileError{f, err})
+			continue
+		}
+		if allowed[mediaType(t)] {
+			selected = append(selected, f)
+		}
+	}
+	return selected, gone, failed
+}
+
+// Типы, с которыми несовместим общий text/plain
+var binaryTypePrefixes = []string{"image/", "audio/", "video/", "application/pdf", "application/zip", "application/octet-stream"}
+
+// Противоречит ли определённый тип содержимого расширению файла.
+// Неизвестные расширения ничему не противоречат.
+func typeMismatch(name, sniffed string) bool {
+	byExt := mediaType(mime.TypeByExtension(filepath.Ext(name)))
+	sniffed = mediaType(sniffed)
+	if byExt == "" || byExt == sniffed {
+		return false
+	}
+	// text/plain - самый общий результат, он не отличает markdown или json от текста
+	if sniffed == "text/plain" {
+		for _, p := range binaryTypePrefixes {
+			if strings.HasPrefix(byExt, p) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Файлы, у которых тип содержимого не совпадает с расширением
+func findTypeMismatches(results []FileAnalysisResult) []string {
+	var mismatched []string
+	for _, res := range results {
+		for _, r := range res.Results {
+			if t, ok := r.Data.(string); ok && r.NameAnalyzer == "type" && typeMismatch(res.FileName, t) {
+				mismatched = append(mismatched, res.FilePath+" ("+mediaType(t)+")")
+			}
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
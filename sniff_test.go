@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSniffFixtures(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"export.txt": []byte("<!DOCTYPE html><html><body><p>exported page</p></body></html>"),
+		"utf16.txt":  {0xFF, 0xFE, 'h', 0, 'i', 0, '\n', 0},
+		"deploy":     []byte("#!/bin/sh\necho deploying\n"),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestSniffFile(t *testing.T) {
+	dir := writeSniffFixtures(t)
+	want := map[string]string{
+		"export.txt": "text/html; charset=utf-8",
+		"utf16.txt":  "text/plain; charset=utf-16le",
+		"deploy":     "text/plain; charset=utf-8",
+	}
+	for name, typ := range want {
+		got, err := sniffFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != typ {
+			t.Errorf("%s: expected %q, got %q", name, typ, got)
+		}
+	}
+}
+
+func TestFilterByType(t *testing.T) {
+	dir := writeSniffFixtures(t)
+	files, _, err := dirTraversal(dir, "", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	selected, gone, failed := filterByType(files, "text/plain", "warn")
+	if len(gone) != 0 || len(failed) != 0 {
+		t.Fatalf("unexpected vanished %v or failed %v", gone, failed)
+	}
+	if len(selected) != 2 {
+		t.Fatalf("expected deploy and utf16.txt selected, got %v", selected)
+	}
+	for _, f := range selected {
+		if filepath.Base(f) == "export.txt" {
+			t.Errorf("html export should not be selected as text/plain")
+		}
+	}
+}
+
+// Исчезнувший или нечитаемый файл не прерывает отбор
+func TestFilterByTypeFailures(t *testing.T) {
+	dir := writeSniffFixtures(t)
+	missing := filepath.Join(dir, "missing.txt")
+	files := []string{filepath.Join(dir, "utf16.txt"), missing, dir}
+
+	selected, gone, failed := filterByType(files, "text/plain", "warn")
+	if len(selected) != 1 || len(gone) != 1 || gone[0] != missing {
+		t.Errorf("expected utf16.txt selected and missing.txt vanished, got %v and %v", selected, gone)
+	}
+	if len(failed) != 1 || failed[0].Path != dir {
+		t.Errorf("expected the directory to fail, got %v", failed)
+	}
+
+	_, gone, failed = filterByType(files, "text/plain", "error")
+	if len(gone) != 0 || len(failed) != 2 {
+		t.Errorf("with -stale-policy error a vanished file is an error, got vanished %v, failed %v", gone, failed)
+	}
+}
+
+func TestFindTypeMismatches(t *testing.T) {
+	dir := writeSniffFixtures(t)
+	var results []FileAnalysisResult
+	for _, name := range []string{"export.txt", "utf16.txt", "deploy"} {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, FileAnalysisResult{
+			FileName: name,
+			FilePath: name,
+			Results:  []AnalysisResult{TypeAnalyzer{}.Analyze(string(content))},
+		})
+	}
+
+	mismatches := findTypeMismatches(results)
+	if len(mismatches) != 1 || mismatches[0] != "export.txt (text/html)" {
+		t.Errorf("expected only export.txt flagged, got %v", mismatches)
+	}
+}
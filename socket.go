@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Запрос клиента Unix-сокета
+type SocketRequest struct {
+	Path    string `json:"path"`
+	Ext     string `json:"ext"`
+	Workers int    `json:"workers"`
+}
+
+// Ответ с ошибкой вместо массива результатов
+type SocketError struct {
+	Error string `json:"error"`
+}
+
+// Сервер анализа на Unix-сокете, работает до отмены ctx
+func serveSocket(ctx context.Context, path string) error {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	return serveListener(ctx, ln)
+}
+
+func serveListener(ctx context.Context, ln net.Listener) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			handleSocketConn(ctx, conn)
+		}()
+	}
+}
+
+// Обработка запросов одного клиента, пока он не закроет соединение.
+// При отмене ctx ожидание запроса прерывается, а идущий анализ останавливается
+// и отвечает клиенту ошибкой отмены, чтобы остановка сервера не ждала клиентов
+func handleSocketConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	dec := json.NewDecoder(conn)
+	enc := json.NewEncoder(conn)
+	for {
+		var req SocketRequest
+		if err := dec.Decode(&req); err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				enc.Encode(SocketError{err.Error()})
+			}
+			return
+		}
+
+		results, err := analyzeRequest(ctx, req)
+		if err != nil {
+			err = enc.Encode(SocketError{err.Error()})
+		} else {
+			err = enc.Encode(results)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func analyzeRequest(ctx context.Context, req SocketRequest) ([]FileAnalysisResult, error) {
+	files, req, err := requestFiles(req)
+	if err != nil {
+		return nil, err
+	}
+	results := []FileAnalysisResult{}
+	err = AnalyzeParallelContext(ctx, files, defaultAnalyzers(Options{}), req.Workers, func(r FileAnalysisResult) {
+		results = append(results, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Проверка запроса, значения по умолчанию и список файлов для анализа
+func requestFiles(req SocketRequest) ([]string, SocketRequest, error) {
+	if req.Path == "" {
+		return nil, req, errors.New("необходимо ввести путь")
+	}
+	if req.Ext == "" {
+		req.Ext = ".txt"
+	}
+	if req.Workers <= 0 {
+		req.Workers = runtime.NumCPU()
+	}
+
+	files, _, err := dirTraversal(req.Path, req.Ext, 0, 0)
+	return files, req, err
+}
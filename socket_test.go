@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"stage5/internal/testutil"
+	"testing"
+	"time"
+)
+
+func TestSocketRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello world\nhello go"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sock := filepath.Join(t.TempDir(), "analyzer.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveSocket(ctx, sock) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}()
+
+	var conn net.Conn
+	var err error
+	for i := 0; i < 50; i++ {
+		if conn, err = net.Dial("unix", sock); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(SocketRequest{Path: dir, Ext: ".txt", Workers: 2}); err != nil {
+		t.Fatal(err)
+	}
+	var results []FileAnalysisResult
+	if err := json.NewDecoder(conn).Decode(&results); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || results[0].FileName != "a.txt" {
+		t.Fatalf("expected result for a.txt, got %+v", results)
+	}
+	for _, r := range results[0].Results {
+		if r.NameAnalyzer == "word_count" && r.Data.(int) != 4 {
+			t.Errorf("expected 4 words, got %v", r.Data)
+		}
+	}
+}
+
+func TestSocketShutdownWithOpenClient(t *testing.T) {
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "analyzer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveListener(ctx, ln) }()
+
+	conn, err := net.Dial("unix", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// клиент подключён и молчит, сервер ждёт его запроса
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop while a client was connected")
+	}
+}
+
+func TestAnalyzeRequestCancelled(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": "one two", "b.txt": "three four"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := analyzeRequest(ctx, SocketRequest{Path: dir, Workers: 1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
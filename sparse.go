@@ -0,0 +1,36 @@
+package main
+
+import "reflect"
+
+// Нулевой ли результат для -sparse: целое 0 или пустая карта
+func zeroResult(r AnalysisResult) bool {
+	switch v := r.Data.(type) {
+	case int:
+		return v == 0
+	case nil:
+		return false
+	}
+	rv := reflect.ValueOf(r.Data)
+	return rv.Kind() == reflect.Map && rv.Len() == 0
+}
+
+// Результаты файлов без нулевых значений. Срезы копируются,
+// исходные результаты делятся с memo и сводкой
+func sparseFiles(files []FileAnalysisResult) []FileAnalysisResult {
+	out := make([]FileAnalysisResult, len(files))
+	for i, f := range files {
+		out[i] = sparseFile(f)
+	}
+	return out
+}
+
+func sparseFile(f FileAnalysisResult) FileAnalysisResult {
+	results := make([]AnalysisResult, 0, len(f.Results))
+	for _, r := range f.Results {
+		if !zeroResult(r) {
+			results = append(results, r)
+		}
+	}
+	f.Results = results
+	return f
+}
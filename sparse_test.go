@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestZeroResult(t *testing.T) {
+	tests := []struct {
+		data any
+		zero bool
+	}{
+		{0, true},
+		{3, false},
+		{map[string]int{}, true},
+		{map[string][]int{}, true},
+		{map[string]int{"a": 1}, false},
+		{0.0, false},
+		{"", false},
+		{false, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := zeroResult(AnalysisResult{Data: tt.data}); got != tt.zero {
+			t.Errorf("zeroResult(%#v) = %v, want %v", tt.data, got, tt.zero)
+		}
+	}
+}
+
+func TestRunSparseOutput(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("plain lowercase words only"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	names := func(sparse bool) map[string]bool {
+		t.Helper()
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", SparseOutput: sparse}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var report Report
+		if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]bool)
+		for _, r := range report.Files[0].Results {
+			got[r.NameAnalyzer] = true
+		}
+		return got
+	}
+	full, sparse := names(false), names(true)
+	if !full["terms"] {
+		t.Fatalf("expected empty terms result without -sparse, got %v", full)
+	}
+	if sparse["terms"] {
+		t.Errorf("empty terms result should be omitted with -sparse, got %v", sparse)
+	}
+	if !sparse["word_count"] || !sparse["line_count"] {
+		t.Errorf("non-zero results should be kept with -sparse, got %v", sparse)
+	}
+}
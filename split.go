@@ -0,0 +1,95 @@
+package main
+
+import "sync"
+
+// Общий пул горутин для частей больших файлов -split-large-files.
+// Воркер, взявший большой файл, раздаёт его части пулу и ждёт,
+// поэтому один файл загружает все workers, а не одну горутину
+type chunkPool struct {
+	tasks chan func()
+	wg    sync.WaitGroup
+}
+
+func newChunkPool(workers int) *chunkPool {
+	p := &chunkPool{tasks: make(chan func())}
+	for i := 0; i < max(workers, 1); i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+func (p *chunkPool) close() {
+	close(p.tasks)
+	p.wg.Wait()
+}
+
+// Анализ файла по частям в пуле: MergeableAnalyzer считают каждую часть
+// с общими артефактами части, результаты объединяются; остальные анализаторы
+// работают над всем содержимым последовательно в текущей горутине.
+// Части режутся по splitChunks, строка не попадает в две части
+func analyzeSplit(content string, analyzers []Analyzer, chunkSize int, pool *chunkPool) []AnalysisResult {
+	chunks := splitChunks(content, chunkSize)
+	var mergeable []MergeableAnalyzer
+	var rest []Analyzer
+	var restIdx, mergeIdx []int
+	for i, a := range analyzers {
+		if m, ok := a.(MergeableAnalyzer); ok && len(chunks) > 1 {
+			mergeable = append(mergeable, m)
+			mergeIdx = append(mergeIdx, i)
+		} else {
+			rest = append(rest, a)
+			restIdx = append(restIdx, i)
+		}
+	}
+
+	// parts[j][k] - данные анализатора mergeable[k] для части j
+	parts := make([][]any, len(chunks))
+	var wg sync.WaitGroup
+	if len(mergeable) > 0 {
+		for j, chunk := range chunks {
+			wg.Add(1)
+			pool.tasks <- func() {
+				defer wg.Done()
+				as := make([]Analyzer, len(mergeable))
+				for k, m := range mergeable {
+					as[k] = cloneAnalyzer(m)
+				}
+				art := buildArtifacts(chunk, as)
+				parts[j] = make([]any, len(as))
+				for k, a := range as {
+					parts[j][k] = runAnalyzerWith(a, chunk, art).Data
+				}
+			}
+		}
+	}
+
+	results := make([]AnalysisResult, len(analyzers))
+	for i, r := range analyzeContentSequential(content, rest) {
+		results[restIdx[i]] = r
+	}
+	wg.Wait()
+	for k, m := range mergeable {
+		data := make([]any, len(chunks))
+		for j := range chunks {
+			data[j] = parts[j][k]
+		}
+		results[mergeIdx[k]] = AnalysisResult{NameAnalyzer: m.Name(), Data: m.MergeChunks(data), Confidence: 1}
+	}
+	return results
+}
+
+// Функция анализа, отправляющая файлы больше threshold байт в analyzeSplit
+func splittingAnalyzer(analyze func(string, []Analyzer) []AnalysisResult, threshold int, pool *chunkPool) func(string, []Analyzer) []AnalysisResult {
+	return func(content string, analyzers []Analyzer) []AnalysisResult {
+		if len(content) > threshold {
+			return analyzeSplit(content, analyzers, threshold, pool)
+		}
+		return analyze(content, analyzers)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Синтетический лог примерно size байт с разными словами в строках
+func syntheticLog(size int) string {
+	var b strings.Builder
+	b.Grow(size + 100)
+	for i := 0; b.Len() < size; i++ {
+		fmt.Fprintf(&b, "2024-01-%02d INFO worker%d handled request id%d in %dms\n", i%28+1, i%16, i%5000, i%997)
+	}
+	return b.String()
+}
+
+func TestAnalyzeSplitMatchesSinglePass(t *testing.T) {
+	size := 100 << 20
+	if testing.Short() {
+		size = 4 << 20
+	}
+	content := syntheticLog(size) + "last line without newline"
+	analyzers := []Analyzer{
+		WordCountAnalyzer{},
+		LineCountAnalyzer{},
+		MostFrequentWordsAnalyzer{},
+		DensityAnalyzer{},
+	}
+
+	pool := newChunkPool(8)
+	defer pool.close()
+	single := analyzeContentSequential(content, analyzers)
+	split := analyzeSplit(content, analyzers, 1<<20, pool)
+	if !reflect.DeepEqual(single, split) {
+		t.Errorf("split results differ from single pass")
+	}
+}
+
+func TestRunSplitLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	content := syntheticLog(64 << 10)
+	if err := os.WriteFile(filepath.Join(dir, "big.log"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := func(split int) Report {
+		t.Helper()
+		var out bytes.Buffer
+		opts := Options{Path: dir, Ext: ".log", Workers: 4, Format: "json", SplitLargeFiles: split}
+		if err := run(context.Background(), opts, &out); err != nil {
+			t.Fatal(err)
+		}
+		var r Report
+		if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+	whole, split := report(0), report(4096)
+	if !reflect.DeepEqual(whole.Files[0].Results, split.Files[0].Results) || whole.Summary.TotalWords != split.Summary.TotalWords {
+		t.Errorf("-split-large-files changed results")
+	}
+}
+
+func BenchmarkSplitLargeFile(b *testing.B) {
+	content := syntheticLog(32 << 20)
+	analyzers := []Analyzer{WordCountAnalyzer{}, LineCountAnalyzer{}, MostFrequentWordsAnalyzer{}}
+	b.Run("single", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			analyzeContentSequential(content, analyzers)
+		}
+	})
+	b.Run("split-8-workers", func(b *testing.B) {
+		pool := newChunkPool(8)
+		defer pool.close()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			analyzeSplit(content, analyzers, 1<<20, pool)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Источник содержимого файлов, подменяется в тестах
+var readSource = readFile
+
+// Проверка значения -stale-policy
+func validStalePolicy(policy string) error {
+	switch policy {
+	case "", "error", "warn", "reread":
+		return nil
+	}
+	return fmt.Errorf("неизвестное значение -stale-policy %q, допустимо: error, warn, reread", policy)
+}
+
+// Файл исчез между обходом каталога и чтением: его больше нет даже как записи в каталоге.
+// Битая символическая ссылка сюда не относится и остаётся ошибкой
+func vanished(path string, err error) bool {
+	if !errors.Is(err, fs.ErrNotExist) {
+		return false
+	}
+	_, lerr := os.Lstat(path)
+	return errors.Is(lerr, fs.ErrNotExist)
+}
+
+// Анализ файла с учётом -stale-policy.
+// gone=true означает, что файл исчез во время работы и не считается ошибкой
+func analyzeFileStale(path string, read func(string) (fileContent, error), analyzers []Analyzer, memo *contentMemo, analyze func(string, []Analyzer) []AnalysisResult, policy string) (result FileAnalysisResult, gone bool, err error) {
+	result, err = analyzeFileWith(path, read, analyzers, memo, analyze)
+	if err == nil || policy == "error" || !errors.Is(err, fs.ErrNotExist) {
+		return result, false, err
+	}
+	// при ротации файл может быть создан заново под тем же именем
+	if policy == "reread" {
+		if _, serr := os.Stat(path); serr == nil {
+			result, err = analyzeFileWith(path, read, analyzers, memo, analyze)
+		}
+	}
+	if err != nil && vanished(path, err) {
+		return result, true, nil
+	}
+	return result, false, err
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Запуск, в котором файл gone.txt удаляется после обхода, перед чтением.
+// recreate воссоздаёт его сразу после неудачного чтения, как при ротации логов
+func runWithVanishingFile(t *testing.T, policy string, recreate bool) (SummaryReport, string) {
+	t.Helper()
+	dir := t.TempDir()
+	gone := filepath.Join(dir, "gone.txt")
+	for _, path := range []string{filepath.Join(dir, "kept.txt"), gone} {
+		if err := os.WriteFile(path, []byte("hello world"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed := false
+	readSource = func(path string) (fileContent, error) {
+		if path != gone || removed {
+			return readFile(path)
+		}
+		removed = true
+		if err := os.Remove(path); err != nil {
+			t.Error(err)
+		}
+		fc, err := readFile(path)
+		if recreate {
+			if err := os.WriteFile(path, []byte("rotated log file"), 0o644); err != nil {
+				t.Error(err)
+			}
+		}
+		return fc, err
+	}
+	t.Cleanup(func() { readSource = readFile })
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", StalePolicy: policy}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	return report.Summary, gone
+}
+
+func TestStalePolicyWarn(t *testing.T) {
+	for _, policy := range []string{"", "warn"} {
+		summary, gone := runWithVanishingFile(t, policy, false)
+		if !reflect.DeepEqual(summary.VanishedFiles, []string{gone}) || len(summary.FailedFiles) != 0 {
+			t.Errorf("policy %q: expected %s as vanished, got vanished=%v failed=%v", policy, gone, summary.VanishedFiles, summary.FailedFiles)
+		}
+		if summary.Files != 1 {
+			t.Errorf("policy %q: expected 1 analyzed file, got %d", policy, summary.Files)
+		}
+	}
+}
+
+func TestStalePolicyError(t *testing.T) {
+	summary, gone := runWithVanishingFile(t, "error", false)
+	if !reflect.DeepEqual(summary.FailedFiles, []string{gone}) || len(summary.VanishedFiles) != 0 {
+		t.Errorf("expected %s as failed, got vanished=%v failed=%v", gone, summary.VanishedFiles, summary.FailedFiles)
+	}
+}
+
+func TestStalePolicyReread(t *testing.T) {
+	summary, _ := runWithVanishingFile(t, "reread", true)
+	if summary.Files != 2 || len(summary.VanishedFiles) != 0 || len(summary.FailedFiles) != 0 {
+		t.Errorf("expected recreated file to be analyzed, got %+v", summary)
+	}
+
+	summary, gone := runWithVanishingFile(t, "reread", false)
+	if !reflect.DeepEqual(summary.VanishedFiles, []string{gone}) {
+		t.Errorf("expected %s as vanished after reread, got %v", gone, summary.VanishedFiles)
+	}
+}
+
+func TestStalePolicyInvalid(t *testing.T) {
+	err := run(context.Background(), Options{Path: t.TempDir(), StalePolicy: "ignore"}, &bytes.Buffer{})
+	if err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
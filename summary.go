@@ -0,0 +1,45 @@
+package main
+
+import "unicode/utf8"
+
+// Слова короче не влияют на оценку предложений: грубая замена списку стоп-слов
+const minSummaryWordLen = 3
+
+// Анализатор краткого содержания: предложение с наибольшим средним весом слов,
+// вес слова - его частота в файле. При равной оценке берётся более раннее предложение
+type SummaryAnalyzer struct{}
+
+func (s SummaryAnalyzer) Name() string {
+	return "summary"
+}
+func (s SummaryAnalyzer) Analyze(content string) AnalysisResult {
+	sentences := splitSentences(content)
+	words := make([][]string, len(sentences))
+	freq := make(map[string]int)
+	for i, sentence := range sentences {
+		for _, w := range paragraphWords(sentence) {
+			if utf8.RuneCountInString(w) >= minSummaryWordLen {
+				words[i] = append(words[i], w)
+				freq[w]++
+			}
+		}
+	}
+
+	best, bestScore := "", 0.0
+	for i, sentence := range sentences {
+		if len(words[i]) == 0 {
+			continue
+		}
+		total := 0
+		for _, w := range words[i] {
+			total += freq[w]
+		}
+		if score := float64(total) / float64(len(words[i])); score > bestScore {
+			best, bestScore = sentence, score
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: s.Name(),
+		Data:         best,
+	}
+}
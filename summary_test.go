@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSummaryAnalyzer(t *testing.T) {
+	content := "We went to the market on Monday. " +
+		"Goroutines and channels make concurrency in Go simple. " +
+		"Channels connect goroutines, and goroutines communicate over channels. " +
+		"The weather was nice."
+	got := runAnalyzer(SummaryAnalyzer{}, content).Data.(string)
+	if want := "Channels connect goroutines, and goroutines communicate over channels."; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := runAnalyzer(SummaryAnalyzer{}, "a b. c d").Data.(string); got != "" {
+		t.Errorf("expected empty summary without scorable words, got %q", got)
+	}
+}
+
+func TestRunSummaryFlag(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("Cats chase mice. Mice fear cats and cats hunt mice."), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "summary:") {
+		t.Errorf("summary should be off by default, got:\n%s", out.String())
+	}
+	out.Reset()
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, Summary: true}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), ` summary: "Cats chase mice."`) {
+		t.Errorf("expected summary line, got:\n%s", out.String())
+	}
+}
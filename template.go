@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Данные, доступные в шаблоне -template
+type TemplateData struct {
+	Files   []FileAnalysisResult
+	Summary SummaryReport
+}
+
+// Разбор шаблона из строки или из файла, если строка начинается с @
+func parseOutputTemplate(s string, globalMap map[string]int) (*template.Template, error) {
+	if name, ok := strings.CutPrefix(s, "@"); ok {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		s = string(data)
+	}
+
+	funcs := template.FuncMap{
+		// N самых частых слов по всем файлам
+		"topwords": func(n int) []WordCount {
+			return topWords(globalMap, n)
+		},
+		// результат анализатора по имени: {{result . "word_count"}}
+		"result": func(f FileAnalysisResult, name string) any {
+			for _, r := range f.Results {
+				if r.NameAnalyzer == name {
+					return r.Data
+				}
+			}
+			return nil
+		},
+	}
+	t, err := template.New("output").Funcs(funcs).Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка разбора шаблона %w", err)
+	}
+	return t, nil
+}
+
+func writeTemplate(out io.Writer, t *template.Template, data TemplateData) error {
+	if err := t.Execute(out, data); err != nil {
+		return fmt.Errorf("ошибка выполнения шаблона %w", err)
+	}
+	return nil
+}
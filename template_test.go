@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunTemplate(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("go go gopher\nrun"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := `{{range .Files}}{{.FileName}}={{result . "word_count"}}{{end}}; total {{.Summary.TotalWords}}; ` +
+		`{{range topwords 1}}{{printf "%s:%d" .Word .Count}}{{end}}`
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Template: tmpl}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "a.txt=4; total 4; go:2"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
+
+func TestParseOutputTemplateFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte("{{len .Files}} files"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := parseOutputTemplate("@"+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := writeTemplate(&out, tmpl, TemplateData{Files: make([]FileAnalysisResult, 2)}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "2 files" {
+		t.Errorf("expected %q, got %q", "2 files", out.String())
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Слова, которые не считаются терминами, даже если написаны капсом
+var DefaultTermExclusions = []string{
+	"THE", "AND", "OR", "BUT", "NOT", "FOR", "YOU", "ARE", "IS", "IT",
+	"OF", "TO", "IN", "ON", "AT", "BY", "WE", "NO", "YES", "OK",
+}
+
+// Анализатор терминов: аббревиатуры (API, SLA) и CamelCase идентификаторы.
+// Нулевые MinLen/MaxLen означают 2 и 6, nil Exclude означает DefaultTermExclusions.
+type TermExtractorAnalyzer struct {
+	MinLen  int
+	MaxLen  int
+	Exclude []string
+}
+
+func (t TermExtractorAnalyzer) Name() string {
+	return "terms"
+}
+func (t TermExtractorAnalyzer) Analyze(content string) AnalysisResult {
+	return t.AnalyzeWithArtifacts(content, &Artifacts{Tokens: strings.Fields(content)})
+}
+func (t TermExtractorAnalyzer) Artifacts() []string {
+	return []string{ArtifactTokens}
+}
+func (t TermExtractorAnalyzer) AnalyzeWithArtifacts(content string, art *Artifacts) AnalysisResult {
+	minLen, maxLen := t.MinLen, t.MaxLen
+	if minLen == 0 {
+		minLen = 2
+	}
+	if maxLen == 0 {
+		maxLen = 6
+	}
+	exclude := t.Exclude
+	if exclude == nil {
+		exclude = DefaultTermExclusions
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		excluded[strings.ToUpper(e)] = true
+	}
+
+	terms := make(map[string]int)
+	for _, word := range art.Tokens {
+		term := cleanTerm(word)
+		if term == "" || excluded[strings.ToUpper(term)] {
+			continue
+		}
+		n := utf8.RuneCountInString(term)
+		if (isAcronym(term) && n >= minLen && n <= maxLen) || isCamelCase(term) {
+			terms[term]++
+		}
+	}
+	return AnalysisResult{
+		NameAnalyzer: t.Name(),
+		Data:         terms,
+	}
+}
+
+// Удаление пунктуации по краям и притяжательного 's
+func cleanTerm(word string) string {
+	notWord := func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }
+	word = strings.TrimFunc(word, notWord)
+	for _, suffix := range []string{"'s", "’s"} {
+		word = strings.TrimSuffix(word, suffix)
+	}
+	return strings.TrimFunc(word, notWord)
+}
+
+func isAcronym(word string) bool {
+	for i, r := range word {
+		if i == 0 && !unicode.IsLetter(r) {
+			return false
+		}
+		if !unicode.IsUpper(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// CamelCase: только буквы и цифры, есть строчные и хотя бы один переход
+// со строчной на заглавную (JavaScript, FileAnalysisResult, iPhone)
+func isCamelCase(word string) bool {
+	hump := false
+	var prev rune
+	for _, r := range word {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+		if unicode.IsLower(prev) && unicode.IsUpper(r) {
+			hump = true
+		}
+		prev = r
+	}
+	return hump
+}
+
+// Глобальная агрегация терминов: термин в верхнем регистре -> вариант написания -> количество
+type TermAggregator map[string]map[string]int
+
+func (ta TermAggregator) Add(terms map[string]int) {
+	for term, count := range terms {
+		key := strings.ToUpper(term)
+		if ta[key] == nil {
+			ta[key] = make(map[string]int)
+		}
+		ta[key][term] += count
+	}
+}
+
+type TermCount struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// N самых частых терминов, каждый в самом частом варианте написания
+func (ta TermAggregator) Top(n int) []TermCount {
+	var terms []TermCount
+	for _, variants := range ta {
+		var best string
+		total := 0
+		for v, c := range variants {
+			total += c
+			if best == "" || c > variants[best] || (c == variants[best] && v < best) {
+				best = v
+			}
+		}
+		terms = append(terms, TermCount{best, total})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	if n < len(terms) {
+		terms = terms[:n]
+	}
+	return terms
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestTermExtractorAnalyzer(t *testing.T) {
+	content := "API calls go through the gateway (SLA applies). The API's latency matters.\n" +
+		"JavaScript clients use FileAnalysisResult. THE END is near, ok."
+
+	terms := TermExtractorAnalyzer{}.Analyze(content).Data.(map[string]int)
+
+	want := map[string]int{
+		"API":                2,
+		"SLA":                1,
+		"JavaScript":         1,
+		"FileAnalysisResult": 1,
+		"END":                1,
+	}
+	if len(terms) != len(want) {
+		t.Errorf("expected %v, got %v", want, terms)
+	}
+	for term, count := range want {
+		if terms[term] != count {
+			t.Errorf("expected %s=%d, got %d", term, count, terms[term])
+		}
+	}
+}
+
+func TestTermExtractorAnalyzerLength(t *testing.T) {
+	a := TermExtractorAnalyzer{MinLen: 3, MaxLen: 4, Exclude: []string{}}
+	terms := a.Analyze("GO API HTTP HTTPS THE").Data.(map[string]int)
+
+	for _, term := range []string{"API", "HTTP", "THE"} {
+		if terms[term] != 1 {
+			t.Errorf("expected %s counted", term)
+		}
+	}
+	for _, term := range []string{"GO", "HTTPS"} {
+		if _, ok := terms[term]; ok {
+			t.Errorf("expected %s skipped by length", term)
+		}
+	}
+}
+
+func TestTermAggregatorTop(t *testing.T) {
+	agg := make(TermAggregator)
+	agg.Add(map[string]int{"GitHub": 3, "SLA": 1})
+	agg.Add(map[string]int{"GITHUB": 1, "Github": 1, "SLA": 1})
+
+	top := agg.Top(1)
+	if len(top) != 1 || top[0].Term != "GitHub" || top[0].Count != 5 {
+		t.Errorf("expected GitHub=5, got %v", top)
+	}
+}
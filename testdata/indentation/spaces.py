@@ -0,0 +1,7 @@
+def total(items):
+    result = sum(item.price
+                 for item in items)
+    if result > 100:
+        return result \
+               - 10
+    return result
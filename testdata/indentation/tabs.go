@@ -0,0 +1,8 @@
+package main
+
+func main() {
+	for i := 0; i < 3; i++ {
+		println(i,
+			i*2)
+	}
+}
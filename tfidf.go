@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Слово файла с оценкой TF-IDF
+type TermScore struct {
+	Term  string  `json:"term"`
+	Score float64 `json:"score"`
+}
+
+// Слова файла с наибольшей оценкой TF-IDF
+type FileTFIDF struct {
+	File  string      `json:"file"`
+	Terms []TermScore `json:"terms"`
+}
+
+// TF-IDF по корпусу в два прохода: сначала документная частота по всем файлам,
+// затем оценки каждого файла. TF - доля слова среди слов файла, IDF - ln(N/df),
+// поэтому слово из всех файлов получает 0 и в результат не попадает
+func topTFIDF(results []FileAnalysisResult, n int) []FileTFIDF {
+	df := make(DocumentFrequency)
+	files := 0
+	for _, res := range results {
+		if freq := frequencyMap(res); freq != nil {
+			df.Add(freq)
+			files++
+		}
+	}
+
+	var out []FileTFIDF
+	for _, res := range results {
+		freq := frequencyMap(res)
+		if freq == nil {
+			continue
+		}
+		total := 0
+		for _, c := range freq {
+			total += c
+		}
+		var terms []TermScore
+		for w, c := range freq {
+			score := float64(c) / float64(total) * math.Log(float64(files)/float64(df[w]))
+			if score > 0 {
+				terms = append(terms, TermScore{w, score})
+			}
+		}
+		sort.Slice(terms, func(i, j int) bool {
+			if terms[i].Score != terms[j].Score {
+				return terms[i].Score > terms[j].Score
+			}
+			return terms[i].Term < terms[j].Term
+		})
+		if n < len(terms) {
+			terms = terms[:n]
+		}
+		out = append(out, FileTFIDF{res.FilePath, terms})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].File < out[j].File })
+	return out
+}
+
+func writeTFIDFText(out io.Writer, c colorizer, files []FileTFIDF) {
+	fmt.Fprintln(out, "TF-IDF:")
+	for _, f := range files {
+		parts := make([]string, len(f.Terms))
+		for i, t := range f.Terms {
+			parts[i] = fmt.Sprintf("%s (%.3f)", t.Term, t.Score)
+		}
+		fmt.Fprintf(out, " %s: %s\n", c.name(f.File), strings.Join(parts, ", "))
+	}
+	fmt.Fprintln(out)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestTopTFIDF(t *testing.T) {
+	results := []FileAnalysisResult{
+		{FilePath: "b.txt", Results: []AnalysisResult{{NameAnalyzer: "most_frequent_words", Data: map[string]int{"the": 5, "cat": 1}}}},
+		{FilePath: "a.txt", Results: []AnalysisResult{{NameAnalyzer: "most_frequent_words", Data: map[string]int{"the": 4, "dog": 2, "cat": 1}}}},
+		{FilePath: "c.txt", Results: []AnalysisResult{{NameAnalyzer: "most_frequent_words", Data: map[string]int{"the": 1, "fish": 1}}}},
+		{FilePath: "dir", IsDir: true},
+	}
+	got := topTFIDF(results, 5)
+	if len(got) != 3 || got[0].File != "a.txt" {
+		t.Fatalf("expected 3 files sorted by path, got %v", got)
+	}
+	terms := got[0].Terms
+	if len(terms) != 2 || terms[0].Term != "dog" || terms[1].Term != "cat" {
+		t.Errorf("expected unique \"dog\" to outrank shared \"cat\", got %v", terms)
+	}
+	for _, f := range got {
+		for _, term := range f.Terms {
+			if term.Term == "the" {
+				t.Errorf("term present in every file must score 0, got %v in %s", term, f.File)
+			}
+		}
+	}
+}
+
+func TestRunTFIDF(t *testing.T) {
+	dir := testutil.CreateTempDir(t, map[string]string{
+		"a.txt": "common common rare",
+		"b.txt": "common common other",
+		"c.txt": "common words only here",
+	})
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, Format: "json", TFIDF: 1}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range report.Summary.TFIDF {
+		if strings.HasSuffix(f.File, "a.txt") {
+			if len(f.Terms) != 1 || f.Terms[0].Term != "rare" {
+				t.Errorf("expected \"rare\" as the top term of a.txt, got %v", f.Terms)
+			}
+			return
+		}
+	}
+	t.Errorf("a.txt missing from %v", report.Summary.TFIDF)
+}
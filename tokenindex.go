@@ -0,0 +1,22 @@
+package main
+
+import "strings"
+
+// Анализатор позиций слов: слово в нижнем регистре -> номера слов, где оно встречается.
+// Для больших файлов результат объёмный, поэтому включается флагом -token-index.
+type TokenIndexAnalyzer struct{}
+
+func (t TokenIndexAnalyzer) Name() string {
+	return "token_index"
+}
+func (t TokenIndexAnalyzer) Analyze(content string) AnalysisResult {
+	index := make(map[string][]int)
+	for i, word := range strings.Fields(content) {
+		word = strings.ToLower(word)
+		index[word] = append(index[word], i)
+	}
+	return AnalysisResult{
+		NameAnalyzer: t.Name(),
+		Data:         index,
+	}
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenIndexAnalyzer(t *testing.T) {
+	index := TokenIndexAnalyzer{}.Analyze("a b A").Data.(map[string][]int)
+
+	want := map[string][]int{"a": {0, 2}, "b": {1}}
+	if !reflect.DeepEqual(index, want) {
+		t.Errorf("expected %v, got %v", want, index)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Частота отслеживаемых слов по периодам времени изменения файлов
+type TrendReport struct {
+	Bucket  string           `json:"bucket"`
+	Periods []string         `json:"periods"`
+	Words   []string         `json:"words"`
+	Counts  map[string][]int `json:"counts"`
+}
+
+// Ключ периода для времени t: day - 2006-01-02, week - 2006-W01, month - 2006-01
+func trendPeriod(t time.Time, bucket string) (string, error) {
+	switch bucket {
+	case "day":
+		return t.Format("2006-01-02"), nil
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week), nil
+	case "month":
+		return t.Format("2006-01"), nil
+	}
+	return "", fmt.Errorf("неизвестный период тренда %q", bucket)
+}
+
+// Построение тренда слов words по результатам файлов
+func computeTrend(results []FileAnalysisResult, words []string, bucket string) (TrendReport, error) {
+	report := TrendReport{Bucket: bucket, Words: words, Counts: make(map[string][]int)}
+
+	perPeriod := make(map[string]map[string]int)
+	for _, res := range results {
+		period, err := trendPeriod(res.ModTime, bucket)
+		if err != nil {
+			return report, err
+		}
+		if perPeriod[period] == nil {
+			perPeriod[period] = make(map[string]int)
+		}
+		for _, r := range res.Results {
+			if freq, ok := r.Data.(map[string]int); ok && r.NameAnalyzer == "most_frequent_words" {
+				for _, w := range words {
+					perPeriod[period][w] += freq[w]
+				}
+			}
+		}
+	}
+
+	for period := range perPeriod {
+		report.Periods = append(report.Periods, period)
+	}
+	sort.Strings(report.Periods)
+	for _, w := range words {
+		counts := make([]int, len(report.Periods))
+		for i, period := range report.Periods {
+			counts[i] = perPeriod[period][w]
+		}
+		report.Counts[w] = counts
+	}
+	return report, nil
+}
+
+// Разбор списка слов -trend, слова приводятся к нижнему регистру как в частотном анализе
+func parseTrendWords(s string) []string {
+	var words []string
+	for _, w := range strings.Split(s, ",") {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// Печать тренда таблицей: строки - слова, столбцы - периоды
+func writeTrendText(out io.Writer, t TrendReport) {
+	width := 0
+	for _, w := range t.Words {
+		width = max(width, len([]rune(w)))
+	}
+	fmt.Fprintf(out, "Тренд (%s):\n", t.Bucket)
+	fmt.Fprintf(out, "%-*s", width, "")
+	for _, p := range t.Periods {
+		fmt.Fprintf(out, " %10s", p)
+	}
+	fmt.Fprintln(out)
+	for _, w := range t.Words {
+		fmt.Fprintf(out, "%-*s", width, w)
+		for _, c := range t.Counts[w] {
+			fmt.Fprintf(out, " %10d", c)
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out)
+}
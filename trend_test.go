@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTrendFixtures(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	fixtures := []struct {
+		name    string
+		content string
+		mtime   time.Time
+	}{
+		{"jan1.txt", "deploy deploy rollback", time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{"jan2.txt", "Deploy incident", time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC)},
+		{"feb.txt", "rollback rollback rollback", time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)},
+		{"mar.txt", "deploy incident incident", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, f := range fixtures {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.mtime, f.mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestComputeTrend(t *testing.T) {
+	dir := writeTrendFixtures(t)
+	files, _, err := dirTraversal(dir, ".txt", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []FileAnalysisResult
+	for _, f := range files {
+		res, err := analyzeFile(f, []Analyzer{MostFrequentWordsAnalyzer{}}, newContentMemo(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+
+	trend, err := computeTrend(results, []string{"deploy", "rollback", "incident"}, "month")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantPeriods := []string{"2024-01", "2024-02", "2024-03"}
+	if !reflect.DeepEqual(trend.Periods, wantPeriods) {
+		t.Errorf("expected periods %v, got %v", wantPeriods, trend.Periods)
+	}
+	wantCounts := map[string][]int{
+		"deploy":   {3, 0, 1},
+		"rollback": {1, 3, 0},
+		"incident": {1, 0, 2},
+	}
+	if !reflect.DeepEqual(trend.Counts, wantCounts) {
+		t.Errorf("expected counts %v, got %v", wantCounts, trend.Counts)
+	}
+}
+
+func TestRunTrendTop(t *testing.T) {
+	dir := writeTrendFixtures(t)
+
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 2, TrendTop: 1, TrendBucket: "month"}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Тренд (month):") {
+		t.Errorf("expected trend table in output:\n%s", out.String())
+	}
+
+	opts.TrendBucket = "year"
+	if err := run(context.Background(), opts, &out); err == nil {
+		t.Error("expected error for unknown bucket")
+	}
+}
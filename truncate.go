@@ -0,0 +1,99 @@
+package main
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Число обрезанных слов и строк файла
+type Truncations struct {
+	Tokens int `json:"tokens"`
+	Lines  int `json:"lines"`
+}
+
+// Обрезка слов длиннее maxToken байт и строк длиннее maxLine байт, 0 - без ограничения.
+// Обрезка идёт по границе руны. Если обрезать нечего, content возвращается без копирования,
+// иначе результат собирается из сохранённых кусков, и гигантское слово не копируется целиком
+func truncateLong(content string, maxToken, maxLine int) (string, Truncations) {
+	return truncateLongKeep(content, maxToken, maxLine, nil)
+}
+
+// Как truncateLong, но сообщает keep о каждом сохранённом куске content[from:to]
+// по порядку. Если обрезать нечего, keep не вызывается: сохранено всё
+func truncateLongKeep(content string, maxToken, maxLine int, keep func(from, to int)) (string, Truncations) {
+	var t Truncations
+	if maxToken <= 0 && maxLine <= 0 {
+		return content, t
+	}
+	var buf []byte
+	cut := false  // что-то уже отброшено, результат собирается в b
+	keepFrom := 0 // начало текущего сохраняемого куска
+	dropping := false
+	tokenLen, lineLen := 0, 0
+	tokenCut, lineCut := false, false
+	for i := 0; i < len(content); {
+		r, size := utf8.DecodeRuneInString(content[i:])
+		drop := false
+		switch {
+		case r == '\n':
+			tokenLen, lineLen = 0, 0
+			tokenCut, lineCut = false, false
+		case lineCut:
+			drop = true
+		case maxLine > 0 && lineLen+size > maxLine:
+			lineCut = true
+			t.Lines++
+			drop = true
+		case unicode.IsSpace(r):
+			tokenLen = 0
+			tokenCut = false
+			lineLen += size
+		case tokenCut:
+			drop = true
+		case maxToken > 0 && tokenLen+size > maxToken:
+			tokenCut = true
+			t.Tokens++
+			drop = true
+		default:
+			tokenLen += size
+			lineLen += size
+		}
+		if drop && !dropping {
+			buf = append(buf, content[keepFrom:i]...)
+			if keep != nil {
+				keep(keepFrom, i)
+			}
+			cut, dropping = true, true
+		} else if !drop && dropping {
+			keepFrom, dropping = i, false
+		}
+		i += size
+	}
+	if !cut {
+		return content, t
+	}
+	if !dropping {
+		buf = append(buf, content[keepFrom:]...)
+		if keep != nil {
+			keep(keepFrom, len(content))
+		}
+	}
+	return string(buf), t
+}
+
+// Обрезка содержимого перед анализаторами для -max-token-length и -max-line-length.
+// Если что-то обрезано, к результатам добавляется "truncated", чтобы было видно,
+// что числа файла посчитаны не по полному тексту
+func truncatingAnalyzer(analyze func(string, []Analyzer) []AnalysisResult, maxToken, maxLine int) func(string, []Analyzer) []AnalysisResult {
+	if maxToken <= 0 && maxLine <= 0 {
+		return analyze
+	}
+	return func(content string, analyzers []Analyzer) []AnalysisResult {
+		content, t := truncateLong(content, maxToken, maxLine)
+		results := analyze(content, analyzers)
+		if t.Tokens > 0 || t.Lines > 0 {
+			results = append(results, AnalysisResult{NameAnalyzer: "truncated", Data: t, Confidence: 1})
+		}
+		return results
+	}
+}
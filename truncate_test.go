@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTruncateLong(t *testing.T) {
+	tests := []struct {
+		name             string
+		content          string
+		maxToken, maxLen int
+		want             string
+		wantT            Truncations
+	}{
+		{"no limits", "aaaaaaaa bb", 0, 0, "aaaaaaaa bb", Truncations{}},
+		{"short tokens", "ab cd\nef", 4, 0, "ab cd\nef", Truncations{}},
+		{"long token", "abcdefgh xy abcdefgh", 4, 0, "abcd xy abcd", Truncations{Tokens: 2}},
+		{"rune boundary", "жжжж ok", 5, 0, "жж ok", Truncations{Tokens: 1}},
+		{"long line", "one two three\nfour\nfive six seven", 0, 8, "one two \nfour\nfive six", Truncations{Lines: 2}},
+		{"both", "aaaaaa bbbbbb cc\nx", 3, 8, "aaa bbb \nx", Truncations{Tokens: 2, Lines: 1}},
+	}
+	for _, tt := range tests {
+		got, gotT := truncateLong(tt.content, tt.maxToken, tt.maxLen)
+		if got != tt.want || gotT != tt.wantT {
+			t.Errorf("%s: expected %q %+v, got %q %+v", tt.name, tt.want, tt.wantT, got, gotT)
+		}
+	}
+}
+
+func TestTruncateLongAllocs(t *testing.T) {
+	short := strings.Repeat("two words\n", 500)
+	if n := testing.AllocsPerRun(10, func() { truncateLong(short, 64, 1024) }); n != 0 {
+		t.Errorf("expected no allocations without truncation, got %v", n)
+	}
+	// 1MB слово вместо 10MB: число аллокаций зависит от результата, а не от длины слова
+	huge := strings.Repeat("x", 1<<20) + " tail"
+	var out string
+	n := testing.AllocsPerRun(5, func() { out, _ = truncateLong(huge, 64, 0) })
+	if n > 4 {
+		t.Errorf("expected a few small allocations, got %v", n)
+	}
+	if len(out) != 69 {
+		t.Errorf("expected truncated length 69, got %d", len(out))
+	}
+}
+
+func TestRunTruncatesHugeToken(t *testing.T) {
+	dir := t.TempDir()
+	huge := strings.Repeat("z", 10<<20)
+	if err := os.WriteFile(filepath.Join(dir, "blob.txt"), []byte(huge+" end\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	opts := Options{Path: dir, Ext: ".txt", Workers: 1, Format: "json", MaxTokenLength: 100}
+	if err := run(context.Background(), opts, &out); err != nil {
+		t.Fatal(err)
+	}
+	var report Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Files) != 1 {
+		t.Fatalf("expected 1 file, got %d", len(report.Files))
+	}
+	var truncated, freq bool
+	for _, r := range report.Files[0].Results {
+		switch r.NameAnalyzer {
+		case "truncated":
+			truncated = true
+			if got := r.Data.(Truncations); got != (Truncations{Tokens: 1}) {
+				t.Errorf("expected 1 truncated token, got %+v", got)
+			}
+		case "most_frequent_words":
+			freq = true
+			for w := range r.Data.(map[string]int) {
+				if len(w) > 100 {
+					t.Errorf("word of %d bytes kept in the frequency map", len(w))
+				}
+			}
+		}
+	}
+	if !truncated || !freq {
+		t.Errorf("expected truncated and most_frequent_words results, got %v", report.Files[0].Results)
+	}
+	if report.Summary.TruncatedFiles != 1 {
+		t.Errorf("expected 1 truncated file in the summary, got %d", report.Summary.TruncatedFiles)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import "fmt"
+
+// Проверка результатов анализаторов на правдоподобие
+func ValidateResult(r FileAnalysisResult) []error {
+	var errs []error
+	for _, res := range r.Results {
+		if isLazy(res) {
+			continue
+		}
+		switch res.NameAnalyzer {
+		case "word_count":
+			if n, ok := res.Data.(int); !ok || n < 0 {
+				errs = append(errs, fmt.Errorf("%s: word_count должен быть неотрицательным, получено %v", r.FileName, res.Data))
+			}
+		case "line_count":
+			n, ok := res.Data.(int)
+			if !ok || n < 0 || (r.Size > 0 && n < 1) {
+				errs = append(errs, fmt.Errorf("%s: line_count должен быть не меньше 1 для непустого файла, получено %v", r.FileName, res.Data))
+			}
+		case "most_frequent_words":
+			freq, ok := res.Data.(map[string]int)
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: most_frequent_words имеет тип %T", r.FileName, res.Data))
+				continue
+			}
+			for word, count := range freq {
+				if count <= 0 {
+					errs = append(errs, fmt.Errorf("%s: most_frequent_words[%q] должно быть положительным, получено %d", r.FileName, word, count))
+				}
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+// Анализатор, возвращающий заданные данные под чужим именем
+type mockAnalyzer struct {
+	name string
+	data any
+}
+
+func (m mockAnalyzer) Name() string {
+	return m.name
+}
+func (m mockAnalyzer) Analyze(content string) AnalysisResult {
+	return AnalysisResult{NameAnalyzer: m.name, Data: m.data}
+}
+
+func TestValidateResult(t *testing.T) {
+	analyzers := []Analyzer{
+		mockAnalyzer{"word_count", -3},
+		mockAnalyzer{"line_count", 0},
+		mockAnalyzer{"most_frequent_words", map[string]int{"ok": 1, "bad": 0}},
+	}
+	res := FileAnalysisResult{
+		FileName: "broken.txt",
+		Size:     10,
+		Results:  analyzeContent("some content", analyzers),
+	}
+
+	errs := ValidateResult(res)
+	if len(errs) != 3 {
+		t.Errorf("expected 3 validation errors, got %d: %v", len(errs), errs)
+	}
+}
+
+func TestValidateResultValid(t *testing.T) {
+	content := "hello world\nhello"
+	res := FileAnalysisResult{
+		FileName: "ok.txt",
+		Size:     int64(len(content)),
+		Results:  analyzeContent(content, defaultAnalyzers(Options{})),
+	}
+	if errs := ValidateResult(res); len(errs) != 0 {
+		t.Errorf("expected no validation errors, got %v", errs)
+	}
+}
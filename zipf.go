@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Точек ранг-частота в отчёте: хвост распределения длинный и в JSON не нужен
+const zipfPoints = 20
+
+type ZipfPoint struct {
+	Rank      int `json:"rank"`
+	Frequency int `json:"frequency"`
+}
+
+// Проверка закона Ципфа: частота слова ранга n пропорциональна 1/n^Exponent.
+// Exponent и R2 - линейная регрессия log(частота) от log(ранга) по всем словам
+type ZipfReport struct {
+	Words    int         `json:"words"`
+	Exponent float64     `json:"exponent"`
+	R2       float64     `json:"r2"`
+	Points   []ZipfPoint `json:"points,omitempty"`
+}
+
+func ZipfAnalysis(globalMap map[string]int) ZipfReport {
+	freqs := make([]int, 0, len(globalMap))
+	for _, c := range globalMap {
+		if c > 0 {
+			freqs = append(freqs, c)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(freqs)))
+
+	report := ZipfReport{Words: len(freqs)}
+	for i := 0; i < len(freqs) && i < zipfPoints; i++ {
+		report.Points = append(report.Points, ZipfPoint{i + 1, freqs[i]})
+	}
+	if len(freqs) < 2 {
+		return report
+	}
+
+	n := float64(len(freqs))
+	var sumX, sumY float64
+	xs, ys := make([]float64, len(freqs)), make([]float64, len(freqs))
+	for i, f := range freqs {
+		xs[i], ys[i] = math.Log(float64(i+1)), math.Log(float64(f))
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	slope := sxy / sxx
+	report.Exponent = -slope
+	// все частоты равны: прямая y = const описывает их точно
+	report.R2 = 1
+	if syy > 0 {
+		report.R2 = sxy * sxy / (sxx * syy)
+	}
+	return report
+}
+
+func writeZipfText(out io.Writer, z ZipfReport) {
+	if z.Words < 2 {
+		fmt.Fprintln(out, "Закон Ципфа: недостаточно слов для оценки")
+		fmt.Fprintln(out)
+		return
+	}
+	fmt.Fprintf(out, "Закон Ципфа: показатель %.3f, R² = %.3f (%d слов)\n", z.Exponent, z.R2, z.Words)
+	for _, p := range z.Points {
+		fmt.Fprintf(out, " %3d: %d\n", p.Rank, p.Frequency)
+	}
+	fmt.Fprintln(out)
+}
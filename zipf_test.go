@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"stage5/internal/testutil"
+	"strings"
+	"testing"
+)
+
+func TestZipfAnalysisExact(t *testing.T) {
+	tests := []struct {
+		name     string
+		freq     func(rank int) int
+		ranks    int
+		exponent float64
+	}{
+		// 2520 делится на 1..10, частоты 2520/n целые
+		{"harmonic", func(r int) int { return 2520 / r }, 10, 1},
+		{"squared", func(r int) int { return 3600 / (r * r) }, 6, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			freq := make(map[string]int)
+			for r := 1; r <= tt.ranks; r++ {
+				freq[fmt.Sprintf("w%d", r)] = tt.freq(r)
+			}
+			got := ZipfAnalysis(freq)
+			if math.Abs(got.Exponent-tt.exponent) > 1e-9 || math.Abs(got.R2-1) > 1e-9 {
+				t.Errorf("expected exponent %v with R² 1, got %+v", tt.exponent, got)
+			}
+			if got.Words != tt.ranks || got.Points[0] != (ZipfPoint{1, tt.freq(1)}) || got.Points[1] != (ZipfPoint{2, tt.freq(2)}) {
+				t.Errorf("unexpected points %+v", got.Points)
+			}
+		})
+	}
+}
+
+func TestZipfAnalysisNoisy(t *testing.T) {
+	got := ZipfAnalysis(map[string]int{"a": 50, "b": 10, "c": 30, "d": 5, "e": 20})
+	if got.Exponent <= 0 || got.R2 <= 0 || got.R2 >= 1 {
+		t.Errorf("expected positive exponent with imperfect fit, got %+v", got)
+	}
+	if got := ZipfAnalysis(map[string]int{"only": 3}); got.Words != 1 || got.Exponent != 0 {
+		t.Errorf("expected no fit for one word, got %+v", got)
+	}
+}
+
+func TestRunZipf(t *testing.T) {
+	var b strings.Builder
+	for r := 1; r <= 6; r++ {
+		b.WriteString(strings.Repeat(fmt.Sprintf("w%d ", r), 60/r))
+	}
+	dir := testutil.CreateTempDir(t, map[string]string{"a.txt": b.String()})
+	var out bytes.Buffer
+	if err := run(context.Background(), Options{Path: dir, Ext: ".txt", Workers: 1, Zipf: true}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Закон Ципфа: показатель 1.000, R² = 1.000 (6 слов)") {
+		t.Errorf("expected Zipf summary, got:\n%s", out.String())
+	}
+}